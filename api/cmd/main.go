@@ -1,27 +1,87 @@
 package main
 
 import (
-	"log"
+	"context"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
 	"star-wars/api"
+	"star-wars/database"
 	"star-wars/env"
-	"time"
+	"star-wars/logger"
+	"star-wars/tracing"
+	"syscall"
+
+	"go.uber.org/zap"
 )
 
 func main() {
+	defer logger.Sync()
+
 	port := ":" + env.Vars.Api.Port
 
 	if port == ":" {
-		log.Fatal("PORT must be set")
+		logger.L().Fatal("PORT must be set")
 	}
 
-	s := &http.Server{
-		Addr:           port,
-		Handler:        api.Config(),
-		ReadTimeout:    10 * time.Second,
-		WriteTimeout:   10 * time.Second,
-		MaxHeaderBytes: 1 << 20,
+	shutdownTracing, err := tracing.Setup(context.Background())
+	if err != nil {
+		logger.L().Fatal("error at tracing setup", zap.Error(err))
+	}
+
+	s := &api.Server{
+		HTTP: &http.Server{
+			Addr:           port,
+			Handler:        api.Config(),
+			ReadTimeout:    env.Vars.Api.ReadTimeout,
+			WriteTimeout:   env.Vars.Api.WriteTimeout,
+			MaxHeaderBytes: 1 << 20,
+		},
+		Timeout: env.Vars.Api.ShutdownTimeout,
+	}
+
+	s.OnShutdown("tracing", shutdownTracing)
+	s.OnShutdown("mongodb", database.Close)
+	s.OnShutdown("redis", api.CloseRedis)
+
+	// registered after mongodb, the jobs in flight finish before it closes
+	jobs := api.Jobs()
+	jobs.Start()
+	s.OnShutdown("jobs", jobs.Shutdown)
+
+	if env.Vars.GRPC.Port != "" {
+		grpcAddr := ":" + env.Vars.GRPC.Port
+
+		lis, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			logger.L().Fatal("error at grpc listen", zap.Error(err))
+		}
+
+		g := api.GRPC()
+
+		go func() {
+			if err := g.Serve(lis); err != nil {
+				logger.L().Error("grpc server stopped", zap.Error(err))
+			}
+		}()
+
+		// closers run in reverse, the calls in flight drain before mongodb closes
+		s.OnShutdown("grpc", g.Shutdown)
+
+		logger.L().Info("listening", zap.String("addr", grpcAddr), zap.String("protocol", "grpc"))
+	}
+
+	logger.L().Info("listening", zap.String("addr", port))
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+
+	if err := s.Run(stop); err != nil {
+		logger.L().Error("error at shutdown", zap.Error(err))
+		logger.Sync()
+		os.Exit(1)
 	}
 
-	log.Panic("error at listen and serve", s.ListenAndServe())
+	logger.L().Info("server stopped")
 }
@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"star-wars/api/handler"
+	"star-wars/api/serializer"
+	"star-wars/apikey"
+	"star-wars/entity"
+	"star-wars/env"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeys controller
+type APIKeys struct {
+	Srv        apikey.Service
+	Serializer serializer.Serializer
+}
+
+// secret responses must never land in a shared cache
+func noStore(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+}
+
+// Post issue an api key, the only response carrying its secret besides Rotate
+func (a APIKeys) Post(c *gin.Context) {
+	var key entity.APIKey
+
+	if err := c.BindJSON(&key); err != nil {
+		handler.ResponseError(handler.BadRequest{Message: "body is invalid"}, c)
+		return
+	}
+
+	ctx, cancel := requestContext(c, env.Vars.Timeouts.Write)
+	defer cancel()
+
+	if err := a.Srv.Create(ctx, &key); err != nil {
+		handler.ResponseError(err, c)
+		return
+	}
+
+	noStore(c)
+	handler.ResponseSuccess(201, key, c)
+}
+
+// All list api keys without their secrets
+func (a APIKeys) All(c *gin.Context) {
+	ctx, cancel := requestContext(c, env.Vars.Timeouts.Read)
+	defer cancel()
+
+	keys, err := a.Srv.List(ctx)
+
+	if err != nil {
+		handler.ResponseError(err, c)
+		return
+	}
+
+	handler.ResponseSuccess(200, serializer.Or(a.Serializer).List(keys, nil), c)
+}
+
+// Rotate replace the secret of an api key
+func (a APIKeys) Rotate(c *gin.Context) {
+	ctx, cancel := requestContext(c, env.Vars.Timeouts.Write)
+	defer cancel()
+
+	key, err := a.Srv.Rotate(ctx, c.Param("id"))
+
+	if err != nil {
+		handler.ResponseError(err, c)
+		return
+	}
+
+	noStore(c)
+	handler.ResponseSuccess(200, key, c)
+}
+
+// Revoke disable an api key
+func (a APIKeys) Revoke(c *gin.Context) {
+	ctx, cancel := requestContext(c, env.Vars.Timeouts.Write)
+	defer cancel()
+
+	if err := a.Srv.Revoke(ctx, c.Param("id")); err != nil {
+		handler.ResponseError(err, c)
+		return
+	}
+
+	handler.ResponseSuccess(200, nil, c)
+}
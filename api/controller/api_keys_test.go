@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"star-wars/api/handler"
+	"star-wars/apikey/mock_apikey"
+	"star-wars/entity"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeysPost(t *testing.T) {
+	t.Parallel()
+
+	type test struct {
+		name           string
+		body           string
+		err            error
+		callSrv        bool
+		wantStatusCode int
+		wantBody       string
+		wantCache      string
+	}
+
+	tests := []test{
+		{
+			name:           "when the key is issued",
+			body:           `{"name":"partner","scopes":["planets:read"]}`,
+			callSrv:        true,
+			wantStatusCode: 201,
+			wantBody:       `{"id":"5f3080961f4799f091e3c515","name":"partner","prefix":"sw_01234567","scopes":["planets:read"],"key":"sw_0123456789"}`,
+			wantCache:      "no-store",
+		},
+		{
+			name:           "when body is invalid",
+			body:           `{"name":`,
+			wantStatusCode: 400,
+			wantBody:       `{"error":"body is invalid"}`,
+		},
+		{
+			name:           "when the service refuses the key",
+			body:           `{"name":"partner","scopes":["planets:delete"]}`,
+			callSrv:        true,
+			err:            handler.BadRequest{Message: "scope planets:delete is invalid"},
+			wantStatusCode: 400,
+			wantBody:       `{"error":"scope planets:delete is invalid"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/admin/api-keys", bytes.NewBufferString(tt.body))
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			srvMock := mock_apikey.NewMockService(ctrl)
+			if tt.callSrv {
+				srvMock.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, key *entity.APIKey) error {
+					if tt.err != nil {
+						return tt.err
+					}
+					key.ID = "5f3080961f4799f091e3c515"
+					key.Prefix = "sw_01234567"
+					key.Hash = "secret-hash"
+					key.Key = "sw_0123456789"
+					return nil
+				})
+			}
+
+			APIKeys{
+				Srv: srvMock,
+			}.Post(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.Equal(t, tt.wantBody, w.Body.String())
+			assert.Equal(t, tt.wantCache, w.Header().Get("Cache-Control"))
+		})
+	}
+}
+
+func TestAPIKeysRotateAndRevoke(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	srvMock := mock_apikey.NewMockService(ctrl)
+	srvMock.EXPECT().Rotate(gomock.Any(), "5f3080961f4799f091e3c515").Return(nil, handler.NotFound{Message: "api key not found"})
+	srvMock.EXPECT().Revoke(gomock.Any(), "5f3080961f4799f091e3c515").Return(nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = []gin.Param{{Key: "id", Value: "5f3080961f4799f091e3c515"}}
+	c.Request, _ = http.NewRequest("POST", "/admin/api-keys/5f3080961f4799f091e3c515/rotate", nil)
+
+	APIKeys{Srv: srvMock}.Rotate(c)
+
+	assert.Equal(t, 404, w.Code)
+	assert.Equal(t, `{"error":"api key not found"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Params = []gin.Param{{Key: "id", Value: "5f3080961f4799f091e3c515"}}
+	c.Request, _ = http.NewRequest("DELETE", "/admin/api-keys/5f3080961f4799f091e3c515", nil)
+
+	APIKeys{Srv: srvMock}.Revoke(c)
+
+	assert.Equal(t, 200, w.Code)
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"star-wars/api/handler"
+	"star-wars/api/serializer"
+	"star-wars/audit"
+	"star-wars/entity"
+	"star-wars/env"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Audit controller
+type Audit struct {
+	Srv        audit.Service
+	Serializer serializer.Serializer
+}
+
+// All get audit entries filtered by planetId, actor, action, from and to
+func (a Audit) All(c *gin.Context) {
+	filter, err := auditFilter(c)
+	if err != nil {
+		handler.ResponseError(err, c)
+		return
+	}
+
+	filter.PlanetID = c.Query("planetId")
+	filter.Actor = c.Query("actor")
+	filter.Action = c.Query("action")
+
+	a.find(filter, c)
+}
+
+// History get audit entries of a planet
+func (a Audit) History(c *gin.Context) {
+	filter, err := auditFilter(c)
+	if err != nil {
+		handler.ResponseError(err, c)
+		return
+	}
+
+	filter.PlanetID = c.Param("id")
+
+	a.find(filter, c)
+}
+
+func (a Audit) find(filter entity.AuditFilter, c *gin.Context) {
+	ctx, cancel := requestContext(c, env.Vars.Timeouts.Read)
+	defer cancel()
+
+	entries, err := a.Srv.Find(ctx, filter)
+
+	if err == nil {
+		page := &serializer.Page{Limit: filter.Limit, Skip: filter.Skip, Count: len(*entries)}
+		handler.ResponseSuccess(200, serializer.Or(a.Serializer).List(entries, page), c)
+	} else {
+		handler.ResponseError(err, c)
+	}
+}
+
+func auditFilter(c *gin.Context) (entity.AuditFilter, error) {
+	var filter entity.AuditFilter
+	var err error
+
+	filter.Limit, err = strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 64)
+	if err != nil {
+		return filter, handler.BadRequest{Message: "limit is invalid"}
+	}
+
+	filter.Skip, err = strconv.ParseInt(c.DefaultQuery("skip", "0"), 10, 64)
+	if err != nil {
+		return filter, handler.BadRequest{Message: "skip is invalid"}
+	}
+
+	if from := c.Query("from"); from != "" {
+		filter.From, err = time.Parse(time.RFC3339, from)
+		if err != nil {
+			return filter, handler.BadRequest{Message: "from is invalid"}
+		}
+	}
+
+	if to := c.Query("to"); to != "" {
+		filter.To, err = time.Parse(time.RFC3339, to)
+		if err != nil {
+			return filter, handler.BadRequest{Message: "to is invalid"}
+		}
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"star-wars/api/handler"
+	"star-wars/audit/mock_audit"
+	"star-wars/entity"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditAll(t *testing.T) {
+	t.Parallel()
+
+	type test struct {
+		name           string
+		uri            string
+		filter         *entity.AuditFilter
+		entries        *[]entity.AuditEntry
+		err            error
+		wantStatusCode int
+		wantBody       string
+	}
+
+	timestamp := time.Date(2020, 8, 7, 10, 0, 0, 0, time.UTC)
+
+	tests := []test{
+		{
+			name: "when filtering by actor, action and period",
+			uri:  "http://t.test/audit?actor=luke&action=delete&from=2020-08-01T00:00:00Z&limit=5",
+			filter: &entity.AuditFilter{
+				Actor:  "luke",
+				Action: "delete",
+				From:   time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC),
+				Limit:  5,
+			},
+			entries: &[]entity.AuditEntry{
+				{
+					ID:        "5f2c891e9a9e070b1ef2e28f",
+					PlanetID:  "5f2c891e9a9e070b1ef2e28c",
+					Action:    "delete",
+					Actor:     "luke",
+					Timestamp: timestamp,
+				},
+			},
+			wantStatusCode: 200,
+			wantBody:       `[{"id":"5f2c891e9a9e070b1ef2e28f","planetId":"5f2c891e9a9e070b1ef2e28c","action":"delete","actor":"luke","timestamp":"2020-08-07T10:00:00Z"}]`,
+		},
+		{
+			name:           "when from is invalid",
+			uri:            "http://t.test/audit?from=yesterday",
+			wantStatusCode: 400,
+			wantBody:       `{"error":"from is invalid"}`,
+		},
+		{
+			name:           "when limit is invalid",
+			uri:            "http://t.test/audit?limit=a",
+			wantStatusCode: 400,
+			wantBody:       `{"error":"limit is invalid"}`,
+		},
+		{
+			name:           "when an error happens",
+			uri:            "http://t.test/audit",
+			filter:         &entity.AuditFilter{Limit: 20},
+			err:            handler.InternalServer{Message: "error"},
+			wantStatusCode: 500,
+			wantBody:       `{"error":"internal server error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET", tt.uri, nil)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			srvMock := mock_audit.NewMockService(ctrl)
+
+			if tt.filter != nil {
+				srvMock.EXPECT().Find(gomock.Any(), *tt.filter).Return(tt.entries, tt.err)
+			}
+
+			Audit{
+				Srv: srvMock,
+			}.All(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.Equal(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestAuditHistory(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/planets/5f2c891e9a9e070b1ef2e28c/history", nil)
+	c.Params = []gin.Param{{Key: "id", Value: "5f2c891e9a9e070b1ef2e28c"}}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	srvMock := mock_audit.NewMockService(ctrl)
+	srvMock.EXPECT().Find(gomock.Any(), entity.AuditFilter{PlanetID: "5f2c891e9a9e070b1ef2e28c", Limit: 20}).Return(&[]entity.AuditEntry{}, nil)
+
+	Audit{
+		Srv: srvMock,
+	}.History(c)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `[]`, w.Body.String())
+}
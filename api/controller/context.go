@@ -0,0 +1,13 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestContext derives from the request context, keeping its trace and request ID
+func requestContext(c *gin.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(c.Request.Context(), timeout)
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"star-wars/entity"
+	"star-wars/env"
+	"star-wars/planet/mock_planet"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestContext(t *testing.T) {
+	t.Parallel()
+
+	t.Run("when the client goes away the service call is cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = []gin.Param{{Key: "id", Value: "5f29e53f2939a742014a04af"}}
+
+		reqCtx, disconnect := context.WithCancel(context.Background())
+		c.Request, _ = http.NewRequestWithContext(reqCtx, "GET", "/planets/5f29e53f2939a742014a04af", nil)
+		disconnect()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		srvMock := mock_planet.NewMockService(ctrl)
+		srvMock.EXPECT().FindByID(gomock.Any(), "5f29e53f2939a742014a04af").DoAndReturn(
+			func(ctx context.Context, id string) (*entity.Planet, error) {
+				assert.Equal(t, context.Canceled, ctx.Err())
+				return nil, ctx.Err()
+			})
+
+		Planets{
+			Srv: srvMock,
+		}.ByID(c)
+	})
+
+	t.Run("when the operation runs within the configured budget", func(t *testing.T) {
+		t.Parallel()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/planets", nil)
+
+		start := time.Now()
+		ctx, cancel := requestContext(c, env.Vars.Timeouts.Read)
+		defer cancel()
+
+		deadline, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.WithinDuration(t, start.Add(env.Vars.Timeouts.Read), deadline, time.Second)
+	})
+}
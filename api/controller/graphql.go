@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"star-wars/api/graph"
+	"star-wars/api/handler"
+	"star-wars/env"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GraphQL controller
+type GraphQL struct {
+	Executor graph.Executor
+}
+
+// Post run a query or mutation; GraphQL errors answer 200 next to the data resolved
+func (g GraphQL) Post(c *gin.Context) {
+	var req graph.Request
+
+	if err := c.BindJSON(&req); err != nil {
+		handler.ResponseError(handler.BadRequest{Message: "body is invalid"}, c)
+		return
+	}
+
+	if req.Query == "" {
+		handler.ResponseError(handler.BadRequest{Message: "query is required"}, c)
+		return
+	}
+
+	ctx, cancel := requestContext(c, env.Vars.Timeouts.Write)
+	defer cancel()
+
+	handler.ResponseSuccess(200, g.Executor.Execute(ctx, req), c)
+}
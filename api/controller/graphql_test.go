@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"star-wars/api/graph"
+	"star-wars/api/graph/mock_graph"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphQL(t *testing.T) {
+	t.Parallel()
+
+	type test struct {
+		name           string
+		body           string
+		callExecutor   bool
+		wantStatusCode int
+		wantBody       string
+	}
+
+	tests := []test{
+		{
+			name:           "runs the query",
+			body:           `{"query":"query ($n: String) { planets(search: $n) { name } }","variables":{"n":"Hoth"}}`,
+			callExecutor:   true,
+			wantStatusCode: 200,
+			wantBody:       `{"data":{"planets":[{"name":"Hoth"}]}}`,
+		},
+		{
+			name:           "when body is invalid",
+			body:           `{"query":`,
+			wantStatusCode: 400,
+			wantBody:       `{"error":"body is invalid"}`,
+		},
+		{
+			name:           "when query is missing",
+			body:           `{"variables":{}}`,
+			wantStatusCode: 400,
+			wantBody:       `{"error":"query is required"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/graphql", bytes.NewBufferString(tt.body))
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			executorMock := mock_graph.NewMockExecutor(ctrl)
+			if tt.callExecutor {
+				executorMock.EXPECT().Execute(gomock.Any(), graph.Request{
+					Query:     "query ($n: String) { planets(search: $n) { name } }",
+					Variables: map[string]interface{}{"n": "Hoth"},
+				}).Return(&graphql.Result{
+					Data: map[string]interface{}{"planets": []interface{}{map[string]interface{}{"name": "Hoth"}}},
+				})
+			}
+
+			GraphQL{
+				Executor: executorMock,
+			}.Post(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.Equal(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
@@ -1,22 +1,24 @@
 package controller
 
 import (
-	"context"
+	"net/http"
 	"star-wars/entity"
+	"star-wars/env"
+	"star-wars/health"
 	"star-wars/planet"
-	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 // HealthCheck controller
 type HealthCheck struct {
-	DB planet.Repository
+	DB        planet.Repository
+	Readiness health.Service
 }
 
 // HealthCheck returns application health
 func (h HealthCheck) HealthCheck(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := requestContext(c, env.Vars.Timeouts.Read)
 	defer cancel()
 
 	hc := entity.HealthCheck{
@@ -29,3 +31,19 @@ func (h HealthCheck) HealthCheck(c *gin.Context) {
 	status := hc.CheckDependencies()
 	c.JSON(status, hc)
 }
+
+// Livez reports the process is up, it never checks dependencies
+func (h HealthCheck) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": entity.StatusOK})
+}
+
+// Readyz reports whether the dependencies can serve traffic
+func (h HealthCheck) Readyz(c *gin.Context) {
+	ctx, cancel := requestContext(c, env.Vars.Timeouts.Read)
+	defer cancel()
+
+	hc := h.Readiness.Ready(ctx)
+
+	status := hc.Readiness()
+	c.JSON(status, hc)
+}
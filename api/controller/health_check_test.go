@@ -1,7 +1,10 @@
 package controller
 
 import (
+	"net/http"
 	"net/http/httptest"
+	"star-wars/entity"
+	"star-wars/health/mock_health"
 	"star-wars/planet/mock_planet"
 	"testing"
 
@@ -13,6 +16,7 @@ import (
 func TestHealthCheck(t *testing.T) {
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/health-check", nil)
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	dbMock := mock_planet.NewMockRepository(ctrl)
@@ -29,6 +33,7 @@ func TestHealthCheck(t *testing.T) {
 func TestHealthCheck_Error(t *testing.T) {
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/health-check", nil)
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	dbMock := mock_planet.NewMockRepository(ctrl)
@@ -41,3 +46,76 @@ func TestHealthCheck_Error(t *testing.T) {
 	assert.Equal(t, 500, w.Code)
 	assert.Equal(t, "{\"status\":\"error\",\"dependencies\":{\"mongoDb\":\"error\"}}", w.Body.String())
 }
+
+func TestLivez(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	HealthCheck{}.Livez(c)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"status":"ok"}`, w.Body.String())
+}
+
+func TestReadyz(t *testing.T) {
+	t.Parallel()
+
+	type test struct {
+		name           string
+		checks         []entity.Check
+		wantStatusCode int
+		wantBody       string
+	}
+
+	tests := []test{
+		{
+			name: "happy path",
+			checks: []entity.Check{
+				{Name: "mongodb", Status: "ok", Critical: true, Latency: "1ms"},
+				{Name: "swapi", Status: "ok", Latency: "20ms"},
+			},
+			wantStatusCode: 200,
+			wantBody:       `{"status":"ok","dependencies":{"mongoDb":"ok"},"checks":[{"name":"mongodb","status":"ok","critical":true,"latency":"1ms"},{"name":"swapi","status":"ok","critical":false,"latency":"20ms"}]}`,
+		},
+		{
+			name: "when a non-critical dependency fails",
+			checks: []entity.Check{
+				{Name: "mongodb", Status: "ok", Critical: true, Latency: "1ms"},
+				{Name: "swapi", Status: "error", Latency: "2s", Error: "timeout"},
+			},
+			wantStatusCode: 200,
+			wantBody:       `{"status":"degraded","dependencies":{"mongoDb":"ok"},"checks":[{"name":"mongodb","status":"ok","critical":true,"latency":"1ms"},{"name":"swapi","status":"error","critical":false,"latency":"2s","error":"timeout"}]}`,
+		},
+		{
+			name: "when a critical dependency fails",
+			checks: []entity.Check{
+				{Name: "mongodb", Status: "error", Critical: true, Latency: "2s", Error: "server selection timeout"},
+			},
+			wantStatusCode: 503,
+			wantBody:       `{"status":"error","dependencies":{"mongoDb":"error"},"checks":[{"name":"mongodb","status":"error","critical":true,"latency":"2s","error":"server selection timeout"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET", "/readyz", nil)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			srvMock := mock_health.NewMockService(ctrl)
+			srvMock.EXPECT().Ready(gomock.Any()).Return(entity.HealthCheck{Checks: tt.checks})
+
+			HealthCheck{
+				Readiness: srvMock,
+			}.Readyz(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.Equal(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
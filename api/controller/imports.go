@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"star-wars/api/handler"
+	"star-wars/api/serializer"
+	"star-wars/entity"
+	"star-wars/env"
+	"star-wars/importer"
+	"star-wars/job"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxUpload bytes of a multipart upload
+const maxUpload = 10 << 20
+
+// Imports controller, imports are the jobs of kind entity.JobImportPlanets
+type Imports struct {
+	Jobs       job.Service
+	Serializer serializer.Serializer
+}
+
+// Post queue the import of the planets in the file part of a multipart upload;
+// rows that are not valid planets are reported at once
+func (i Imports) Post(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUpload)
+
+	file, part, err := c.Request.FormFile("file")
+	if err == http.ErrMissingFile {
+		handler.ResponseError(handler.BadRequest{Message: "file is required"}, c)
+		return
+	}
+	if err != nil {
+		handler.ResponseError(handler.BadRequest{Message: fmt.Sprintf("upload is invalid or exceeds %d MB", maxUpload>>20)}, c)
+		return
+	}
+	defer file.Close()
+
+	format, ok := uploadFormat(part)
+	if !ok {
+		handler.ResponseError(handler.BadRequest{Message: "file must be CSV or JSON"}, c)
+		return
+	}
+
+	rows, invalid, err := importer.Read(file, format)
+	if err != nil {
+		handler.ResponseError(handler.BadRequest{Message: err.Error()}, c)
+		return
+	}
+
+	total := len(rows) + len(invalid)
+
+	if total == 0 {
+		handler.ResponseError(handler.BadRequest{Message: "file has no planets"}, c)
+		return
+	}
+
+	if max := env.Vars.Imports.MaxRows; total > max {
+		handler.ResponseError(handler.BadRequest{Message: fmt.Sprintf("file exceeds %d planets", max)}, c)
+		return
+	}
+
+	ctx, cancel := requestContext(c, env.Vars.Timeouts.Write)
+	defer cancel()
+
+	j := entity.Job{
+		Kind: entity.JobImportPlanets,
+		Import: &entity.Import{
+			Format: format,
+			Total:  total,
+			Failed: len(invalid),
+			Errors: append([]entity.ImportError{}, invalid...),
+			Rows:   rows,
+		},
+	}
+
+	if err := i.Jobs.Enqueue(ctx, &j); err != nil {
+		handler.ResponseError(err, c)
+		return
+	}
+
+	c.Header("Location", c.FullPath()+"/"+j.ID)
+	handler.ResponseSuccess(http.StatusAccepted, j, c)
+}
+
+// ByID get the progress of an import and the rows that failed so far
+func (i Imports) ByID(c *gin.Context) {
+	j, ok := i.find(c)
+	if !ok {
+		return
+	}
+
+	noStore(c)
+	handler.ResponseSuccess(200, j, c)
+}
+
+// Errors get the rows of an import that failed, a CSV download when asked for text/csv
+func (i Imports) Errors(c *gin.Context) {
+	j, ok := i.find(c)
+	if !ok {
+		return
+	}
+
+	if handler.Preferred(c) == handler.Encoder(handler.CSV{}) {
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="import-%s-errors.csv"`, j.ID))
+	}
+
+	noStore(c)
+	handler.ResponseSuccess(200, serializer.Or(i.Serializer).List(j.Import.Errors, nil), c)
+}
+
+// find the import of the id param, answering 404 for jobs of other kinds
+func (i Imports) find(c *gin.Context) (*entity.Job, bool) {
+	ctx, cancel := requestContext(c, env.Vars.Timeouts.Read)
+	defer cancel()
+
+	j, err := i.Jobs.Find(ctx, c.Param("id"))
+
+	if _, missing := err.(handler.NotFound); missing || err == nil && (j.Kind != entity.JobImportPlanets || j.Import == nil) {
+		handler.ResponseError(handler.NotFound{Message: "import not found"}, c)
+		return nil, false
+	}
+
+	if err != nil {
+		handler.ResponseError(err, c)
+		return nil, false
+	}
+
+	return j, true
+}
+
+// uploadFormat from the extension of the file, or the content type of its part
+func uploadFormat(part *multipart.FileHeader) (string, bool) {
+	media, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+
+	switch {
+	case strings.EqualFold(filepath.Ext(part.Filename), ".csv") || media == "text/csv":
+		return importer.FormatCSV, true
+	case strings.EqualFold(filepath.Ext(part.Filename), ".json") || media == "application/json":
+		return importer.FormatJSON, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,244 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http/httptest"
+	"star-wars/api/handler"
+	"star-wars/entity"
+	"star-wars/job/mock_job"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// upload a multipart body with file in its file part, none when filename is empty
+func upload(t *testing.T, filename, file string) (*bytes.Buffer, string) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+
+	if filename != "" {
+		part, err := w.CreateFormFile("file", filename)
+		assert.NoError(t, err)
+		_, err = part.Write([]byte(file))
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, w.Close())
+	return &body, w.FormDataContentType()
+}
+
+func TestPostImport(t *testing.T) {
+	t.Parallel()
+
+	type test struct {
+		name           string
+		filename       string
+		file           string
+		err            error
+		wantStatusCode int
+		wantLocation   string
+		wantBody       string
+	}
+
+	tests := []test{
+		{
+			name:           "happy path",
+			filename:       "planets.csv",
+			file:           "name;climate;terrain\nKamino;temperate;ocean\nHoth;frozen\n",
+			wantStatusCode: 202,
+			wantLocation:   "/v2/imports/5f3a1c2b9a9e070b1ef2e29a",
+			wantBody:       `{"id":"5f3a1c2b9a9e070b1ef2e29a","kind":"planets.import","status":"pending","attempts":0,"import":{"format":"csv","total":2,"imported":0,"failed":1,"errors":[{"row":2,"name":"Hoth","status":400,"error":"name, climate and terrain is required"}]},"createdAt":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:           "when the job cannot be queued",
+			filename:       "planets.json",
+			file:           `[{"name":"Kamino","climate":["temperate"],"terrain":["ocean"]}]`,
+			err:            handler.InternalServer{Message: "connection refused"},
+			wantStatusCode: 500,
+			wantBody:       `{"error":"internal server error"}`,
+		},
+		{
+			name:           "when the file is missing",
+			wantStatusCode: 400,
+			wantBody:       `{"error":"file is required"}`,
+		},
+		{
+			name:           "when the file is neither CSV nor JSON",
+			filename:       "planets.xlsx",
+			file:           "name;climate;terrain\n",
+			wantStatusCode: 400,
+			wantBody:       `{"error":"file must be CSV or JSON"}`,
+		},
+		{
+			name:           "when the header is another",
+			filename:       "planets.csv",
+			file:           "name,climate,terrain\nKamino,temperate,ocean\n",
+			wantStatusCode: 400,
+			wantBody:       `{"error":"header must be name;climate;terrain"}`,
+		},
+		{
+			name:           "when the file has no planets",
+			filename:       "planets.csv",
+			file:           "name;climate;terrain\n",
+			wantStatusCode: 400,
+			wantBody:       `{"error":"file has no planets"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			jobsMock := mock_job.NewMockService(ctrl)
+
+			if tt.wantStatusCode != 400 {
+				jobsMock.EXPECT().Enqueue(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, j *entity.Job) error {
+					assert.Equal(t, entity.JobImportPlanets, j.Kind)
+					j.ID = "5f3a1c2b9a9e070b1ef2e29a"
+					j.Status = entity.JobPending
+					return tt.err
+				})
+			}
+
+			router := gin.New()
+			router.POST("/v2/imports", Imports{Jobs: jobsMock}.Post)
+
+			body, contentType := upload(t, tt.filename, tt.file)
+			req := httptest.NewRequest("POST", "/v2/imports", body)
+			req.Header.Set("Content-Type", contentType)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.Equal(t, tt.wantLocation, w.Header().Get("Location"))
+			assert.Equal(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestImportByID(t *testing.T) {
+	t.Parallel()
+
+	type test struct {
+		name           string
+		job            *entity.Job
+		err            error
+		wantStatusCode int
+		wantBody       string
+	}
+
+	tests := []test{
+		{
+			name: "happy path",
+			job: &entity.Job{
+				ID:     "5f3a1c2b9a9e070b1ef2e29a",
+				Kind:   entity.JobImportPlanets,
+				Status: entity.JobRunning,
+				Import: &entity.Import{Format: "csv", Total: 2, Imported: 1},
+			},
+			wantStatusCode: 200,
+			wantBody:       `{"id":"5f3a1c2b9a9e070b1ef2e29a","kind":"planets.import","status":"running","attempts":0,"import":{"format":"csv","total":2,"imported":1,"failed":0,"errors":null},"createdAt":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:           "when the job is of another kind",
+			job:            &entity.Job{ID: "5f3a1c2b9a9e070b1ef2e29a", Kind: entity.JobCreatePlanet},
+			wantStatusCode: 404,
+			wantBody:       `{"error":"import not found"}`,
+		},
+		{
+			name:           "when the job does not exist",
+			err:            handler.NotFound{Message: "job not found"},
+			wantStatusCode: 404,
+			wantBody:       `{"error":"import not found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			jobsMock := mock_job.NewMockService(ctrl)
+			jobsMock.EXPECT().Find(gomock.Any(), "5f3a1c2b9a9e070b1ef2e29a").Return(tt.job, tt.err)
+
+			router := gin.New()
+			router.GET("/imports/:id", Imports{Jobs: jobsMock}.ByID)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/imports/5f3a1c2b9a9e070b1ef2e29a", nil))
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.Equal(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	t.Parallel()
+
+	j := &entity.Job{
+		ID:   "5f3a1c2b9a9e070b1ef2e29a",
+		Kind: entity.JobImportPlanets,
+		Import: &entity.Import{Errors: []entity.ImportError{
+			{Row: 2, Name: "Hoth", Status: 400, Error: "non-existent planet"},
+		}},
+	}
+
+	type test struct {
+		name            string
+		accept          string
+		wantDisposition string
+		wantBody        string
+	}
+
+	tests := []test{
+		{
+			name:     "json",
+			wantBody: `[{"row":2,"name":"Hoth","status":400,"error":"non-existent planet"}]`,
+		},
+		{
+			name:            "csv download",
+			accept:          "text/csv",
+			wantDisposition: `attachment; filename="import-5f3a1c2b9a9e070b1ef2e29a-errors.csv"`,
+			wantBody:        "row;name;status;error\n2;Hoth;400;non-existent planet\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			jobsMock := mock_job.NewMockService(ctrl)
+			jobsMock.EXPECT().Find(gomock.Any(), "5f3a1c2b9a9e070b1ef2e29a").Return(j, nil)
+
+			router := gin.New()
+			router.GET("/imports/:id/errors", Imports{Jobs: jobsMock}.Errors)
+
+			req := httptest.NewRequest("GET", "/imports/5f3a1c2b9a9e070b1ef2e29a/errors", nil)
+			req.Header.Set("Accept", tt.accept)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, 200, w.Code)
+			assert.Equal(t, tt.wantDisposition, w.Header().Get("Content-Disposition"))
+			assert.Equal(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
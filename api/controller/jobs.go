@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"star-wars/api/handler"
+	"star-wars/env"
+	"star-wars/job"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Jobs controller
+type Jobs struct {
+	Srv job.Service
+}
+
+// ByID get the status and outcome of a job; never cached, it is polled
+func (j Jobs) ByID(c *gin.Context) {
+	ctx, cancel := requestContext(c, env.Vars.Timeouts.Read)
+	defer cancel()
+
+	found, err := j.Srv.Find(ctx, c.Param("id"))
+
+	if err != nil {
+		handler.ResponseError(err, c)
+		return
+	}
+
+	noStore(c)
+	handler.ResponseSuccess(200, found, c)
+}
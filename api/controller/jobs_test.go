@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"net/http/httptest"
+	"star-wars/api/handler"
+	"star-wars/entity"
+	"star-wars/job/mock_job"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobByID(t *testing.T) {
+	t.Parallel()
+
+	type test struct {
+		name           string
+		job            *entity.Job
+		err            error
+		wantStatusCode int
+		wantBody       string
+	}
+
+	tests := []test{
+		{
+			name: "happy path",
+			job: &entity.Job{
+				ID:       "5f3a1c2b9a9e070b1ef2e29a",
+				Kind:     entity.JobCreatePlanet,
+				Status:   entity.JobFailed,
+				Actor:    "luke",
+				Attempts: 1,
+				Error:    &entity.JobError{Status: 400, Message: "planet already registered"},
+			},
+			wantStatusCode: 200,
+			wantBody:       `{"id":"5f3a1c2b9a9e070b1ef2e29a","kind":"planet.create","status":"failed","attempts":1,"error":{"status":400,"message":"planet already registered"},"createdAt":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:           "when the job does not exist",
+			err:            handler.NotFound{Message: "job not found"},
+			wantStatusCode: 404,
+			wantBody:       `{"error":"job not found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			srvMock := mock_job.NewMockService(ctrl)
+			srvMock.EXPECT().Find(gomock.Any(), "5f3a1c2b9a9e070b1ef2e29a").Return(tt.job, tt.err)
+
+			router := gin.New()
+			router.GET("/jobs/:id", Jobs{Srv: srvMock}.ByID)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/jobs/5f3a1c2b9a9e070b1ef2e29a", nil))
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.Equal(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
@@ -1,19 +1,28 @@
 package controller
 
 import (
-	"context"
+	"fmt"
+	"net/http"
 	"star-wars/api/handler"
+	"star-wars/api/serializer"
 	"star-wars/entity"
+	"star-wars/env"
+	"star-wars/job"
+	"star-wars/logger"
 	"star-wars/planet"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
 // Planets controller
 type Planets struct {
-	Srv planet.Service
+	Srv        planet.Service
+	Jobs       job.Service
+	Serializer serializer.Serializer
 }
 
 // All get planets
@@ -42,7 +51,7 @@ func (p Planets) All(c *gin.Context) {
 
 	search := c.DefaultQuery("search", "")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := requestContext(c, env.Vars.Timeouts.Read)
 	defer cancel()
 
 	var planets *[]entity.Planet
@@ -67,30 +76,60 @@ func (p Planets) All(c *gin.Context) {
 		return
 	}
 
-	handler.ResponseSuccess(200, planets, c)
+	body := serializer.Or(p.Serializer).List(planets, &serializer.Page{Limit: limit, Skip: skip, Count: len(*planets)})
+
+	// no Last-Modified: removing a planet changes the page without changing
+	// the dates of the ones left, only the ETag notices it
+	cache := handler.Cache{
+		ETag:    handler.HashETag(body, handler.Preferred(c).ContentType()),
+		Control: env.Vars.Cache.Planets,
+	}
+
+	if handler.NotModified(cache, c) {
+		return
+	}
+
+	handler.ResponseSuccess(200, body, c)
 }
 
 // ByID get planet
 func (p Planets) ByID(c *gin.Context) {
 	id := c.Param("id")
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := requestContext(c, env.Vars.Timeouts.Read)
 	defer cancel()
 	planet, err := p.Srv.FindByID(ctx, id)
 
-	if err == nil {
-		handler.ResponseSuccess(200, &planet, c)
-	} else {
+	if err != nil {
 		handler.ResponseError(err, c)
+		return
+	}
+
+	cache := handler.Cache{
+		ETag:         handler.ETag(planet.Version, handler.Preferred(c).ContentType()),
+		LastModified: planet.LastModified(),
+		Control:      env.Vars.Cache.Planet,
 	}
+
+	if handler.NotModified(cache, c) {
+		return
+	}
+
+	handler.ResponseSuccess(200, &planet, c)
 }
 
 // Delete planet
 func (p Planets) Delete(c *gin.Context) {
 	id := c.Param("id")
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	version, err := handler.IfMatch(c)
+	if err != nil {
+		handler.ResponseError(err, c)
+		return
+	}
+
+	ctx, cancel := requestContext(c, env.Vars.Timeouts.Write)
 	defer cancel()
 
-	err := p.Srv.Delete(ctx, id)
+	err = p.Srv.Delete(ctx, id, version)
 
 	if err == nil {
 		handler.ResponseSuccess(200, nil, c)
@@ -99,6 +138,82 @@ func (p Planets) Delete(c *gin.Context) {
 	}
 }
 
+// Restore planet
+func (p Planets) Restore(c *gin.Context) {
+	id := c.Param("id")
+	version, err := handler.IfMatch(c)
+	if err != nil {
+		handler.ResponseError(err, c)
+		return
+	}
+
+	ctx, cancel := requestContext(c, env.Vars.Timeouts.Write)
+	defer cancel()
+
+	planet, err := p.Srv.Restore(ctx, id, version)
+
+	if err == nil {
+		c.Header("ETag", handler.ETag(planet.Version, handler.Preferred(c).ContentType()))
+		handler.ResponseSuccess(200, planet, c)
+	} else {
+		handler.ResponseError(err, c)
+	}
+}
+
+// Purge removes soft-deleted planets older than the retention window
+func (p Planets) Purge(c *gin.Context) {
+	retention := env.Vars.Planets.Retention
+
+	if value, ok := c.GetQuery("retention"); ok {
+		var err error
+		retention, err = time.ParseDuration(value)
+
+		if err != nil {
+			handler.ResponseError(
+				handler.BadRequest{
+					Message: "retention is invalid",
+				},
+				c,
+			)
+			return
+		}
+	}
+
+	ctx, cancel := requestContext(c, env.Vars.Timeouts.Purge)
+	defer cancel()
+
+	total, err := p.Srv.Purge(ctx, retention)
+
+	if err == nil {
+		handler.ResponseSuccess(200, gin.H{"purged": total}, c)
+	} else {
+		handler.ResponseError(err, c)
+	}
+}
+
+// Climates lists the climates in use
+func (p Planets) Climates(c *gin.Context) {
+	p.distinct("climate", c)
+}
+
+// Terrains lists the terrains in use
+func (p Planets) Terrains(c *gin.Context) {
+	p.distinct("terrain", c)
+}
+
+func (p Planets) distinct(field string, c *gin.Context) {
+	ctx, cancel := requestContext(c, env.Vars.Timeouts.Read)
+	defer cancel()
+
+	values, err := p.Srv.Distinct(ctx, field)
+
+	if err == nil {
+		handler.ResponseSuccess(200, serializer.Or(p.Serializer).List(values, nil), c)
+	} else {
+		handler.ResponseError(err, c)
+	}
+}
+
 // Post save planet
 func (p Planets) Post(c *gin.Context) {
 	var planet entity.Planet
@@ -114,17 +229,28 @@ func (p Planets) Post(c *gin.Context) {
 		return
 	}
 
-	if planet.IsEmpty([]string{"Name", "Climate", "Terrain"}) {
+	if err := planet.Validate(); err != nil {
 		handler.ResponseError(
 			handler.BadRequest{
-				Message: "name, climate and terrain is required",
+				Message: err.Error(),
 			},
 			c,
 		)
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	async, err := strconv.ParseBool(c.DefaultQuery("async", "false"))
+	if err != nil {
+		handler.ResponseError(handler.BadRequest{Message: "async is invalid"}, c)
+		return
+	}
+
+	if async {
+		p.enqueue(&planet, c)
+		return
+	}
+
+	ctx, cancel := requestContext(c, env.Vars.Timeouts.Write)
 	defer cancel()
 
 	err = p.Srv.Save(ctx, &planet)
@@ -134,5 +260,195 @@ func (p Planets) Post(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", handler.ETag(planet.Version, handler.Preferred(c).ContentType()))
 	handler.ResponseSuccess(201, planet, c)
 }
+
+// enqueue the creation of planet, answering where its job is followed
+func (p Planets) enqueue(planet *entity.Planet, c *gin.Context) {
+	ctx, cancel := requestContext(c, env.Vars.Timeouts.Write)
+	defer cancel()
+
+	j := entity.Job{Kind: entity.JobCreatePlanet, Planet: planet}
+
+	if err := p.Jobs.Enqueue(ctx, &j); err != nil {
+		handler.ResponseError(err, c)
+		return
+	}
+
+	c.Header("Location", strings.TrimSuffix(c.FullPath(), "/planets")+"/jobs/"+j.ID)
+	handler.ResponseSuccess(http.StatusAccepted, j, c)
+}
+
+// Put update planet
+func (p Planets) Put(c *gin.Context) {
+	version, err := handler.IfMatch(c)
+	if err != nil {
+		handler.ResponseError(err, c)
+		return
+	}
+
+	var planet entity.Planet
+	err = c.BindJSON(&planet)
+
+	if err != nil {
+		handler.ResponseError(
+			handler.BadRequest{
+				Message: "body is invalid",
+			},
+			c,
+		)
+		return
+	}
+
+	planet.ID = c.Param("id")
+
+	ctx, cancel := requestContext(c, env.Vars.Timeouts.Write)
+	defer cancel()
+
+	err = p.Srv.Update(ctx, &planet, version)
+
+	if err != nil {
+		handler.ResponseError(err, c)
+		return
+	}
+
+	c.Header("ETag", handler.ETag(planet.Version, handler.Preferred(c).ContentType()))
+	handler.ResponseSuccess(200, planet, c)
+}
+
+// deletion item of a batch delete, version works as If-Match
+type deletion struct {
+	ID      string `json:"id"`
+	Version *int64 `json:"version"`
+}
+
+// PostBatch creates the planets of the body and answers 207 with the outcome
+// of each one; with atomic=true either all of them are created or none
+func (p Planets) PostBatch(c *gin.Context) {
+	var planets []entity.Planet
+	err := c.BindJSON(&planets)
+
+	if err != nil {
+		handler.ResponseError(
+			handler.BadRequest{
+				Message: "body is invalid",
+			},
+			c,
+		)
+		return
+	}
+
+	atomic, err := batch(len(planets), c)
+	if err != nil {
+		handler.ResponseError(err, c)
+		return
+	}
+
+	ctx, cancel := requestContext(c, env.Vars.Timeouts.Write)
+	defer cancel()
+
+	errs, err := p.Srv.SaveMany(ctx, planets, atomic)
+
+	if err != nil {
+		handler.ResponseError(err, c)
+		return
+	}
+
+	results := make([]entity.BatchResult, len(planets))
+
+	for i := range planets {
+		results[i] = outcome(i, http.StatusCreated, errs[i], c)
+
+		if errs[i] == nil {
+			results[i].ID = planets[i].ID
+			results[i].Planet = &planets[i]
+		}
+	}
+
+	handler.ResponseSuccess(http.StatusMultiStatus, serializer.Or(p.Serializer).List(results, nil), c)
+}
+
+// DeleteBatch deletes the planets of the body and answers 207 with the outcome
+// of each one; with atomic=true either all of them are deleted or none
+func (p Planets) DeleteBatch(c *gin.Context) {
+	var deletions []deletion
+	err := c.BindJSON(&deletions)
+
+	if err != nil {
+		handler.ResponseError(
+			handler.BadRequest{
+				Message: "body is invalid",
+			},
+			c,
+		)
+		return
+	}
+
+	atomic, err := batch(len(deletions), c)
+	if err != nil {
+		handler.ResponseError(err, c)
+		return
+	}
+
+	refs := make([]entity.PlanetRef, len(deletions))
+
+	for i, d := range deletions {
+		refs[i] = entity.PlanetRef{ID: d.ID, Version: handler.AnyVersion}
+		if d.Version != nil {
+			refs[i].Version = *d.Version
+		}
+	}
+
+	ctx, cancel := requestContext(c, env.Vars.Timeouts.Write)
+	defer cancel()
+
+	errs, err := p.Srv.DeleteMany(ctx, refs, atomic)
+
+	if err != nil {
+		handler.ResponseError(err, c)
+		return
+	}
+
+	results := make([]entity.BatchResult, len(refs))
+
+	for i, ref := range refs {
+		results[i] = outcome(i, http.StatusOK, errs[i], c)
+		results[i].ID = ref.ID
+	}
+
+	handler.ResponseSuccess(http.StatusMultiStatus, serializer.Or(p.Serializer).List(results, nil), c)
+}
+
+// batch checks the size of a batch and whether it was asked to be atomic
+func batch(size int, c *gin.Context) (bool, error) {
+	if size == 0 {
+		return false, handler.BadRequest{Message: "batch is empty"}
+	}
+
+	if max := env.Vars.Planets.BatchSize; size > max {
+		return false, handler.BadRequest{Message: fmt.Sprintf("batch exceeds %d items", max)}
+	}
+
+	atomic, err := strconv.ParseBool(c.DefaultQuery("atomic", "false"))
+	if err != nil {
+		return false, handler.BadRequest{Message: "atomic is invalid"}
+	}
+
+	return atomic, nil
+}
+
+// outcome of item i of a batch, status when it succeeded
+func outcome(i int, status int, err error, c *gin.Context) entity.BatchResult {
+	if err == nil {
+		return entity.BatchResult{Index: i, Status: status}
+	}
+
+	result := entity.BatchResult{Index: i, Status: handler.Status(err), Error: err.Error()}
+
+	if result.Status == http.StatusInternalServerError {
+		logger.FromContext(c.Request.Context()).Error(result.Error, zap.Int("index", i), zap.Error(handler.Cause(err)))
+	}
+
+	return result
+}
@@ -2,12 +2,19 @@ package controller
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"star-wars/api/handler"
+	"star-wars/api/serializer"
 	"star-wars/entity"
+	"star-wars/env"
+	"star-wars/job/mock_job"
 	"star-wars/planet/mock_planet"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
@@ -25,10 +32,19 @@ func TestByName(t *testing.T) {
 		planets        *[]entity.Planet
 		errPlanet      error
 		errPlanets     error
+		ifNoneMatch    string
+		serializer     serializer.Serializer
 		wantStatusCode int
 		wantBody       string
 	}
 
+	cached := &[]entity.Planet{
+		{
+			ID:   "5f2c891e9a9e070b1ef2e28c",
+			Name: "Alderaan",
+		},
+	}
+
 	tests := []test{
 		{
 			name: "when get planet with a limit parameter",
@@ -37,13 +53,28 @@ func TestByName(t *testing.T) {
 				{
 					ID:         "5f2c891e9a9e070b1ef2e28c",
 					Name:       "Alderaan",
-					Climate:    "temperate",
-					Terrain:    "grasslands, mountains",
+					Climate:    entity.Terms{"temperate"},
+					Terrain:    entity.Terms{"grasslands", "mountains"},
 					TotalFilms: 2,
 				},
 			},
 			wantStatusCode: 200,
-			wantBody:       `[{"id":"5f2c891e9a9e070b1ef2e28c","name":"Alderaan","climate":"temperate","terrain":"grasslands, mountains","totalFilms":2}]`,
+			wantBody:       `[{"id":"5f2c891e9a9e070b1ef2e28c","name":"Alderaan","climate":["temperate"],"terrain":["grasslands","mountains"],"totalFilms":2}]`,
+		},
+		{
+			name:           "when get planets from v2",
+			uri:            "http://t.test/?limit=1",
+			planets:        cached,
+			serializer:     serializer.V2(),
+			wantStatusCode: 200,
+			wantBody:       `{"data":[{"id":"5f2c891e9a9e070b1ef2e28c","name":"Alderaan","climate":null,"terrain":null,"totalFilms":0}],"page":{"limit":1,"skip":0,"count":1}}`,
+		},
+		{
+			name:           "when the client copy is still fresh",
+			uri:            "http://t.test/?limit=1",
+			planets:        cached,
+			ifNoneMatch:    handler.HashETag(cached, handler.JSON{}.ContentType()),
+			wantStatusCode: 304,
 		},
 		{
 			name:           "when get planet with an invalid limit parameter",
@@ -64,12 +95,12 @@ func TestByName(t *testing.T) {
 			planet: &entity.Planet{
 				ID:         "5f2c891e9a9e070b1ef2e28c",
 				Name:       "Alderaan",
-				Climate:    "temperate",
-				Terrain:    "grasslands, mountains",
+				Climate:    entity.Terms{"temperate"},
+				Terrain:    entity.Terms{"grasslands", "mountains"},
 				TotalFilms: 2,
 			},
 			wantStatusCode: 200,
-			wantBody:       `[{"id":"5f2c891e9a9e070b1ef2e28c","name":"Alderaan","climate":"temperate","terrain":"grasslands, mountains","totalFilms":2}]`,
+			wantBody:       `[{"id":"5f2c891e9a9e070b1ef2e28c","name":"Alderaan","climate":["temperate"],"terrain":["grasslands","mountains"],"totalFilms":2}]`,
 		},
 		{
 			name:           "when get non-existent planet",
@@ -97,6 +128,9 @@ func TestByName(t *testing.T) {
 			w := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(w)
 			c.Request, _ = http.NewRequest("GET", tt.uri, nil)
+			if tt.ifNoneMatch != "" {
+				c.Request.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			srvMock := mock_planet.NewMockService(ctrl)
@@ -115,11 +149,13 @@ func TestByName(t *testing.T) {
 			}
 
 			Planets{
-				Srv: srvMock,
+				Srv:        srvMock,
+				Serializer: tt.serializer,
 			}.All(c)
 
 			assert.Equal(t, tt.wantStatusCode, w.Code)
 			assert.Equal(t, tt.wantBody, w.Body.String())
+			assert.Empty(t, w.Header().Get("Last-Modified"))
 		})
 	}
 }
@@ -132,10 +168,17 @@ func TestByID(t *testing.T) {
 		idParam        string
 		planet         *entity.Planet
 		errPlanet      error
+		accept         string
+		ifNoneMatch    string
+		ifModified     string
 		wantStatusCode int
 		wantBody       string
+		wantETag       string
+		wantModified   string
 	}
 
+	updatedAt := time.Date(2020, 8, 7, 10, 0, 0, 0, time.UTC)
+
 	tests := []test{
 		{
 			name:    "happy path",
@@ -143,12 +186,74 @@ func TestByID(t *testing.T) {
 			planet: &entity.Planet{
 				ID:         "5f29e53f2939a742014a04af",
 				Name:       "Tatooine",
-				Climate:    "arid",
-				Terrain:    "desert",
+				Climate:    entity.Terms{"arid"},
+				Terrain:    entity.Terms{"desert"},
+				TotalFilms: 5,
+				Version:    2,
+			},
+			wantStatusCode: 200,
+			wantBody:       `{"id":"5f29e53f2939a742014a04af","name":"Tatooine","climate":["arid"],"terrain":["desert"],"totalFilms":5,"version":2}`,
+			wantETag:       `"2"`,
+		},
+		{
+			name:    "when the client copy matches the ETag",
+			idParam: "5f29e53f2939a742014a04af",
+			planet: &entity.Planet{
+				ID:        "5f29e53f2939a742014a04af",
+				Name:      "Tatooine",
+				UpdatedAt: &updatedAt,
+				Version:   2,
+			},
+			ifNoneMatch:    `W/"1", W/"2"`,
+			wantStatusCode: 304,
+			wantETag:       `"2"`,
+			wantModified:   "Fri, 07 Aug 2020 10:00:00 GMT",
+		},
+		{
+			name:    "when the client copy is older than the planet",
+			idParam: "5f29e53f2939a742014a04af",
+			planet: &entity.Planet{
+				ID:        "5f29e53f2939a742014a04af",
+				Name:      "Tatooine",
+				UpdatedAt: &updatedAt,
+				Version:   2,
+			},
+			ifModified:     "Fri, 07 Aug 2020 09:00:00 GMT",
+			wantStatusCode: 200,
+			wantBody:       `{"id":"5f29e53f2939a742014a04af","name":"Tatooine","climate":null,"terrain":null,"totalFilms":0,"updatedAt":"2020-08-07T10:00:00Z","version":2}`,
+			wantETag:       `"2"`,
+			wantModified:   "Fri, 07 Aug 2020 10:00:00 GMT",
+		},
+		{
+			name:    "when the planet was not modified since the client copy",
+			idParam: "5f29e53f2939a742014a04af",
+			planet: &entity.Planet{
+				ID:        "5f29e53f2939a742014a04af",
+				Name:      "Tatooine",
+				UpdatedAt: &updatedAt,
+				Version:   2,
+			},
+			ifModified:     "Fri, 07 Aug 2020 10:00:00 GMT",
+			wantStatusCode: 304,
+			wantETag:       `"2"`,
+			wantModified:   "Fri, 07 Aug 2020 10:00:00 GMT",
+		},
+		{
+			name:    "when the client asks for another representation",
+			idParam: "5f29e53f2939a742014a04af",
+			planet: &entity.Planet{
+				ID:         "5f29e53f2939a742014a04af",
+				Name:       "Tatooine",
+				Climate:    entity.Terms{"arid"},
+				Terrain:    entity.Terms{"desert"},
 				TotalFilms: 5,
+				Version:    2,
 			},
+			accept:         "text/csv",
+			ifNoneMatch:    `"2"`,
 			wantStatusCode: 200,
-			wantBody:       `{"id":"5f29e53f2939a742014a04af","name":"Tatooine","climate":"arid","terrain":"desert","totalFilms":5}`,
+			wantBody:       "name;climate;terrain\nTatooine;arid;desert\n",
+			wantETag:       `"2-csv"`,
 		},
 		{
 			name:           "error",
@@ -168,6 +273,16 @@ func TestByID(t *testing.T) {
 			w := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(w)
 			c.Params = []gin.Param{{Key: "id", Value: tt.idParam}}
+			c.Request, _ = http.NewRequest("GET", "/planets/"+tt.idParam, nil)
+			if tt.accept != "" {
+				c.Request.Header.Set("Accept", tt.accept)
+			}
+			if tt.ifNoneMatch != "" {
+				c.Request.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			if tt.ifModified != "" {
+				c.Request.Header.Set("If-Modified-Since", tt.ifModified)
+			}
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			srvMock := mock_planet.NewMockService(ctrl)
@@ -179,6 +294,8 @@ func TestByID(t *testing.T) {
 
 			assert.Equal(t, tt.wantStatusCode, w.Code)
 			assert.Equal(t, tt.wantBody, w.Body.String())
+			assert.Equal(t, tt.wantETag, w.Header().Get("ETag"))
+			assert.Equal(t, tt.wantModified, w.Header().Get("Last-Modified"))
 		})
 	}
 }
@@ -189,6 +306,8 @@ func TestDelete(t *testing.T) {
 	type test struct {
 		name           string
 		idParam        string
+		ifMatch        string
+		version        int64
 		planet         *entity.Planet
 		err            error
 		wantStatusCode int
@@ -196,6 +315,23 @@ func TestDelete(t *testing.T) {
 	}
 
 	tests := []test{
+		{
+			name:           "when If-Match matches",
+			idParam:        "5f29e53f2939a742014a04af",
+			ifMatch:        `"3"`,
+			version:        3,
+			wantStatusCode: 200,
+			wantBody:       ``,
+		},
+		{
+			name:           "when version is stale",
+			idParam:        "5f29e53f2939a742014a04af",
+			ifMatch:        `"2"`,
+			version:        2,
+			err:            handler.PreconditionFailed{Message: "planet was modified by another request"},
+			wantStatusCode: 412,
+			wantBody:       `{"error":"planet was modified by another request"}`,
+		},
 		{
 			name:           "happy path",
 			idParam:        "5f29e53f2939a742014a04af",
@@ -220,10 +356,16 @@ func TestDelete(t *testing.T) {
 			w := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(w)
 			c.Params = []gin.Param{{Key: "id", Value: tt.idParam}}
+			c.Request, _ = http.NewRequest("DELETE", "/planets/"+tt.idParam, nil)
+			version := handler.AnyVersion
+			if tt.ifMatch != "" {
+				c.Request.Header.Set("If-Match", tt.ifMatch)
+				version = tt.version
+			}
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			srvMock := mock_planet.NewMockService(ctrl)
-			srvMock.EXPECT().Delete(gomock.Any(), tt.idParam).Return(tt.err)
+			srvMock.EXPECT().Delete(gomock.Any(), tt.idParam, version).Return(tt.err)
 
 			Planets{
 				Srv: srvMock,
@@ -253,8 +395,18 @@ func TestPost(t *testing.T) {
 			body: `{"name":"Kamino","climate":"temperate","terrain":"ocean"}`,
 			planet: &entity.Planet{
 				Name:    "Kamino",
-				Climate: "temperate",
-				Terrain: "ocean",
+				Climate: entity.Terms{"temperate"},
+				Terrain: entity.Terms{"ocean"},
+			},
+			wantStatusCode: 201,
+		},
+		{
+			name: "when climate and terrain are arrays",
+			body: `{"name":"Kamino","climate":["Temperate"],"terrain":[" ocean"]}`,
+			planet: &entity.Planet{
+				Name:    "Kamino",
+				Climate: entity.Terms{"temperate"},
+				Terrain: entity.Terms{"ocean"},
 			},
 			wantStatusCode: 201,
 		},
@@ -270,13 +422,19 @@ func TestPost(t *testing.T) {
 			wantStatusCode: 400,
 			wantBody:       `{"error":"name, climate and terrain is required"}`,
 		},
+		{
+			name:           "when unknown terrain",
+			body:           `{"name":"Kamino","climate":"temperate","terrain":"sand"}`,
+			wantStatusCode: 400,
+			wantBody:       `{"error":"terrain has unknown value: \"sand\""}`,
+		},
 		{
 			name: "when internal error",
 			body: `{"name":"Kamino","climate":"temperate","terrain":"ocean"}`,
 			planet: &entity.Planet{
 				Name:    "Kamino",
-				Climate: "temperate",
-				Terrain: "ocean",
+				Climate: entity.Terms{"temperate"},
+				Terrain: entity.Terms{"ocean"},
 			},
 			err:            handler.BadRequest{Message: "planet already registered"},
 			wantStatusCode: 400,
@@ -310,3 +468,491 @@ func TestPost(t *testing.T) {
 		})
 	}
 }
+
+func TestPostAsync(t *testing.T) {
+	t.Parallel()
+
+	type test struct {
+		name           string
+		target         string
+		err            error
+		wantStatusCode int
+		wantLocation   string
+		wantBody       string
+	}
+
+	tests := []test{
+		{
+			name:           "happy path",
+			target:         "/v2/planets?async=true",
+			wantStatusCode: 202,
+			wantLocation:   "/v2/jobs/5f3a1c2b9a9e070b1ef2e29a",
+			wantBody:       `{"id":"5f3a1c2b9a9e070b1ef2e29a","kind":"planet.create","status":"pending","attempts":0,"planet":{"id":"","name":"Kamino","climate":["temperate"],"terrain":["ocean"],"totalFilms":0},"createdAt":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:           "when the job cannot be queued",
+			target:         "/v2/planets?async=true",
+			err:            handler.InternalServer{Message: "connection refused"},
+			wantStatusCode: 500,
+			wantBody:       `{"error":"internal server error"}`,
+		},
+		{
+			name:           "when async is invalid",
+			target:         "/v2/planets?async=maybe",
+			wantStatusCode: 400,
+			wantBody:       `{"error":"async is invalid"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			jobsMock := mock_job.NewMockService(ctrl)
+
+			if tt.wantStatusCode != 400 {
+				jobsMock.EXPECT().Enqueue(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, j *entity.Job) error {
+					assert.Equal(t, entity.JobCreatePlanet, j.Kind)
+					j.ID = "5f3a1c2b9a9e070b1ef2e29a"
+					j.Status = entity.JobPending
+					return tt.err
+				})
+			}
+
+			router := gin.New()
+			router.POST("/v2/planets", Planets{Jobs: jobsMock}.Post)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", tt.target, strings.NewReader(`{"name":"Kamino","climate":"temperate","terrain":"ocean"}`))
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.Equal(t, tt.wantLocation, w.Header().Get("Location"))
+			assert.Equal(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestDistinct(t *testing.T) {
+	t.Parallel()
+
+	type test struct {
+		name           string
+		field          string
+		handler        func(p Planets, c *gin.Context)
+		serializer     serializer.Serializer
+		values         []string
+		err            error
+		wantStatusCode int
+		wantBody       string
+	}
+
+	tests := []test{
+		{
+			name:           "climates in v2",
+			field:          "climate",
+			handler:        Planets.Climates,
+			serializer:     serializer.V2(),
+			values:         []string{"arid", "temperate"},
+			wantStatusCode: 200,
+			wantBody:       `{"data":["arid","temperate"]}`,
+		},
+		{
+			name:           "climates",
+			field:          "climate",
+			handler:        Planets.Climates,
+			values:         []string{"arid", "temperate"},
+			wantStatusCode: 200,
+			wantBody:       `["arid","temperate"]`,
+		},
+		{
+			name:           "terrains",
+			field:          "terrain",
+			handler:        Planets.Terrains,
+			values:         []string{"desert"},
+			wantStatusCode: 200,
+			wantBody:       `["desert"]`,
+		},
+		{
+			name:           "error",
+			field:          "terrain",
+			handler:        Planets.Terrains,
+			err:            handler.InternalServer{Message: "error"},
+			wantStatusCode: 500,
+			wantBody:       `{"error":"internal server error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET", "/"+tt.field+"s", nil)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			srvMock := mock_planet.NewMockService(ctrl)
+			srvMock.EXPECT().Distinct(gomock.Any(), tt.field).Return(tt.values, tt.err)
+
+			tt.handler(Planets{Srv: srvMock, Serializer: tt.serializer}, c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.Equal(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	t.Parallel()
+
+	type test struct {
+		name           string
+		idParam        string
+		planet         *entity.Planet
+		err            error
+		wantStatusCode int
+		wantBody       string
+	}
+
+	tests := []test{
+		{
+			name:    "happy path",
+			idParam: "5f29e53f2939a742014a04af",
+			planet: &entity.Planet{
+				ID:         "5f29e53f2939a742014a04af",
+				Name:       "Tatooine",
+				Climate:    entity.Terms{"arid"},
+				Terrain:    entity.Terms{"desert"},
+				TotalFilms: 5,
+			},
+			wantStatusCode: 200,
+			wantBody:       `{"id":"5f29e53f2939a742014a04af","name":"Tatooine","climate":["arid"],"terrain":["desert"],"totalFilms":5}`,
+		},
+		{
+			name:           "error",
+			idParam:        "5f29e53f2939a742014a04af",
+			err:            handler.NotFound{Message: "planet not found"},
+			wantStatusCode: 404,
+			wantBody:       `{"error":"planet not found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = []gin.Param{{Key: "id", Value: tt.idParam}}
+			c.Request, _ = http.NewRequest("POST", "/planets/"+tt.idParam+"/restore", nil)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			srvMock := mock_planet.NewMockService(ctrl)
+			srvMock.EXPECT().Restore(gomock.Any(), tt.idParam, handler.AnyVersion).Return(tt.planet, tt.err)
+
+			Planets{
+				Srv: srvMock,
+			}.Restore(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.Equal(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestPurge(t *testing.T) {
+	t.Parallel()
+
+	type test struct {
+		name           string
+		uri            string
+		retention      time.Duration
+		total          int64
+		err            error
+		wantStatusCode int
+		wantBody       string
+	}
+
+	tests := []test{
+		{
+			name:           "happy path",
+			uri:            "http://t.test/?retention=24h",
+			retention:      24 * time.Hour,
+			total:          2,
+			wantStatusCode: 200,
+			wantBody:       `{"purged":2}`,
+		},
+		{
+			name:           "when retention is invalid",
+			uri:            "http://t.test/?retention=a",
+			wantStatusCode: 400,
+			wantBody:       `{"error":"retention is invalid"}`,
+		},
+		{
+			name:           "error",
+			uri:            "http://t.test/?retention=1h",
+			retention:      time.Hour,
+			err:            handler.InternalServer{Message: "error"},
+			wantStatusCode: 500,
+			wantBody:       `{"error":"internal server error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", tt.uri, nil)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			srvMock := mock_planet.NewMockService(ctrl)
+
+			if tt.retention != 0 {
+				srvMock.EXPECT().Purge(gomock.Any(), tt.retention).Return(tt.total, tt.err)
+			}
+
+			Planets{
+				Srv: srvMock,
+			}.Purge(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.Equal(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestPut(t *testing.T) {
+	t.Parallel()
+
+	type test struct {
+		name           string
+		body           string
+		ifMatch        string
+		version        int64
+		update         bool
+		err            error
+		wantStatusCode int
+		wantBody       string
+		wantETag       string
+	}
+
+	tests := []test{
+		{
+			name:           "happy path",
+			body:           `{"name":"Tatooine","climate":"arid, hot","terrain":"desert"}`,
+			ifMatch:        `"2"`,
+			version:        2,
+			update:         true,
+			wantStatusCode: 200,
+			wantBody:       `{"id":"5f29e53f2939a742014a04af","name":"Tatooine","climate":["arid","hot"],"terrain":["desert"],"totalFilms":0,"version":3}`,
+			wantETag:       `"3"`,
+		},
+		{
+			name:           "when If-Match is invalid",
+			body:           `{"name":"Tatooine","climate":"arid","terrain":"desert"}`,
+			ifMatch:        `2`,
+			wantStatusCode: 400,
+			wantBody:       `{"error":"If-Match is invalid"}`,
+		},
+		{
+			name:           "when invalid payload",
+			body:           ``,
+			wantStatusCode: 400,
+			wantBody:       `{"error":"body is invalid"}`,
+		},
+		{
+			name:           "when version is stale",
+			body:           `{"name":"Tatooine","climate":"arid","terrain":"desert"}`,
+			ifMatch:        `"1"`,
+			version:        1,
+			update:         true,
+			err:            handler.PreconditionFailed{Message: "planet was modified by another request"},
+			wantStatusCode: 412,
+			wantBody:       `{"error":"planet was modified by another request"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = []gin.Param{{Key: "id", Value: "5f29e53f2939a742014a04af"}}
+			c.Request, _ = http.NewRequest("PUT", "/planets/5f29e53f2939a742014a04af", bytes.NewBufferString(tt.body))
+			if tt.ifMatch != "" {
+				c.Request.Header.Set("If-Match", tt.ifMatch)
+			}
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			srvMock := mock_planet.NewMockService(ctrl)
+
+			if tt.update {
+				srvMock.EXPECT().Update(gomock.Any(), gomock.Any(), tt.version).DoAndReturn(
+					func(_ context.Context, planet *entity.Planet, version int64) error {
+						assert.Equal(t, "5f29e53f2939a742014a04af", planet.ID)
+						planet.Version = version + 1
+						return tt.err
+					})
+			}
+
+			Planets{
+				Srv: srvMock,
+			}.Put(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.Equal(t, tt.wantBody, w.Body.String())
+			assert.Equal(t, tt.wantETag, w.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestPostBatch(t *testing.T) {
+	t.Parallel()
+
+	type test struct {
+		name           string
+		uri            string
+		body           string
+		atomic         bool
+		errs           []error
+		err            error
+		wantStatusCode int
+		wantBody       string
+	}
+
+	tests := []test{
+		{
+			name:           "answers the outcome of each planet",
+			uri:            "/planets:batch",
+			body:           `[{"name":"Kamino","climate":"temperate","terrain":"ocean"},{"name":"Hoth","climate":"frozen","terrain":"sand"}]`,
+			errs:           []error{nil, handler.BadRequest{Message: `terrain has unknown value: "sand"`}},
+			wantStatusCode: 207,
+			wantBody: `[{"index":0,"status":201,"id":"5f2c891e9a9e070b1ef2e28c","planet":{"id":"5f2c891e9a9e070b1ef2e28c","name":"Kamino","climate":["temperate"],"terrain":["ocean"],"totalFilms":0,"version":1}},` +
+				`{"index":1,"status":400,"error":"terrain has unknown value: \"sand\""}]`,
+		},
+		{
+			name:           "atomic",
+			uri:            "/planets:batch?atomic=true",
+			body:           `[{"name":"Kamino","climate":"temperate","terrain":"ocean"}]`,
+			atomic:         true,
+			errs:           []error{handler.InternalServer{Message: "write conflict"}},
+			wantStatusCode: 207,
+			wantBody:       `[{"index":0,"status":500,"error":"internal server error"}]`,
+		},
+		{
+			name:           "when the batch fails as a whole",
+			uri:            "/planets:batch?atomic=1",
+			body:           `[{"name":"Kamino","climate":"temperate","terrain":"ocean"}]`,
+			atomic:         true,
+			err:            handler.BadRequest{Message: "atomic batches are not supported by the database"},
+			wantStatusCode: 400,
+			wantBody:       `{"error":"atomic batches are not supported by the database"}`,
+		},
+		{
+			name:           "when invalid payload",
+			uri:            "/planets:batch",
+			body:           `{"name":"Kamino"}`,
+			wantStatusCode: 400,
+			wantBody:       `{"error":"body is invalid"}`,
+		},
+		{
+			name:           "when the batch is empty",
+			uri:            "/planets:batch",
+			body:           `[]`,
+			wantStatusCode: 400,
+			wantBody:       `{"error":"batch is empty"}`,
+		},
+		{
+			name:           "when the batch is too large",
+			uri:            "/planets:batch",
+			body:           "[" + strings.Repeat(`{"name":"Kamino"},`, env.Vars.Planets.BatchSize) + `{"name":"Kamino"}]`,
+			wantStatusCode: 400,
+			wantBody:       fmt.Sprintf(`{"error":"batch exceeds %d items"}`, env.Vars.Planets.BatchSize),
+		},
+		{
+			name:           "when atomic is invalid",
+			uri:            "/planets:batch?atomic=yes",
+			body:           `[{"name":"Kamino","climate":"temperate","terrain":"ocean"}]`,
+			wantStatusCode: 400,
+			wantBody:       `{"error":"atomic is invalid"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", tt.uri, bytes.NewBufferString(tt.body))
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			srvMock := mock_planet.NewMockService(ctrl)
+
+			if tt.errs != nil || tt.err != nil {
+				srvMock.EXPECT().SaveMany(gomock.Any(), gomock.Any(), tt.atomic).DoAndReturn(
+					func(_ context.Context, planets []entity.Planet, _ bool) ([]error, error) {
+						for i := range planets {
+							if tt.errs != nil && tt.errs[i] == nil {
+								planets[i].ID, planets[i].Version = "5f2c891e9a9e070b1ef2e28c", 1
+							}
+						}
+						return tt.errs, tt.err
+					})
+			}
+
+			Planets{
+				Srv: srvMock,
+			}.PostBatch(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.Equal(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestDeleteBatch(t *testing.T) {
+	t.Parallel()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("DELETE", "/planets:batch", bytes.NewBufferString(
+		`[{"id":"5f29e53f2939a742014a04af","version":3},{"id":"5f29e53f2939a742014a04b0"}]`,
+	))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	srvMock := mock_planet.NewMockService(ctrl)
+	srvMock.EXPECT().DeleteMany(gomock.Any(), []entity.PlanetRef{
+		{ID: "5f29e53f2939a742014a04af", Version: 3},
+		{ID: "5f29e53f2939a742014a04b0", Version: handler.AnyVersion},
+	}, false).Return([]error{handler.PreconditionFailed{Message: "planet was modified by another request"}, nil}, nil)
+
+	Planets{
+		Srv:        srvMock,
+		Serializer: serializer.V2(),
+	}.DeleteBatch(c)
+
+	assert.Equal(t, 207, w.Code)
+	assert.Equal(t, `{"data":[{"index":0,"status":412,"id":"5f29e53f2939a742014a04af","error":"planet was modified by another request"},`+
+		`{"index":1,"status":200,"id":"5f29e53f2939a742014a04b0"}]}`, w.Body.String())
+}
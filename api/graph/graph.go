@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"context"
+	"star-wars/api/handler"
+	"star-wars/logger"
+	"star-wars/planet"
+	"star-wars/swapi"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"go.uber.org/zap"
+)
+
+// Request body of a GraphQL call
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Executor contract
+type Executor interface {
+	Execute(ctx context.Context, req Request) *graphql.Result
+}
+
+type executor struct {
+	schema  graphql.Schema
+	planets planet.Service
+	swapi   swapi.Service
+}
+
+// New returns an executor over the planets, following their SWAPI links for
+// films and residents; authorize guards the mutations, nil lets everyone in
+func New(planets planet.Service, s swapi.Service, authorize Authorize) (Executor, error) {
+	schema, err := schema(planets, authorize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &executor{
+		schema:  schema,
+		planets: planets,
+		swapi:   s,
+	}, nil
+}
+
+// Execute runs req with loaders of its own, errors carrying the code of the
+// status the REST routes would answer in their extensions
+func (e executor) Execute(ctx context.Context, req Request) *graphql.Result {
+	result := graphql.Do(graphql.Params{
+		Schema:         e.schema,
+		RequestString:  req.Query,
+		OperationName:  req.OperationName,
+		VariableValues: req.Variables,
+		Context:        withLoaders(ctx, newLoaders(e.planets, e.swapi)),
+	})
+
+	for i := range result.Errors {
+		classify(ctx, &result.Errors[i])
+	}
+
+	return result
+}
+
+// code of the handler error err, as other GraphQL servers name them
+func code(err error) string {
+	switch err.(type) {
+	case handler.BadRequest:
+		return "BAD_REQUEST"
+	case handler.Unauthorized:
+		return "UNAUTHENTICATED"
+	case handler.Forbidden:
+		return "FORBIDDEN"
+	case handler.NotFound:
+		return "NOT_FOUND"
+	case handler.PreconditionFailed:
+		return "PRECONDITION_FAILED"
+	case handler.TooManyRequests:
+		return "TOO_MANY_REQUESTS"
+	case handler.InternalServer:
+		return "INTERNAL_SERVER_ERROR"
+	}
+	return ""
+}
+
+// classify sets the code of err from the error a resolver returned
+func classify(ctx context.Context, err *gqlerrors.FormattedError) {
+	cause := original(err.OriginalError())
+
+	code := code(cause)
+	if code == "" {
+		return
+	}
+
+	if code == "INTERNAL_SERVER_ERROR" {
+		logger.FromContext(ctx).Error(err.Message, zap.Error(handler.Cause(cause)))
+	}
+
+	err.Extensions = map[string]interface{}{"code": code}
+}
+
+// original unwraps the errors graphql wraps resolver errors in
+func original(err error) error {
+	for {
+		switch e := err.(type) {
+		case gqlerrors.FormattedError:
+			if e.OriginalError() == nil {
+				return e
+			}
+			err = e.OriginalError()
+		case *gqlerrors.Error:
+			if e.OriginalError == nil {
+				return e
+			}
+			err = e.OriginalError
+		default:
+			return err
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"star-wars/api/handler"
+	"star-wars/auth"
+	"star-wars/entity"
+	"star-wars/planet/mock_planet"
+	"star-wars/swapi/adapter"
+	"star-wars/swapi/mock_swapi"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	newHope = "https://swapi.dev/api/films/1/"
+	empire  = "https://swapi.dev/api/films/2/"
+	luke    = "https://swapi.dev/api/people/1/"
+	leia    = "https://swapi.dev/api/people/5/"
+)
+
+// fetch answers Fetch from SWAPI resources by url
+func fetch(resources map[string]interface{}) func(context.Context, string, interface{}) error {
+	return func(_ context.Context, url string, v interface{}) error {
+		switch v := v.(type) {
+		case *adapter.Film:
+			*v = resources[url].(adapter.Film)
+		case *adapter.Person:
+			*v = resources[url].(adapter.Person)
+		}
+		return nil
+	}
+}
+
+func execute(t *testing.T, e Executor, ctx context.Context, query string, variables map[string]interface{}) string {
+	body, err := json.Marshal(e.Execute(ctx, Request{Query: query, Variables: variables}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestPlanetsQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	planets := mock_planet.NewMockService(ctrl)
+	swapi := mock_swapi.NewMockService(ctrl)
+
+	planets.EXPECT().FindAll(gomock.Any(), int64(2), int64(0)).Return(&[]entity.Planet{
+		{ID: "5f2c891e9a9e070b1ef2e28c", Name: "Tatooine", TotalFilms: 2},
+		{ID: "5f2c891e9a9e070b1ef2e28d", Name: "Alderaan", TotalFilms: 1},
+	}, nil)
+
+	// one search per planet and one fetch per distinct link, however many
+	// planets share a film
+	swapi.EXPECT().GetPlanet(gomock.Any(), "Tatooine").Return(adapter.Planets{Results: []adapter.Planet{
+		{Name: "Tatooine", Films: []string{newHope, empire}, Residents: []string{luke}},
+	}}, nil)
+	swapi.EXPECT().GetPlanet(gomock.Any(), "Alderaan").Return(adapter.Planets{Results: []adapter.Planet{
+		{Name: "Alderaan II"},
+		{Name: "Alderaan", Films: []string{newHope}, Residents: []string{leia}},
+	}}, nil)
+
+	resources := fetch(map[string]interface{}{
+		newHope: adapter.Film{Title: "A New Hope", EpisodeID: 4},
+		empire:  adapter.Film{Title: "The Empire Strikes Back", EpisodeID: 5},
+		luke:    adapter.Person{Name: "Luke Skywalker"},
+		leia:    adapter.Person{Name: "Leia Organa"},
+	})
+	for _, url := range []string{newHope, empire, luke, leia} {
+		swapi.EXPECT().Fetch(gomock.Any(), url, gomock.Any()).DoAndReturn(resources)
+	}
+
+	e, err := New(planets, swapi, nil)
+	assert.NoError(t, err)
+
+	got := execute(t, e, context.Background(), `{
+		planets(limit: 2) {
+			name
+			totalFilms
+			films { title episodeId }
+			residents { name url }
+		}
+	}`, nil)
+
+	assert.JSONEq(t, `{"data":{"planets":[
+		{"name":"Tatooine","totalFilms":2,
+		 "films":[{"title":"A New Hope","episodeId":4},{"title":"The Empire Strikes Back","episodeId":5}],
+		 "residents":[{"name":"Luke Skywalker","url":"`+luke+`"}]},
+		{"name":"Alderaan","totalFilms":1,
+		 "films":[{"title":"A New Hope","episodeId":4}],
+		 "residents":[{"name":"Leia Organa","url":"`+leia+`"}]}
+	]}}`, got)
+}
+
+func TestPlanetQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	planets := mock_planet.NewMockService(ctrl)
+	swapi := mock_swapi.NewMockService(ctrl)
+
+	planets.EXPECT().FindByIDs(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, ids []string) (*[]entity.Planet, error) {
+		assert.ElementsMatch(t, []string{"5f2c891e9a9e070b1ef2e28c", "5f2c891e9a9e070b1ef2e28d", "unknown"}, ids)
+		return &[]entity.Planet{
+			{ID: "5f2c891e9a9e070b1ef2e28c", Name: "Tatooine", Climate: entity.Terms{"arid"}},
+			{ID: "5f2c891e9a9e070b1ef2e28d", Name: "Hoth", Climate: entity.Terms{"frozen"}},
+		}, nil
+	})
+	swapi.EXPECT().GetPlanet(gomock.Any(), "Hoth").Return(adapter.Planets{}, nil)
+
+	e, err := New(planets, swapi, nil)
+	assert.NoError(t, err)
+
+	got := execute(t, e, context.Background(), `{
+		tatooine: planet(id: "5f2c891e9a9e070b1ef2e28c") { name climate }
+		hoth: planet(id: "5f2c891e9a9e070b1ef2e28d") { name films { title } }
+		unknown: planet(id: "unknown") { name }
+	}`, nil)
+
+	assert.JSONEq(t, `{"data":{
+		"tatooine":{"name":"Tatooine","climate":["arid"]},
+		"hoth":{"name":"Hoth","films":[]},
+		"unknown":null
+	}}`, got)
+}
+
+func TestSearch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	planets := mock_planet.NewMockService(ctrl)
+
+	planets.EXPECT().FindByName(gomock.Any(), "Hoth").Return(&entity.Planet{Name: "Hoth"}, nil)
+	planets.EXPECT().FindByName(gomock.Any(), "Naboo").Return(nil, handler.NotFound{Message: "planet not found"})
+	planets.EXPECT().FindByName(gomock.Any(), "Kamino").Return(nil, handler.InternalServer{Message: "connection refused"})
+
+	e, err := New(planets, mock_swapi.NewMockService(ctrl), nil)
+	assert.NoError(t, err)
+
+	query := `query ($name: String) { planets(search: $name) { name } }`
+
+	assert.JSONEq(t, `{"data":{"planets":[{"name":"Hoth"}]}}`, execute(t, e, context.Background(), query, map[string]interface{}{"name": "Hoth"}))
+	assert.JSONEq(t, `{"data":{"planets":[]}}`, execute(t, e, context.Background(), query, map[string]interface{}{"name": "Naboo"}))
+	assert.JSONEq(t, `{"data":null,"errors":[{
+		"message":"internal server error","locations":[{"line":1,"column":25}],"path":["planets"],
+		"extensions":{"code":"INTERNAL_SERVER_ERROR"}
+	}]}`, execute(t, e, context.Background(), query, map[string]interface{}{"name": "Kamino"}))
+}
+
+func TestMutations(t *testing.T) {
+	anonymous := auth.With(context.Background(), auth.Principal{Role: auth.RoleReader})
+	editor := auth.With(context.Background(), auth.Principal{Subject: "ci", Role: auth.RoleEditor, Method: "api-key"})
+
+	type test struct {
+		name      string
+		ctx       context.Context
+		query     string
+		variables map[string]interface{}
+		mock      func(*mock_planet.MockService)
+		want      string
+	}
+
+	tests := []test{
+		{
+			name:  "create requires the editor role",
+			ctx:   anonymous,
+			query: `mutation { createPlanet(input: {name: "Hoth", climate: ["frozen"], terrain: ["tundra"]}) { id } }`,
+			want: `{"data":null,"errors":[{
+				"message":"authentication required","locations":[{"line":1,"column":12}],"path":["createPlanet"],
+				"extensions":{"code":"UNAUTHENTICATED"}
+			}]}`,
+		},
+		{
+			name:  "create",
+			ctx:   editor,
+			query: `mutation { createPlanet(input: {name: "Hoth", climate: ["frozen"], terrain: ["tundra"]}) { id name version } }`,
+			mock: func(m *mock_planet.MockService) {
+				m.EXPECT().Save(gomock.Any(), &entity.Planet{Name: "Hoth", Climate: entity.Terms{"frozen"}, Terrain: entity.Terms{"tundra"}}).
+					DoAndReturn(func(_ context.Context, p *entity.Planet) error {
+						p.ID, p.Version = "5f2c891e9a9e070b1ef2e28c", 1
+						return nil
+					})
+			},
+			want: `{"data":{"createPlanet":{"id":"5f2c891e9a9e070b1ef2e28c","name":"Hoth","version":1}}}`,
+		},
+		{
+			name:  "create normalizes climate and terrain",
+			ctx:   editor,
+			query: `mutation { createPlanet(input: {name: "Hoth", climate: ["Temperate "], terrain: [" TUNDRA", ""]}) { id } }`,
+			mock: func(m *mock_planet.MockService) {
+				m.EXPECT().Save(gomock.Any(), &entity.Planet{Name: "Hoth", Climate: entity.Terms{"temperate"}, Terrain: entity.Terms{"tundra"}}).Return(nil)
+			},
+			want: `{"data":{"createPlanet":{"id":""}}}`,
+		},
+		{
+			name:  "create validates the planet",
+			ctx:   editor,
+			query: `mutation { createPlanet(input: {name: "Hoth", climate: [], terrain: ["tundra"]}) { id } }`,
+			want: `{"data":null,"errors":[{
+				"message":"name, climate and terrain is required","locations":[{"line":1,"column":12}],"path":["createPlanet"],
+				"extensions":{"code":"BAD_REQUEST"}
+			}]}`,
+		},
+		{
+			name:      "update checks the version",
+			ctx:       editor,
+			query:     `mutation ($v: Int) { updatePlanet(id: "5f2c891e9a9e070b1ef2e28c", version: $v, input: {name: "Hoth", climate: ["frozen"], terrain: ["tundra"]}) { id } }`,
+			variables: map[string]interface{}{"v": 2},
+			mock: func(m *mock_planet.MockService) {
+				m.EXPECT().Update(gomock.Any(), gomock.Any(), int64(2)).Return(handler.PreconditionFailed{Message: "planet was modified"})
+			},
+			want: `{"data":null,"errors":[{
+				"message":"planet was modified","locations":[{"line":1,"column":22}],"path":["updatePlanet"],
+				"extensions":{"code":"PRECONDITION_FAILED"}
+			}]}`,
+		},
+		{
+			name:  "update normalizes climate and terrain",
+			ctx:   editor,
+			query: `mutation { updatePlanet(id: "5f2c891e9a9e070b1ef2e28c", input: {name: "Hoth", climate: ["Temperate "], terrain: ["tundra"]}) { id } }`,
+			mock: func(m *mock_planet.MockService) {
+				m.EXPECT().Update(gomock.Any(), &entity.Planet{ID: "5f2c891e9a9e070b1ef2e28c", Name: "Hoth", Climate: entity.Terms{"temperate"}, Terrain: entity.Terms{"tundra"}}, handler.AnyVersion).Return(nil)
+			},
+			want: `{"data":{"updatePlanet":{"id":"5f2c891e9a9e070b1ef2e28c"}}}`,
+		},
+		{
+			name:  "delete any version",
+			ctx:   editor,
+			query: `mutation { deletePlanet(id: "5f2c891e9a9e070b1ef2e28c") }`,
+			mock: func(m *mock_planet.MockService) {
+				m.EXPECT().Delete(gomock.Any(), "5f2c891e9a9e070b1ef2e28c", handler.AnyVersion).Return(nil)
+			},
+			want: `{"data":{"deletePlanet":true}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			planets := mock_planet.NewMockService(ctrl)
+			if tt.mock != nil {
+				tt.mock(planets)
+			}
+
+			e, err := New(planets, mock_swapi.NewMockService(ctrl), auth.Check)
+			assert.NoError(t, err)
+
+			assert.JSONEq(t, tt.want, execute(t, e, tt.ctx, tt.query, tt.variables))
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package graph
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchFunc loads keys at once; keys missing from the result resolve to nil
+// and keys mapped to an error fail alone, while an error returned fails them all
+type BatchFunc func(ctx context.Context, keys []string) (map[string]interface{}, error)
+
+// Loader collects the keys asked for while GraphQL resolves a level of the
+// query and loads them in a single batch on the first thunk called, caching
+// the results for the rest of the request
+type Loader struct {
+	batch   BatchFunc
+	mu      sync.Mutex
+	pending []string
+	results map[string]*result
+}
+
+type result struct {
+	value interface{}
+	err   error
+	done  bool
+}
+
+// NewLoader returns a loader batching with batch
+func NewLoader(batch BatchFunc) *Loader {
+	return &Loader{
+		batch:   batch,
+		results: map[string]*result{},
+	}
+}
+
+// Load queues key and returns a thunk resolving it
+func (l *Loader) Load(ctx context.Context, key string) func() (interface{}, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	r, ok := l.results[key]
+	if !ok {
+		r = &result{}
+		l.results[key] = r
+		l.pending = append(l.pending, key)
+	}
+
+	return func() (interface{}, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if !r.done {
+			l.dispatch(ctx)
+		}
+
+		return r.value, r.err
+	}
+}
+
+// dispatch loads every pending key, l.mu held
+func (l *Loader) dispatch(ctx context.Context) {
+	keys := l.pending
+	l.pending = nil
+
+	values, err := l.batch(ctx, keys)
+
+	for _, key := range keys {
+		r := l.results[key]
+		r.value, r.err, r.done = values[key], err, true
+
+		if failed, ok := r.value.(error); ok && err == nil {
+			r.value, r.err = nil, failed
+		}
+	}
+}
+
+// Parallel batches calls to fetch, which loads a single key, running at most
+// limit of them at a time
+func Parallel(limit int, fetch func(ctx context.Context, key string) (interface{}, error)) BatchFunc {
+	return func(ctx context.Context, keys []string) (map[string]interface{}, error) {
+		var (
+			mu     sync.Mutex
+			wg     sync.WaitGroup
+			values = make(map[string]interface{}, len(keys))
+			slots  = make(chan struct{}, limit)
+		)
+
+		for _, key := range keys {
+			wg.Add(1)
+			slots <- struct{}{}
+
+			go func(key string) {
+				defer func() { <-slots; wg.Done() }()
+
+				value, err := fetch(ctx, key)
+
+				if err != nil {
+					value = err
+				}
+
+				mu.Lock()
+				values[key] = value
+				mu.Unlock()
+			}(key)
+		}
+
+		wg.Wait()
+
+		return values, nil
+	}
+}
@@ -0,0 +1,102 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoader(t *testing.T) {
+	t.Run("batches and dedupes the keys queued before the first thunk", func(t *testing.T) {
+		var batches [][]string
+		l := NewLoader(func(_ context.Context, keys []string) (map[string]interface{}, error) {
+			batches = append(batches, keys)
+			values := map[string]interface{}{}
+			for _, k := range keys {
+				values[k] = "planet " + k
+			}
+			return values, nil
+		})
+
+		ctx := context.Background()
+		a, b, again := l.Load(ctx, "1"), l.Load(ctx, "2"), l.Load(ctx, "1")
+
+		v, err := b()
+		assert.NoError(t, err)
+		assert.Equal(t, "planet 2", v)
+
+		v, _ = a()
+		assert.Equal(t, "planet 1", v)
+		v, _ = again()
+		assert.Equal(t, "planet 1", v)
+
+		v, _ = l.Load(ctx, "1")()
+		assert.Equal(t, "planet 1", v, "cached")
+
+		v, _ = l.Load(ctx, "3")()
+		assert.Equal(t, "planet 3", v)
+
+		assert.Equal(t, [][]string{{"1", "2"}, {"3"}}, batches)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		failed := errors.New("failed")
+
+		l := NewLoader(func(_ context.Context, keys []string) (map[string]interface{}, error) {
+			return map[string]interface{}{"bad": failed, "good": 1}, nil
+		})
+		ctx := context.Background()
+		good, bad, missing := l.Load(ctx, "good"), l.Load(ctx, "bad"), l.Load(ctx, "missing")
+
+		v, err := good()
+		assert.Equal(t, 1, v)
+		assert.NoError(t, err)
+
+		_, err = bad()
+		assert.Equal(t, failed, err)
+
+		v, err = missing()
+		assert.Nil(t, v)
+		assert.NoError(t, err)
+
+		l = NewLoader(func(_ context.Context, keys []string) (map[string]interface{}, error) {
+			return nil, failed
+		})
+		_, err = l.Load(ctx, "good")()
+		assert.Equal(t, failed, err)
+	})
+}
+
+func TestParallel(t *testing.T) {
+	var running, most int32
+
+	batch := Parallel(2, func(_ context.Context, key string) (interface{}, error) {
+		now := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+
+		for {
+			seen := atomic.LoadInt32(&most)
+			if now <= seen || atomic.CompareAndSwapInt32(&most, seen, now) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		if key == "bad" {
+			return nil, errors.New("failed")
+		}
+		return key + "!", nil
+	})
+
+	values, err := batch(context.Background(), []string{"a", "b", "bad", "c", "d"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "a!", values["a"])
+	assert.Equal(t, "d!", values["d"])
+	assert.EqualError(t, values["bad"].(error), "failed")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&most))
+}
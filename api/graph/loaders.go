@@ -0,0 +1,75 @@
+package graph
+
+import (
+	"context"
+	"star-wars/planet"
+	"star-wars/swapi"
+	"star-wars/swapi/adapter"
+	"strings"
+)
+
+// fetches SWAPI calls a batch runs at once
+const fetches = 4
+
+// loaders of a single request, so nothing is cached across clients
+type loaders struct {
+	// planets by id
+	planets *Loader
+	// swapi planets by name, nil when SWAPI does not know the name
+	swapi *Loader
+	// films by url
+	films *Loader
+	// people by url
+	people *Loader
+}
+
+func newLoaders(planets planet.Service, s swapi.Service) *loaders {
+	return &loaders{
+		planets: NewLoader(func(ctx context.Context, ids []string) (map[string]interface{}, error) {
+			found, err := planets.FindByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+
+			values := make(map[string]interface{}, len(*found))
+			for i := range *found {
+				values[(*found)[i].ID] = &(*found)[i]
+			}
+			return values, nil
+		}),
+		swapi: NewLoader(Parallel(fetches, func(ctx context.Context, name string) (interface{}, error) {
+			result, err := s.GetPlanet(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+
+			// search matches substrings, Naboo must not answer for Naboo Prime
+			for i := range result.Results {
+				if strings.EqualFold(result.Results[i].Name, name) {
+					return &result.Results[i], nil
+				}
+			}
+			return nil, nil
+		})),
+		films: NewLoader(Parallel(fetches, func(ctx context.Context, url string) (interface{}, error) {
+			var film adapter.Film
+			err := s.Fetch(ctx, url, &film)
+			return &film, err
+		})),
+		people: NewLoader(Parallel(fetches, func(ctx context.Context, url string) (interface{}, error) {
+			var person adapter.Person
+			err := s.Fetch(ctx, url, &person)
+			return &person, err
+		})),
+	}
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
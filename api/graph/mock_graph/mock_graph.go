@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: api/graph/graph.go
+
+// Package mock_graph is a generated GoMock package.
+package mock_graph
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	graphql "github.com/graphql-go/graphql"
+	reflect "reflect"
+	graph "star-wars/api/graph"
+)
+
+// MockExecutor is a mock of Executor interface
+type MockExecutor struct {
+	ctrl     *gomock.Controller
+	recorder *MockExecutorMockRecorder
+}
+
+// MockExecutorMockRecorder is the mock recorder for MockExecutor
+type MockExecutorMockRecorder struct {
+	mock *MockExecutor
+}
+
+// NewMockExecutor creates a new mock instance
+func NewMockExecutor(ctrl *gomock.Controller) *MockExecutor {
+	mock := &MockExecutor{ctrl: ctrl}
+	mock.recorder = &MockExecutorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockExecutor) EXPECT() *MockExecutorMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method
+func (m *MockExecutor) Execute(ctx context.Context, req graph.Request) *graphql.Result {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute", ctx, req)
+	ret0, _ := ret[0].(*graphql.Result)
+	return ret0
+}
+
+// Execute indicates an expected call of Execute
+func (mr *MockExecutorMockRecorder) Execute(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockExecutor)(nil).Execute), ctx, req)
+}
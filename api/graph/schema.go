@@ -0,0 +1,287 @@
+package graph
+
+import (
+	"context"
+	"star-wars/api/handler"
+	"star-wars/auth"
+	"star-wars/entity"
+	"star-wars/planet"
+	"star-wars/swapi/adapter"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Authorize tells why the request cannot act as role, nil when it can
+type Authorize func(ctx context.Context, role auth.Role) error
+
+func terms() *graphql.NonNull {
+	return graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))
+}
+
+// fetched resolves a field of the resource whose url is the source, every
+// field of every resource at a level of the query sharing one batch
+func fetched(loader func(*loaders) *Loader, field func(interface{}) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		thunk := loader(loadersFrom(p.Context)).Load(p.Context, p.Source.(string))
+
+		return func() (interface{}, error) {
+			v, err := thunk()
+			if err != nil {
+				return nil, err
+			}
+			return field(v), nil
+		}, nil
+	}
+}
+
+func url(p graphql.ResolveParams) (interface{}, error) {
+	return p.Source, nil
+}
+
+func film(field func(*adapter.Film) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Resolve: fetched(func(l *loaders) *Loader { return l.films }, func(v interface{}) interface{} {
+			return field(v.(*adapter.Film))
+		}),
+	}
+}
+
+func person(field func(*adapter.Person) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Resolve: fetched(func(l *loaders) *Loader { return l.people }, func(v interface{}) interface{} {
+			return field(v.(*adapter.Person))
+		}),
+	}
+}
+
+func withType(f *graphql.Field, t graphql.Output) *graphql.Field {
+	f.Type = t
+	return f
+}
+
+var filmType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Film",
+	Description: "A film, as SWAPI tells",
+	Fields: graphql.Fields{
+		"url":         &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: url},
+		"title":       withType(film(func(f *adapter.Film) interface{} { return f.Title }), graphql.String),
+		"episodeId":   withType(film(func(f *adapter.Film) interface{} { return f.EpisodeID }), graphql.Int),
+		"director":    withType(film(func(f *adapter.Film) interface{} { return f.Director }), graphql.String),
+		"producer":    withType(film(func(f *adapter.Film) interface{} { return f.Producer }), graphql.String),
+		"releaseDate": withType(film(func(f *adapter.Film) interface{} { return f.ReleaseDate }), graphql.String),
+	},
+})
+
+var personType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Person",
+	Description: "A resident of a planet, as SWAPI tells",
+	Fields: graphql.Fields{
+		"url":       &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: url},
+		"name":      withType(person(func(p *adapter.Person) interface{} { return p.Name }), graphql.String),
+		"birthYear": withType(person(func(p *adapter.Person) interface{} { return p.BirthYear }), graphql.String),
+		"gender":    withType(person(func(p *adapter.Person) interface{} { return p.Gender }), graphql.String),
+		"height":    withType(person(func(p *adapter.Person) interface{} { return p.Height }), graphql.String),
+		"mass":      withType(person(func(p *adapter.Person) interface{} { return p.Mass }), graphql.String),
+	},
+})
+
+// swapiLinks resolves the links of the SWAPI planet with the source name,
+// none when SWAPI does not know it
+func swapiLinks(links func(*adapter.Planet) []string) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		thunk := loadersFrom(p.Context).swapi.Load(p.Context, p.Source.(*entity.Planet).Name)
+
+		return func() (interface{}, error) {
+			v, err := thunk()
+			if err != nil || v == nil {
+				return []string{}, err
+			}
+			return links(v.(*adapter.Planet)), nil
+		}, nil
+	}
+}
+
+var planetType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Planet",
+	Fields: graphql.Fields{
+		"id":         &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+		"name":       &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"climate":    &graphql.Field{Type: terms()},
+		"terrain":    &graphql.Field{Type: terms()},
+		"totalFilms": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"createdAt":  &graphql.Field{Type: graphql.DateTime},
+		"updatedAt":  &graphql.Field{Type: graphql.DateTime},
+		"version":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"films": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(filmType))),
+			Description: "Films the planet appears in, empty when SWAPI does not know the planet",
+			Resolve:     swapiLinks(func(p *adapter.Planet) []string { return p.Films }),
+		},
+		"residents": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(personType))),
+			Description: "People living on the planet, empty when SWAPI does not know the planet",
+			Resolve:     swapiLinks(func(p *adapter.Planet) []string { return p.Residents }),
+		},
+	},
+})
+
+var planetInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "PlanetInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"name":    &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"climate": &graphql.InputObjectFieldConfig{Type: terms()},
+		"terrain": &graphql.InputObjectFieldConfig{Type: terms()},
+	},
+})
+
+// version argument, the If-Match of the REST routes
+var versionArg = &graphql.ArgumentConfig{
+	Type:        graphql.Int,
+	Description: "Version the client read, the mutation fails when the stored one differs",
+}
+
+// termsOf normalizes the listed values as the REST body does
+func termsOf(v interface{}) entity.Terms {
+	var values []string
+	for _, s := range v.([]interface{}) {
+		values = append(values, s.(string))
+	}
+	return entity.NewTerms(values...)
+}
+
+// fromInput builds a planet from the PlanetInput in args
+func fromInput(args map[string]interface{}) entity.Planet {
+	input := args["input"].(map[string]interface{})
+	return entity.Planet{
+		Name:    input["name"].(string),
+		Climate: termsOf(input["climate"]),
+		Terrain: termsOf(input["terrain"]),
+	}
+}
+
+// expected version in args, any when missing
+func expected(args map[string]interface{}) int64 {
+	if v, ok := args["version"].(int); ok {
+		return int64(v)
+	}
+	return handler.AnyVersion
+}
+
+func schema(srv planet.Service, authorize Authorize) (graphql.Schema, error) {
+	// editor guards the mutations as authorize does the REST routes
+	editor := func(resolve graphql.FieldResolveFn) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (interface{}, error) {
+			if authorize != nil {
+				if err := authorize(p.Context, auth.RoleEditor); err != nil {
+					return nil, err
+				}
+			}
+			return resolve(p)
+		}
+	}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"planet": &graphql.Field{
+				Type: planetType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadersFrom(p.Context).planets.Load(p.Context, p.Args["id"].(string)), nil
+				},
+			},
+			"planets": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(planetType))),
+				Args: graphql.FieldConfigArgument{
+					"limit":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 3},
+					"skip":   &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+					"search": &graphql.ArgumentConfig{Type: graphql.String, Description: "Exact name of the planet"},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if search, ok := p.Args["search"].(string); ok {
+						found, err := srv.FindByName(p.Context, search)
+						if _, missing := err.(handler.NotFound); missing {
+							return []*entity.Planet{}, nil
+						}
+						if err != nil {
+							return nil, err
+						}
+						return []*entity.Planet{found}, nil
+					}
+
+					found, err := srv.FindAll(p.Context, int64(p.Args["limit"].(int)), int64(p.Args["skip"].(int)))
+					if err != nil {
+						return nil, err
+					}
+
+					planets := make([]*entity.Planet, len(*found))
+					for i := range *found {
+						planets[i] = &(*found)[i]
+					}
+					return planets, nil
+				},
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createPlanet": &graphql.Field{
+				Type: graphql.NewNonNull(planetType),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(planetInput)},
+				},
+				Resolve: editor(func(p graphql.ResolveParams) (interface{}, error) {
+					planet := fromInput(p.Args)
+
+					if err := planet.Validate(); err != nil {
+						return nil, handler.BadRequest{Message: err.Error()}
+					}
+
+					if err := srv.Save(p.Context, &planet); err != nil {
+						return nil, err
+					}
+					return &planet, nil
+				}),
+			},
+			"updatePlanet": &graphql.Field{
+				Type: graphql.NewNonNull(planetType),
+				Args: graphql.FieldConfigArgument{
+					"id":      &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"version": versionArg,
+					"input":   &graphql.ArgumentConfig{Type: graphql.NewNonNull(planetInput)},
+				},
+				Resolve: editor(func(p graphql.ResolveParams) (interface{}, error) {
+					planet := fromInput(p.Args)
+					planet.ID = p.Args["id"].(string)
+
+					if err := srv.Update(p.Context, &planet, expected(p.Args)); err != nil {
+						return nil, err
+					}
+					return &planet, nil
+				}),
+			},
+			"deletePlanet": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{
+					"id":      &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"version": versionArg,
+				},
+				Resolve: editor(func(p graphql.ResolveParams) (interface{}, error) {
+					if err := srv.Delete(p.Context, p.Args["id"].(string), expected(p.Args)); err != nil {
+						return nil, err
+					}
+					return true, nil
+				}),
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    query,
+		Mutation: mutation,
+	})
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cache validators and policy of a representation
+type Cache struct {
+	ETag         string
+	LastModified time.Time
+	Control      string
+}
+
+// HashETag strong ETag derived from the JSON representation and the media
+// type it is answered in, so each representation has a tag of its own
+func HashETag(body interface{}, media string) string {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(append([]byte(media+"\n"), b...))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// NotModified writes the cache headers and answers 304 when the client copy is still fresh
+func NotModified(cache Cache, c *gin.Context) bool {
+	if cache.ETag != "" {
+		c.Header("ETag", cache.ETag)
+	}
+
+	if !cache.LastModified.IsZero() {
+		c.Header("Last-Modified", cache.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if cache.Control != "" {
+		c.Header("Cache-Control", cache.Control)
+	}
+
+	if !fresh(cache, c.Request) {
+		return false
+	}
+
+	c.Status(http.StatusNotModified)
+	c.Writer.WriteHeaderNow()
+	return true
+}
+
+func fresh(cache Cache, r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return cache.ETag != "" && matchETag(inm, cache.ETag)
+	}
+
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || cache.LastModified.IsZero() {
+		return false
+	}
+
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+
+	return !cache.LastModified.Truncate(time.Second).After(since)
+}
+
+// matchETag weak comparison as required for If-None-Match
+func matchETag(header string, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
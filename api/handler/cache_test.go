@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashETag(t *testing.T) {
+	json := JSON{}.ContentType()
+	assert.Equal(t, HashETag([]string{"arid"}, json), HashETag([]string{"arid"}, json))
+	assert.NotEqual(t, HashETag([]string{"arid"}, json), HashETag([]string{"frozen"}, json))
+	assert.NotEqual(t, HashETag([]string{"arid"}, json), HashETag([]string{"arid"}, CSV{}.ContentType()), "each representation has a tag of its own")
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, HashETag([]string{"arid"}, json))
+}
+
+func TestNotModified(t *testing.T) {
+	modified := time.Date(2020, 8, 7, 10, 0, 0, 500, time.UTC)
+	cache := Cache{ETag: `"2"`, LastModified: modified, Control: "public, max-age=60"}
+
+	type test struct {
+		name     string
+		method   string
+		headers  map[string]string
+		want     bool
+		wantCode int
+	}
+
+	tests := []test{
+		{name: "when there are no validators", method: "GET", wantCode: 200},
+		{name: "when the ETag matches", method: "GET", headers: map[string]string{"If-None-Match": `"2"`}, want: true, wantCode: 304},
+		{name: "when a weak ETag matches", method: "GET", headers: map[string]string{"If-None-Match": `W/"2"`}, want: true, wantCode: 304},
+		{name: "when the ETag is in a list", method: "HEAD", headers: map[string]string{"If-None-Match": `"1", "2"`}, want: true, wantCode: 304},
+		{name: "when the ETag is a wildcard", method: "GET", headers: map[string]string{"If-None-Match": "*"}, want: true, wantCode: 304},
+		{name: "when the ETag does not match", method: "GET", headers: map[string]string{"If-None-Match": `"1"`}, wantCode: 200},
+		{
+			name:     "when the ETag does not match but the date does",
+			method:   "GET",
+			headers:  map[string]string{"If-None-Match": `"1"`, "If-Modified-Since": "Fri, 07 Aug 2020 10:00:00 GMT"},
+			wantCode: 200,
+		},
+		{name: "when not modified since", method: "GET", headers: map[string]string{"If-Modified-Since": "Fri, 07 Aug 2020 10:00:00 GMT"}, want: true, wantCode: 304},
+		{name: "when modified since", method: "GET", headers: map[string]string{"If-Modified-Since": "Fri, 07 Aug 2020 09:59:59 GMT"}, wantCode: 200},
+		{name: "when the date is invalid", method: "GET", headers: map[string]string{"If-Modified-Since": "yesterday"}, wantCode: 200},
+		{name: "when the method is not a read", method: "PUT", headers: map[string]string{"If-None-Match": `"2"`}, wantCode: 200},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(tt.method, "/planets/1", nil)
+			for key, value := range tt.headers {
+				c.Request.Header.Set(key, value)
+			}
+
+			assert.Equal(t, tt.want, NotModified(cache, c))
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+			assert.Equal(t, "Fri, 07 Aug 2020 10:00:00 GMT", w.Header().Get("Last-Modified"))
+			assert.Equal(t, "public, max-age=60", w.Header().Get("Cache-Control"))
+		})
+	}
+}
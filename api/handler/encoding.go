@@ -0,0 +1,316 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+
+	"github.com/ugorji/go/codec"
+	"gopkg.in/yaml.v2"
+)
+
+// ErrNotEncodable the body has no representation in the media type
+var ErrNotEncodable = errors.New("not encodable")
+
+// Encoder writes response bodies in a media type
+type Encoder interface {
+	// MediaTypes the encoder answers to, the first one names it
+	MediaTypes() []string
+	// ContentType of the responses
+	ContentType() string
+	// Encode writes body to w, failing with ErrNotEncodable before writing anything
+	Encode(w io.Writer, body interface{}) error
+}
+
+// Collection is a body wrapping the items tabular encoders write, as the v2 envelope does
+type Collection interface {
+	Items() interface{}
+}
+
+// JSON encoder, answered when the client states no preference
+type JSON struct{}
+
+func (JSON) MediaTypes() []string { return []string{"application/json"} }
+func (JSON) ContentType() string  { return "application/json; charset=utf-8" }
+
+func (JSON) Encode(w io.Writer, body interface{}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// YAML encoder, keys named and ordered as in JSON
+type YAML struct{}
+
+func (YAML) MediaTypes() []string {
+	return []string{"application/yaml", "application/x-yaml", "text/yaml"}
+}
+func (YAML) ContentType() string { return "application/yaml; charset=utf-8" }
+
+func (YAML) Encode(w io.Writer, body interface{}) error {
+	value, err := ordered(body)
+	if err != nil {
+		return err
+	}
+	return yaml.NewEncoder(w).Encode(yamlValue(value))
+}
+
+func yamlValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case object:
+		m := make(yaml.MapSlice, len(v))
+		for i, member := range v {
+			m[i] = yaml.MapItem{Key: member.key, Value: yamlValue(member.value)}
+		}
+		return m
+	case []interface{}:
+		for i := range v {
+			v[i] = yamlValue(v[i])
+		}
+	}
+	return value
+}
+
+// XML encoder, every key an element under a response root and array items
+// item elements
+type XML struct{}
+
+func (XML) MediaTypes() []string { return []string{"application/xml", "text/xml"} }
+func (XML) ContentType() string  { return "application/xml; charset=utf-8" }
+
+func (XML) Encode(w io.Writer, body interface{}) error {
+	value, err := ordered(body)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	if err := writeXML(&buf, "response", value); err != nil {
+		return err
+	}
+
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+func writeXML(buf *bytes.Buffer, name string, value interface{}) error {
+	if value == nil {
+		buf.WriteString("<" + name + "/>")
+		return nil
+	}
+
+	buf.WriteString("<" + name + ">")
+
+	switch v := value.(type) {
+	case object:
+		for _, member := range v {
+			if err := writeXML(buf, member.key, member.value); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if err := writeXML(buf, "item", item); err != nil {
+				return err
+			}
+		}
+	default:
+		if err := xml.EscapeText(buf, []byte(fmt.Sprint(v))); err != nil {
+			return err
+		}
+	}
+
+	buf.WriteString("</" + name + ">")
+	return nil
+}
+
+// MsgPack encoder, keys named as in JSON
+type MsgPack struct{}
+
+func (MsgPack) MediaTypes() []string {
+	return []string{"application/msgpack", "application/x-msgpack"}
+}
+func (MsgPack) ContentType() string { return "application/msgpack" }
+
+func (MsgPack) Encode(w io.Writer, body interface{}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(b, &value); err != nil {
+		return err
+	}
+
+	h := &codec.MsgpackHandle{}
+	h.Canonical = true
+	return codec.NewEncoder(w, h).Encode(value)
+}
+
+// CSV encoder for bodies of structs with csv tags, in the name;climate;terrain
+// layout the importer reads; rows are flushed as they are written
+type CSV struct{}
+
+// flushEvery rows written to the client at a time
+const flushEvery = 100
+
+func (CSV) MediaTypes() []string { return []string{"text/csv"} }
+func (CSV) ContentType() string  { return "text/csv; charset=utf-8; header=present" }
+
+func (CSV) Encode(w io.Writer, body interface{}) error {
+	if c, ok := body.(Collection); ok {
+		body = c.Items()
+	}
+
+	rows := reflect.ValueOf(body)
+	for rows.Kind() == reflect.Ptr && !rows.IsNil() {
+		rows = rows.Elem()
+	}
+
+	if rows.Kind() == reflect.Struct {
+		single := reflect.MakeSlice(reflect.SliceOf(rows.Type()), 1, 1)
+		single.Index(0).Set(rows)
+		rows = single
+	}
+
+	if rows.Kind() != reflect.Slice || rows.Type().Elem().Kind() != reflect.Struct {
+		return ErrNotEncodable
+	}
+
+	var header []string
+	var fields []int
+	t := rows.Type().Elem()
+	for i := 0; i < t.NumField(); i++ {
+		if name := t.Field(i).Tag.Get("csv"); name != "" {
+			header = append(header, name)
+			fields = append(fields, i)
+		}
+	}
+
+	if len(fields) == 0 {
+		return ErrNotEncodable
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Comma = ';'
+
+	flush := func() error {
+		writer.Flush()
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+		return writer.Error()
+	}
+
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	record := make([]string, len(fields))
+	for i := 0; i < rows.Len(); i++ {
+		for j, field := range fields {
+			record[j] = cell(rows.Index(i).Field(field))
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+
+		if (i+1)%flushEvery == 0 {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return flush()
+}
+
+func cell(v reflect.Value) string {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// member of a JSON object, kept in order
+type member struct {
+	key   string
+	value interface{}
+}
+
+type object []member
+
+// ordered decodes the JSON representation of body keeping the order of the
+// keys, so every encoding names and orders them alike
+func ordered(body interface{}) (interface{}, error) {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	return decodeValue(d)
+}
+
+func decodeValue(d *json.Decoder) (interface{}, error) {
+	token, err := d.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		if t == '{' {
+			o := object{}
+			for d.More() {
+				key, err := d.Token()
+				if err != nil {
+					return nil, err
+				}
+
+				value, err := decodeValue(d)
+				if err != nil {
+					return nil, err
+				}
+
+				o = append(o, member{key: key.(string), value: value})
+			}
+			_, err := d.Token()
+			return o, err
+		}
+
+		list := []interface{}{}
+		for d.More() {
+			value, err := decodeValue(d)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err := d.Token()
+		return list, err
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i, nil
+		}
+		return t.Float64()
+	}
+
+	return token, nil
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ugorji/go/codec"
+)
+
+type terms []string
+
+func (t terms) String() string {
+	return strings.Join(t, ", ")
+}
+
+type world struct {
+	Name      string     `json:"name" csv:"name"`
+	Climate   terms      `json:"climate" csv:"climate"`
+	Terrain   terms      `json:"terrain" csv:"terrain"`
+	Films     int        `json:"totalFilms"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+}
+
+type envelope struct {
+	Data interface{} `json:"data"`
+}
+
+func (e envelope) Items() interface{} {
+	return e.Data
+}
+
+var worlds = &[]world{
+	{Name: "Alderaan", Climate: terms{"temperate"}, Terrain: terms{"grasslands", "mountains"}, Films: 2},
+	{Name: "Yavin IV", Climate: terms{"temperate", "tropical"}, Terrain: terms{"jungle"}},
+}
+
+func encode(t *testing.T, e Encoder, body interface{}) string {
+	var buf bytes.Buffer
+	if err := e.Encode(&buf, body); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestEncoders(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		assert.Equal(t, `[{"name":"Alderaan","climate":["temperate"],"terrain":["grasslands","mountains"],"totalFilms":2},`+
+			`{"name":"Yavin IV","climate":["temperate","tropical"],"terrain":["jungle"],"totalFilms":0}]`, encode(t, JSON{}, worlds))
+	})
+
+	t.Run("yaml keeps the json names and order", func(t *testing.T) {
+		assert.Equal(t, `data:
+- name: Alderaan
+  climate:
+  - temperate
+  terrain:
+  - grasslands
+  - mountains
+  totalFilms: 2
+`, encode(t, YAML{}, envelope{Data: (*worlds)[:1]}))
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<response><data><item><name>Alderaan</name><climate><item>temperate</item></climate>`+
+			`<terrain><item>grasslands</item><item>mountains</item></terrain><totalFilms>2</totalFilms></item></data>`+
+			`<next/><note>a &amp; b</note></response>`,
+			encode(t, XML{}, map[string]interface{}{"data": (*worlds)[:1], "next": nil, "note": "a & b"}))
+	})
+
+	t.Run("msgpack", func(t *testing.T) {
+		var decoded map[string]interface{}
+		err := codec.NewDecoderBytes([]byte(encode(t, MsgPack{}, (*worlds)[0])), &codec.MsgpackHandle{}).Decode(&decoded)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Alderaan", string(decoded["name"].([]byte)))
+		assert.EqualValues(t, 2, decoded["totalFilms"])
+	})
+
+	t.Run("csv in the importer layout", func(t *testing.T) {
+		want := "name;climate;terrain\nAlderaan;temperate;grasslands, mountains\nYavin IV;temperate, tropical;jungle\n"
+
+		assert.Equal(t, want, encode(t, CSV{}, worlds))
+		assert.Equal(t, want, encode(t, CSV{}, envelope{Data: worlds}), "unwraps collections")
+		assert.Equal(t, "name;climate;terrain\nAlderaan;temperate;grasslands, mountains\n", encode(t, CSV{}, &(*worlds)[0]))
+
+		alderaan := &(*worlds)[0]
+		assert.Equal(t, "name;climate;terrain\nAlderaan;temperate;grasslands, mountains\n", encode(t, CSV{}, &alderaan), "follows every pointer")
+	})
+
+	t.Run("csv refuses bodies without csv tags", func(t *testing.T) {
+		for _, body := range []interface{}{map[string]int{"purged": 2}, []string{"arid"}, struct{ Name string }{"Hoth"}} {
+			var buf bytes.Buffer
+
+			assert.Equal(t, ErrNotEncodable, CSV{}.Encode(&buf, body))
+			assert.Empty(t, buf.String())
+		}
+	})
+}
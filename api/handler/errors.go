@@ -1,7 +1,5 @@
 package handler
 
-import "log"
-
 // BadRequest HTTP 400
 type BadRequest struct {
 	Message string
@@ -18,7 +16,6 @@ type InternalServer struct {
 }
 
 func (i InternalServer) Error() string {
-	log.Print(i.Message)
 	return "internal server error"
 }
 
@@ -31,3 +28,76 @@ type NotFound struct {
 func (n NotFound) Error() string {
 	return n.Message
 }
+
+// Unauthorized HTTP 401
+type Unauthorized struct {
+	Message string
+}
+
+func (u Unauthorized) Error() string {
+	return u.Message
+}
+
+// Forbidden HTTP 403
+type Forbidden struct {
+	Message string
+}
+
+func (f Forbidden) Error() string {
+	return f.Message
+}
+
+// TooManyRequests HTTP 429
+type TooManyRequests struct {
+	Message string
+}
+
+func (t TooManyRequests) Error() string {
+	return t.Message
+}
+
+// PreconditionFailed HTTP 412
+type PreconditionFailed struct {
+	Message string
+}
+
+func (p PreconditionFailed) Error() string {
+	return p.Message
+}
+
+// NotAcceptable HTTP 406
+type NotAcceptable struct {
+	Message string
+}
+
+func (n NotAcceptable) Error() string {
+	return n.Message
+}
+
+// FailedDependency HTTP 424, an item left undone because another one of its
+// batch failed
+type FailedDependency struct {
+	Message string
+}
+
+func (f FailedDependency) Error() string {
+	return f.Message
+}
+
+// Conflict HTTP 409
+type Conflict struct {
+	Message string
+}
+
+func (c Conflict) Error() string {
+	return c.Message
+}
+
+// UnprocessableEntity HTTP 422
+type UnprocessableEntity struct {
+	Message string
+}
+
+func (u UnprocessableEntity) Error() string {
+	return u.Message
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AnyVersion no precondition was sent by the client
+const AnyVersion int64 = -1
+
+// ETag derived from the resource version and the media type it is answered
+// in: "3" for JSON, "3-csv" for CSV, so each representation has a tag of its own
+func ETag(version int64, media string) string {
+	tag := strconv.FormatInt(version, 10)
+	if r := representation(media); r != "" {
+		tag += "-" + r
+	}
+	return strconv.Quote(tag)
+}
+
+// representation suffix of the ETag of media, empty for JSON
+func representation(media string) string {
+	media = strings.TrimSpace(strings.SplitN(media, ";", 2)[0])
+	if media == "" || media == "application/json" {
+		return ""
+	}
+	return media[strings.LastIndex(media, "/")+1:]
+}
+
+// IfMatch returns the version expected by the If-Match header
+func IfMatch(c *gin.Context) (int64, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+
+	if header == "" || header == "*" {
+		return AnyVersion, nil
+	}
+
+	// a weak tag never matches a strong comparison
+	weak := strings.HasPrefix(header, "W/")
+
+	version, err := strconv.Unquote(strings.TrimPrefix(header, "W/"))
+	if err != nil {
+		return 0, BadRequest{Message: "If-Match is invalid"}
+	}
+
+	// the tag of any representation carries the version
+	v, err := strconv.ParseInt(strings.SplitN(version, "-", 2)[0], 10, 64)
+	if weak || err != nil || v < 0 {
+		return 0, PreconditionFailed{Message: "If-Match does not match any version"}
+	}
+
+	return v, nil
+}
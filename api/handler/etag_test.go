@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestETag(t *testing.T) {
+	assert.Equal(t, `"3"`, ETag(3, JSON{}.ContentType()))
+	assert.Equal(t, `"3-csv"`, ETag(3, CSV{}.ContentType()))
+	assert.Equal(t, `"3-msgpack"`, ETag(3, MsgPack{}.ContentType()))
+}
+
+func TestIfMatch(t *testing.T) {
+	type test struct {
+		name    string
+		header  string
+		want    int64
+		wantErr error
+	}
+
+	tests := []test{
+		{name: "when header is missing", want: AnyVersion},
+		{name: "when header is a wildcard", header: "*", want: AnyVersion},
+		{name: "when header has a version", header: `"3"`, want: 3},
+		{name: "when header is the tag of another representation", header: `"3-yaml"`, want: 3},
+		{name: "when header is weak", header: `W/"3"`, wantErr: PreconditionFailed{Message: "If-Match does not match any version"}},
+		{name: "when header is not a version", header: `"abc"`, wantErr: PreconditionFailed{Message: "If-Match does not match any version"}},
+		{name: "when header is a negative version", header: `"-3"`, wantErr: PreconditionFailed{Message: "If-Match does not match any version"}},
+		{name: "when header is malformed", header: `3`, wantErr: BadRequest{Message: "If-Match is invalid"}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request, _ = http.NewRequest("PUT", "/planets/1", nil)
+			if tt.header != "" {
+				c.Request.Header.Set("If-Match", tt.header)
+			}
+
+			version, err := IfMatch(c)
+
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.Equal(t, tt.want, version)
+			}
+		})
+	}
+}
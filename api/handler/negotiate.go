@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// encoders negotiated, the first one answers clients without a preference
+var encoders = []Encoder{JSON{}, YAML{}, XML{}, MsgPack{}, CSV{}}
+
+// Register adds an encoder, ahead of the built-in ones answering its media types
+func Register(e Encoder) {
+	encoders = append([]Encoder{e}, encoders...)
+}
+
+// MediaTypes the encoders answer, in order of registration
+func MediaTypes() []string {
+	var types []string
+	for _, e := range encoders {
+		types = append(types, e.MediaTypes()[0])
+	}
+	return types
+}
+
+type mediaRange struct {
+	media string
+	q     float64
+}
+
+func (r mediaRange) matches(e Encoder) bool {
+	for _, media := range e.MediaTypes() {
+		if r.media == "*/*" || r.media == media ||
+			strings.HasSuffix(r.media, "/*") && strings.HasPrefix(media, strings.TrimSuffix(r.media, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAccept returns the media ranges of an Accept header, the preferred first
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+
+	for _, part := range strings.Split(accept, ",") {
+		media, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+
+		ranges = append(ranges, mediaRange{media: media, q: q})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+	return ranges
+}
+
+// Acceptable encoders for an Accept header, the preferred first; every one
+// when the header is empty
+func Acceptable(accept string) []Encoder {
+	if strings.TrimSpace(accept) == "" {
+		return encoders
+	}
+
+	ranges := parseAccept(accept)
+
+	var acceptable []Encoder
+	seen := make([]bool, len(encoders))
+
+	for _, r := range ranges {
+		if r.q == 0 {
+			continue
+		}
+
+		for i, e := range encoders {
+			if seen[i] || !r.matches(e) || refused(ranges, e) {
+				continue
+			}
+			seen[i] = true
+			acceptable = append(acceptable, e)
+		}
+	}
+
+	return acceptable
+}
+
+// refused tells whether the client ruled e out with q=0
+func refused(ranges []mediaRange, e Encoder) bool {
+	for _, r := range ranges {
+		if r.q == 0 && r.media != "*/*" && r.matches(e) {
+			return true
+		}
+	}
+	return false
+}
+
+const encodersKey = "handler.encoders"
+
+// notAcceptable lists what the client may ask for instead
+func notAcceptable() NotAcceptable {
+	return NotAcceptable{Message: "accept one of " + strings.Join(MediaTypes(), ", ")}
+}
+
+// Negotiate answers 406 before the handler runs when no encoder matches the
+// Accept header, and keeps the acceptable ones for ResponseSuccess
+func Negotiate(c *gin.Context) {
+	acceptable := Acceptable(c.GetHeader("Accept"))
+
+	if len(acceptable) == 0 {
+		ResponseError(notAcceptable(), c)
+		c.Abort()
+		return
+	}
+
+	c.Set(encodersKey, acceptable)
+	c.Next()
+}
+
+// Preferred encoder of the request, the one ResponseSuccess tries first
+func Preferred(c *gin.Context) Encoder {
+	if acceptable := negotiated(c); len(acceptable) > 0 {
+		return acceptable[0]
+	}
+	return JSON{}
+}
+
+// negotiated encoders of the request, the preferred first
+func negotiated(c *gin.Context) []Encoder {
+	if acceptable, ok := c.Get(encodersKey); ok {
+		return acceptable.([]Encoder)
+	}
+
+	if c.Request == nil {
+		return encoders
+	}
+
+	return Acceptable(c.GetHeader("Accept"))
+}
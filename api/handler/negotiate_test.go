@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func names(encoders []Encoder) []string {
+	var names []string
+	for _, e := range encoders {
+		names = append(names, e.MediaTypes()[0])
+	}
+	return names
+}
+
+func TestAcceptable(t *testing.T) {
+	type test struct {
+		accept string
+		want   []string
+	}
+
+	all := []string{"application/json", "application/yaml", "application/xml", "application/msgpack", "text/csv"}
+
+	tests := []test{
+		{accept: "", want: all},
+		{accept: "*/*", want: all},
+		{accept: "text/csv", want: []string{"text/csv"}},
+		{accept: "application/x-msgpack", want: []string{"application/msgpack"}},
+		{accept: "text/*", want: []string{"application/yaml", "application/xml", "text/csv"}},
+		{accept: "application/json;q=0.5, text/csv", want: []string{"text/csv", "application/json"}},
+		{accept: "text/html, application/xhtml+xml, */*;q=0.8", want: all},
+		{accept: "*/*, application/json;q=0", want: all[1:]},
+		{accept: "image/png", want: nil},
+		{accept: "text/csv;q=0", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			assert.Equal(t, tt.want, names(Acceptable(tt.accept)))
+		})
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	router := gin.New()
+	router.Use(Negotiate)
+	router.GET("/worlds", func(c *gin.Context) { ResponseSuccess(200, worlds, c) })
+	router.GET("/purge", func(c *gin.Context) { ResponseSuccess(200, gin.H{"purged": 2}, c) })
+
+	type test struct {
+		name            string
+		target          string
+		accept          string
+		wantStatusCode  int
+		wantContentType string
+		wantBody        string
+	}
+
+	tests := []test{
+		{
+			name:            "json without preference",
+			target:          "/purge",
+			wantStatusCode:  200,
+			wantContentType: "application/json; charset=utf-8",
+			wantBody:        `{"purged":2}`,
+		},
+		{
+			name:            "csv",
+			target:          "/worlds",
+			accept:          "text/csv",
+			wantStatusCode:  200,
+			wantContentType: "text/csv; charset=utf-8; header=present",
+			wantBody:        "name;climate;terrain\nAlderaan;temperate;grasslands, mountains\nYavin IV;temperate, tropical;jungle\n",
+		},
+		{
+			name:            "next preference when csv cannot represent the body",
+			target:          "/purge",
+			accept:          "text/csv, application/yaml;q=0.9",
+			wantStatusCode:  200,
+			wantContentType: "application/yaml; charset=utf-8",
+			wantBody:        "purged: 2\n",
+		},
+		{
+			name:            "json when no preference can represent the body",
+			target:          "/purge",
+			accept:          "text/csv",
+			wantStatusCode:  200,
+			wantContentType: "application/json; charset=utf-8",
+			wantBody:        `{"purged":2}`,
+		},
+		{
+			name:            "406 before the handler runs",
+			target:          "/worlds",
+			accept:          "image/png",
+			wantStatusCode:  406,
+			wantContentType: "application/json; charset=utf-8",
+			wantBody:        `{"error":"accept one of application/json, application/yaml, application/xml, application/msgpack, text/csv"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			req.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.Equal(t, tt.wantContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	saved := encoders
+	defer func() { encoders = saved }()
+
+	Register(JSON{})
+
+	assert.Equal(t, "application/json", names(Acceptable("application/*"))[0])
+	assert.Len(t, encoders, len(saved)+1)
+}
@@ -1,35 +1,98 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"reflect"
+	"star-wars/logger"
+	"star-wars/requestid"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
-// ResponseSuccess creates payload
+// ResponseSuccess creates payload in the media type the client prefers among
+// those that can represent body, JSON when none can: the handler is done by
+// now, and its work must not be lost to a 406
 func ResponseSuccess(status int, body interface{}, c *gin.Context) {
-	if body != nil {
-		c.JSON(status, body)
-	} else {
+	if body == nil {
 		c.Writer.WriteHeader(http.StatusOK)
+		return
+	}
+
+	c.Writer.Header().Add("Vary", "Accept")
+
+	for _, e := range append(negotiated(c), JSON{}) {
+		c.Header("Content-Type", e.ContentType())
+		c.Status(status)
+
+		err := e.Encode(c.Writer, body)
+		if err == ErrNotEncodable {
+			continue
+		}
+
+		// the status is out already, all that is left is to tell
+		if err != nil && c.Request != nil {
+			logger.FromContext(c.Request.Context()).Error("response encoding failed", zap.Error(err))
+		}
+		return
+	}
+}
+
+// Status of the response for err
+func Status(err error) int {
+	switch reflect.TypeOf(err).String() {
+	case "handler.BadRequest":
+		return http.StatusBadRequest
+	case "handler.Unauthorized":
+		return http.StatusUnauthorized
+	case "handler.Forbidden":
+		return http.StatusForbidden
+	case "handler.NotFound":
+		return http.StatusNotFound
+	case "handler.NotAcceptable":
+		return http.StatusNotAcceptable
+	case "handler.Conflict":
+		return http.StatusConflict
+	case "handler.PreconditionFailed":
+		return http.StatusPreconditionFailed
+	case "handler.UnprocessableEntity":
+		return http.StatusUnprocessableEntity
+	case "handler.FailedDependency":
+		return http.StatusFailedDependency
+	case "handler.TooManyRequests":
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
 	}
 }
 
 // ResponseError creates payload
 func ResponseError(err error, c *gin.Context) {
-	typeError := reflect.TypeOf(err).String()
-	status := http.StatusInternalServerError
+	status := Status(err)
 	message := err.Error()
 
-	switch typeError {
-	case "handler.BadRequest":
-		status = http.StatusBadRequest
-	case "handler.NotFound":
-		status = http.StatusNotFound
-	default:
-		status = http.StatusInternalServerError
+	body := gin.H{"error": message}
+
+	if c.Request != nil {
+		ctx := c.Request.Context()
+
+		if status == http.StatusInternalServerError {
+			logger.FromContext(ctx).Error(message, zap.Error(Cause(err)))
+		}
+
+		if id := requestid.From(ctx); id != "" {
+			body["requestId"] = id
+		}
 	}
 
-	c.JSON(status, gin.H{"error": message})
+	c.JSON(status, body)
+}
+
+// Cause keeps the detail InternalServer hides from the client
+func Cause(err error) error {
+	if i, ok := err.(InternalServer); ok {
+		return errors.New(i.Message)
+	}
+	return err
 }
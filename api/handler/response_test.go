@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"net/http"
 	"net/http/httptest"
+	"star-wars/requestid"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -56,3 +58,55 @@ func TestResponseError_NotFound(t *testing.T) {
 	assert.Equal(t, 404, w.Code)
 	assert.Equal(t, "{\"error\":\"not found error\"}", w.Body.String())
 }
+
+func TestResponseError_Unauthorized(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	ResponseError(Unauthorized{Message: "authentication required"}, c)
+
+	assert.Equal(t, 401, w.Code)
+	assert.Equal(t, "{\"error\":\"authentication required\"}", w.Body.String())
+}
+
+func TestResponseError_Forbidden(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	ResponseError(Forbidden{Message: "requires the editor role"}, c)
+
+	assert.Equal(t, 403, w.Code)
+	assert.Equal(t, "{\"error\":\"requires the editor role\"}", w.Body.String())
+}
+
+func TestResponseError_TooManyRequests(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	ResponseError(TooManyRequests{Message: "rate limit exceeded"}, c)
+
+	assert.Equal(t, 429, w.Code)
+	assert.Equal(t, "{\"error\":\"rate limit exceeded\"}", w.Body.String())
+}
+
+func TestResponseError_PreconditionFailed(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	ResponseError(PreconditionFailed{Message: "planet was modified by another request"}, c)
+
+	assert.Equal(t, 412, w.Code)
+	assert.Equal(t, "{\"error\":\"planet was modified by another request\"}", w.Body.String())
+}
+
+func TestResponseError_RequestID(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/planets/1", nil)
+	c.Request = c.Request.WithContext(requestid.With(c.Request.Context(), "req-1"))
+
+	ResponseError(NotFound{Message: "planet not found"}, c)
+
+	assert.Equal(t, 404, w.Code)
+	assert.Equal(t, "{\"error\":\"planet not found\",\"requestId\":\"req-1\"}", w.Body.String())
+}
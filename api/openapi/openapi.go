@@ -0,0 +1,276 @@
+// Package openapi documents routes as they are registered, so the spec
+// served to clients is the one the router actually answers
+package openapi
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Version of the OpenAPI specification the documents follow
+const Version = "3.0.3"
+
+// Document root of a spec
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers,omitempty"`
+	Tags       []Tag                `json:"tags,omitempty"`
+	Security   []Requirement        `json:"security,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+// Info about the API
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Server the paths are relative to
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// Tag groups operations
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// Requirement security schemes accepted together, an empty one allows anonymous calls
+type Requirement map[string][]string
+
+// PathItem operations of a path
+type PathItem struct {
+	Servers []Server   `json:"servers,omitempty"`
+	Get     *Operation `json:"get,omitempty"`
+	Put     *Operation `json:"put,omitempty"`
+	Post    *Operation `json:"post,omitempty"`
+	Delete  *Operation `json:"delete,omitempty"`
+	Patch   *Operation `json:"patch,omitempty"`
+}
+
+// slot of the method, nil for methods the spec does not model
+func (p *PathItem) slot(method string) **Operation {
+	switch method {
+	case http.MethodGet:
+		return &p.Get
+	case http.MethodPut:
+		return &p.Put
+	case http.MethodPost:
+		return &p.Post
+	case http.MethodDelete:
+		return &p.Delete
+	case http.MethodPatch:
+		return &p.Patch
+	}
+	return nil
+}
+
+// Operation of a route
+type Operation struct {
+	Tags        []string             `json:"tags,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+}
+
+// Parameter of an operation, or a reference to a shared one
+type Parameter struct {
+	Ref         string      `json:"$ref,omitempty"`
+	Name        string      `json:"name,omitempty"`
+	In          string      `json:"in,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Required    bool        `json:"required,omitempty"`
+	Example     interface{} `json:"example,omitempty"`
+	Schema      *Schema     `json:"schema,omitempty"`
+}
+
+// RequestBody of an operation
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// MediaType payload of a body
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// Response of an operation, or a reference to a shared one
+type Response struct {
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Headers     map[string]*Header   `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// Header of a response, or a reference to a shared one
+type Header struct {
+	Ref         string  `json:"$ref,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+}
+
+// Components shared by the operations
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas,omitempty"`
+	Responses       map[string]*Response       `json:"responses,omitempty"`
+	Parameters      map[string]*Parameter      `json:"parameters,omitempty"`
+	Headers         map[string]*Header         `json:"headers,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme credentials the API accepts
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Description  string `json:"description,omitempty"`
+	Name         string `json:"name,omitempty"`
+	In           string `json:"in,omitempty"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// New returns an empty document
+func New(info Info, servers ...Server) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Servers: servers,
+		Paths:   map[string]*PathItem{},
+		Components: Components{
+			Schemas:         map[string]*Schema{},
+			Responses:       map[string]*Response{},
+			Parameters:      map[string]*Parameter{},
+			Headers:         map[string]*Header{},
+			SecuritySchemes: map[string]*SecurityScheme{},
+		},
+	}
+}
+
+// Operation documented for method and path, nil when there is none
+func (d *Document) Operation(method, path string) *Operation {
+	item, ok := d.Paths[path]
+	if !ok {
+		return nil
+	}
+
+	slot := item.slot(method)
+	if slot == nil {
+		return nil
+	}
+
+	return *slot
+}
+
+// add documents op, completing the path parameters it does not describe
+func (d *Document) add(method, path string, op Operation, servers []Server) {
+	item, ok := d.Paths[path]
+	if !ok {
+		item = &PathItem{Servers: servers}
+		d.Paths[path] = item
+	}
+
+	slot := item.slot(method)
+	if slot == nil {
+		panic("openapi: method " + method + " can not be documented")
+	}
+	if *slot != nil {
+		panic("openapi: " + method + " " + path + " documented twice")
+	}
+
+	params := append([]Parameter{}, op.Parameters...)
+
+	for _, name := range pathParams(path) {
+		if !hasParam(params, name) {
+			params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: String()})
+		}
+	}
+
+	if len(params) > 0 {
+		op.Parameters = params
+	}
+
+	*slot = &op
+}
+
+// Path converts a gin path to an OpenAPI one: /planets/:id becomes /planets/{id}
+// and the custom method /planets%3Abatch /planets:batch
+func Path(path string) string {
+	segments := strings.Split(path, "/")
+
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+
+	return strings.ReplaceAll(strings.Join(segments, "/"), escapedColon, ":")
+}
+
+func pathParams(path string) []string {
+	var names []string
+
+	for _, s := range strings.Split(path, "/") {
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+			names = append(names, s[1:len(s)-1])
+		}
+	}
+
+	return names
+}
+
+func hasParam(params []Parameter, name string) bool {
+	for _, p := range params {
+		if p.In == "path" && p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// JSON content of a body or response
+func JSON(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+// Body required JSON request body
+func Body(schema *Schema) *RequestBody {
+	return &RequestBody{Required: true, Content: JSON(schema)}
+}
+
+// Reply JSON response, without content when schema is nil
+func Reply(description string, schema *Schema) *Response {
+	r := &Response{Description: description}
+	if schema != nil {
+		r.Content = JSON(schema)
+	}
+	return r
+}
+
+// ResponseRef points to a shared response
+func ResponseRef(name string) *Response {
+	return &Response{Ref: "#/components/responses/" + name}
+}
+
+// ParameterRef points to a shared parameter
+func ParameterRef(name string) Parameter {
+	return Parameter{Ref: "#/components/parameters/" + name}
+}
+
+// HeaderRef points to a shared header
+func HeaderRef(name string) *Header {
+	return &Header{Ref: "#/components/headers/" + name}
+}
+
+// Query optional query parameter
+func Query(name, description string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
@@ -0,0 +1,87 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPath(t *testing.T) {
+	assert.Equal(t, "/planets", Path("/planets"))
+	assert.Equal(t, "/planets/{id}/restore", Path("/planets/:id/restore"))
+	assert.Equal(t, "/files/{path}", Path("/files/*path"))
+	assert.Equal(t, "/planets:batch", Path(CustomMethod("/planets", "batch")))
+}
+
+func TestRouter(t *testing.T) {
+	t.Run("registers and documents the route", func(t *testing.T) {
+		d := New(Info{Title: "test", Version: "1"})
+		router := gin.New()
+
+		NewRouter(router, d).GET("/planets/:id", Operation{Summary: "get"}, func(c *gin.Context) {
+			c.String(http.StatusOK, c.Param("id"))
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/planets/42", nil))
+		assert.Equal(t, "42", w.Body.String())
+
+		op := d.Operation(http.MethodGet, "/planets/{id}")
+		assert.Equal(t, "get", op.Summary)
+		assert.Equal(t, []Parameter{{Name: "id", In: "path", Required: true, Schema: String()}}, op.Parameters)
+		assert.Nil(t, d.Operation(http.MethodPost, "/planets/{id}"))
+	})
+
+	t.Run("keeps described path parameters", func(t *testing.T) {
+		d := New(Info{Title: "test", Version: "1"})
+		id := Parameter{Name: "id", In: "path", Required: true, Description: "Planet ID", Schema: String()}
+
+		NewRouter(gin.New(), d).DELETE("/planets/:id", Operation{Parameters: []Parameter{id}}, func(*gin.Context) {})
+
+		assert.Equal(t, []Parameter{id}, d.Operation(http.MethodDelete, "/planets/{id}").Parameters)
+	})
+
+	t.Run("documents in every document with the servers given", func(t *testing.T) {
+		v1, v2 := New(Info{Title: "test", Version: "1"}), New(Info{Title: "test", Version: "2"})
+
+		NewRouter(gin.New(), v1, v2).Servers(Server{URL: "/"}).GET("/livez", Operation{}, func(*gin.Context) {})
+
+		for _, d := range []*Document{v1, v2} {
+			assert.NotNil(t, d.Operation(http.MethodGet, "/livez"))
+			assert.Equal(t, []Server{{URL: "/"}}, d.Paths["/livez"].Servers)
+		}
+	})
+
+	t.Run("aliases are not documented", func(t *testing.T) {
+		router := gin.New()
+
+		NewRouter(router).GET("/planets", Operation{}, func(*gin.Context) {})
+
+		assert.Len(t, router.Routes(), 1)
+	})
+
+	t.Run("documenting a route twice panics", func(t *testing.T) {
+		d := New(Info{Title: "test", Version: "1"})
+		NewRouter(gin.New(), d).GET("/planets", Operation{}, func(*gin.Context) {})
+
+		assert.Panics(t, func() {
+			NewRouter(gin.New(), d).GET("/planets", Operation{}, func(*gin.Context) {})
+		})
+	})
+}
+
+func TestServe(t *testing.T) {
+	d := New(Info{Title: "test", Version: "1"}, Server{URL: "/v1"})
+
+	router := gin.New()
+	router.GET("/openapi.json", Serve(d))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"openapi":"3.0.3","info":{"title":"test","version":"1"},"servers":[{"url":"/v1"}],"paths":{},"components":{}}`, w.Body.String())
+}
@@ -0,0 +1,90 @@
+package openapi
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Router registers gin routes and documents them at once
+type Router struct {
+	routes  gin.IRoutes
+	docs    []*Document
+	servers []Server
+}
+
+// NewRouter registers on routes and documents in docs; without docs routes
+// are only registered, as for aliases of documented ones
+func NewRouter(routes gin.IRoutes, docs ...*Document) *Router {
+	return &Router{routes: routes, docs: docs}
+}
+
+// Servers returns a router documenting its paths under servers instead of
+// the document ones, for routes mounted outside the documents base path
+func (r *Router) Servers(servers ...Server) *Router {
+	return &Router{routes: r.routes, docs: r.docs, servers: servers}
+}
+
+// Handle registers handlers for method and path and documents op
+func (r *Router) Handle(method, path string, op Operation, handlers ...gin.HandlerFunc) {
+	r.routes.Handle(method, path, handlers...)
+
+	for _, d := range r.docs {
+		d.add(method, Path(path), op, r.servers)
+	}
+}
+
+// GET registers and documents a GET route
+func (r *Router) GET(path string, op Operation, handlers ...gin.HandlerFunc) {
+	r.Handle(http.MethodGet, path, op, handlers...)
+}
+
+// POST registers and documents a POST route
+func (r *Router) POST(path string, op Operation, handlers ...gin.HandlerFunc) {
+	r.Handle(http.MethodPost, path, op, handlers...)
+}
+
+// PUT registers and documents a PUT route
+func (r *Router) PUT(path string, op Operation, handlers ...gin.HandlerFunc) {
+	r.Handle(http.MethodPut, path, op, handlers...)
+}
+
+// DELETE registers and documents a DELETE route
+func (r *Router) DELETE(path string, op Operation, handlers ...gin.HandlerFunc) {
+	r.Handle(http.MethodDelete, path, op, handlers...)
+}
+
+// Serve answers d as JSON
+func Serve(d *Document) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, d)
+	}
+}
+
+// escapedColon stands for the colon of custom methods in gin paths, where a
+// colon starts a parameter
+const escapedColon = "%3A"
+
+// CustomMethod gin path of the custom method verb of path: /planets:batch,
+// which gin cannot register next to /planets/:id
+func CustomMethod(path, verb string) string {
+	return path + escapedColon + verb
+}
+
+// CustomMethods routes requests for custom methods again with the colon
+// escaped as CustomMethod registers it; it must run before any other middleware
+func CustomMethods(engine *gin.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+
+		if c.FullPath() != "" || !strings.Contains(path, ":") {
+			c.Next()
+			return
+		}
+
+		c.Request.URL.Path = strings.ReplaceAll(path, ":", escapedColon)
+		engine.HandleContext(c)
+		c.Abort()
+	}
+}
@@ -0,0 +1,53 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomMethods(t *testing.T) {
+	router := gin.New()
+	router.Use(CustomMethods(router))
+
+	calls := 0
+	router.Use(func(c *gin.Context) {
+		calls++
+		c.Next()
+	})
+
+	answer := func(c *gin.Context) { c.String(http.StatusOK, c.FullPath()+" "+c.Param("id")) }
+	router.POST("/v1/planets", answer)
+	router.POST("/v1/planets/:id/restore", answer)
+	router.POST(CustomMethod("/v1/planets", "batch"), answer)
+
+	type test struct {
+		target         string
+		wantStatusCode int
+		wantBody       string
+	}
+
+	tests := []test{
+		{target: "/v1/planets:batch", wantStatusCode: 200, wantBody: "/v1/planets%3Abatch "},
+		{target: "/v1/planets%3Abatch", wantStatusCode: 200, wantBody: "/v1/planets%3Abatch "},
+		{target: "/v1/planets", wantStatusCode: 200, wantBody: "/v1/planets "},
+		{target: "/v1/planets/a:b/restore", wantStatusCode: 200, wantBody: "/v1/planets/:id/restore a:b"},
+		{target: "/v1/planets:purge", wantStatusCode: 404, wantBody: "404 page not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			calls = 0
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tt.target, nil))
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.Equal(t, tt.wantBody, w.Body.String())
+			assert.Equal(t, 1, calls, "the other middlewares run once")
+		})
+	}
+}
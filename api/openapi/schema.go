@@ -0,0 +1,175 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Schema of a value, or a reference to a shared one
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Example              interface{}        `json:"example,omitempty"`
+}
+
+// String schema
+func String() *Schema {
+	return &Schema{Type: "string"}
+}
+
+// DateTime RFC 3339 string schema
+func DateTime() *Schema {
+	return &Schema{Type: "string", Format: "date-time"}
+}
+
+// Integer schema
+func Integer() *Schema {
+	return &Schema{Type: "integer"}
+}
+
+// Boolean schema
+func Boolean() *Schema {
+	return &Schema{Type: "boolean"}
+}
+
+// Enum string schema accepting values only
+func Enum(values ...string) *Schema {
+	s := String()
+	for _, v := range values {
+		s.Enum = append(s.Enum, v)
+	}
+	return s
+}
+
+// Array schema of items
+func Array(items *Schema) *Schema {
+	return &Schema{Type: "array", Items: items}
+}
+
+// Object schema with properties, the required ones named
+func Object(properties map[string]*Schema, required ...string) *Schema {
+	return &Schema{Type: "object", Properties: properties, Required: required}
+}
+
+// Ref points to a shared schema
+func Ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Schema of v derived from its json tags; named structs are shared in the
+// components and referenced
+func (d *Document) Schema(v interface{}) *Schema {
+	return d.reflect(reflect.TypeOf(v))
+}
+
+func (d *Document) reflect(t reflect.Type) *Schema {
+	if t == timeType {
+		return DateTime()
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return d.reflect(t.Elem())
+	case reflect.String:
+		return String()
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Integer()
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return Array(d.reflect(t.Elem()))
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.reflect(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return d.object(t)
+		}
+
+		if _, ok := d.Components.Schemas[t.Name()]; !ok {
+			// reserve the name first, so recursive types end in a reference
+			d.Components.Schemas[t.Name()] = &Schema{}
+			d.Components.Schemas[t.Name()] = d.object(t)
+		}
+
+		return Ref(t.Name())
+	}
+
+	return &Schema{}
+}
+
+func (d *Document) object(t reflect.Type) *Schema {
+	s := Object(map[string]*Schema{})
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+
+		tag := strings.Split(f.Tag.Get("json"), ",")
+		name := tag[0]
+		if name == "-" && len(tag) == 1 {
+			continue
+		}
+
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for k, v := range d.object(embedded).Properties {
+					s.Properties[k] = v
+				}
+				continue
+			}
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		property := d.reflect(f.Type)
+
+		// encoding/json writes null for nil values it does not omit
+		if property.Ref == "" && nullable(f.Type) && !omitempty(tag[1:]) {
+			property.Nullable = true
+		}
+
+		s.Properties[name] = property
+	}
+
+	return s
+}
+
+func nullable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		return true
+	}
+	return false
+}
+
+func omitempty(options []string) bool {
+	for _, o := range options {
+		if o == "omitempty" {
+			return true
+		}
+	}
+	return false
+}
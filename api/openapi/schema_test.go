@@ -0,0 +1,50 @@
+package openapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type moon struct {
+	Name string `json:"name"`
+}
+
+type base struct {
+	ID string `json:"id"`
+}
+
+type world struct {
+	base
+	Name      string            `json:"name"`
+	Climate   []string          `json:"climate"`
+	Films     int               `json:"films,omitempty"`
+	Moons     []moon            `json:"moons,omitempty"`
+	Orbit     *world            `json:"orbit,omitempty"`
+	CreatedAt *time.Time        `json:"createdAt,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Secret    string            `json:"-"`
+	internal  string
+}
+
+func TestSchema(t *testing.T) {
+	d := New(Info{Title: "test", Version: "1"})
+
+	assert.Equal(t, Ref("world"), d.Schema(world{}))
+	assert.Equal(t, Array(String()), d.Schema([]string{}))
+
+	assert.Equal(t, Object(map[string]*Schema{
+		"id":        String(),
+		"name":      String(),
+		"climate":   {Type: "array", Items: String(), Nullable: true},
+		"films":     Integer(),
+		"moons":     Array(Ref("moon")),
+		"orbit":     Ref("world"),
+		"createdAt": DateTime(),
+		"labels":    {Type: "object", AdditionalProperties: String()},
+	}), d.Components.Schemas["world"])
+
+	assert.Equal(t, Object(map[string]*Schema{"name": String()}), d.Components.Schemas["moon"])
+	assert.NotContains(t, d.Components.Schemas, "base")
+}
@@ -0,0 +1,323 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"mime"
+	"net/http"
+	"star-wars/api/handler"
+	"star-wars/logger"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Validate answers 400 to requests d does not allow and logs the responses
+// that stray from it. Routes are looked up without prefix, the base path of
+// d. Meant for development: it buffers every body it checks.
+func Validate(d *Document, prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		op := d.Operation(c.Request.Method, Path(strings.TrimPrefix(c.FullPath(), prefix)))
+		if op == nil {
+			c.Next()
+			return
+		}
+
+		if err := d.request(op, c.Request); err != nil {
+			handler.ResponseError(handler.BadRequest{Message: err.Error()}, c)
+			c.Abort()
+			return
+		}
+
+		w := &recorder{ResponseWriter: c.Writer}
+		c.Writer = w
+
+		c.Next()
+
+		if err := d.response(op, w.Status(), w.Header().Get("Content-Type"), w.body.Bytes()); err != nil {
+			logger.FromContext(c.Request.Context()).Error(
+				"response does not match the spec",
+				zap.String("method", c.Request.Method),
+				zap.String("route", c.FullPath()),
+				zap.Int("status", w.Status()),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// recorder keeps a copy of the body written
+type recorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *recorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+// request checks the parameters and body of r against op
+func (d *Document) request(op *Operation, r *http.Request) error {
+	query := r.URL.Query()
+
+	for _, p := range op.Parameters {
+		if p.Ref != "" {
+			shared, ok := d.Components.Parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]
+			if !ok {
+				return fmt.Errorf("parameter %s is not defined", p.Ref)
+			}
+			p = *shared
+		}
+
+		var value string
+		var present bool
+
+		switch p.In {
+		case "query":
+			_, present = query[p.Name]
+			value = query.Get(p.Name)
+		case "header":
+			value = r.Header.Get(p.Name)
+			present = value != ""
+		default:
+			// gin already matched the path
+			continue
+		}
+
+		if !present {
+			if p.Required {
+				return fmt.Errorf("%s %s is required", p.In, p.Name)
+			}
+			continue
+		}
+
+		if err := d.param(p.Schema, value, p.In+" "+p.Name); err != nil {
+			return err
+		}
+	}
+
+	if op.RequestBody == nil {
+		return nil
+	}
+
+	raw, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return errors.New("body is invalid")
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(raw))
+
+	if len(bytes.TrimSpace(raw)) == 0 {
+		if op.RequestBody.Required {
+			return errors.New("body is required")
+		}
+		return nil
+	}
+
+	media, ok := op.RequestBody.Content[mediaType(r.Header.Get("Content-Type"))]
+	if !ok {
+		return fmt.Errorf("content type %q is not accepted", r.Header.Get("Content-Type"))
+	}
+
+	// uploads are left to the handler, as the bodies of other types in responses
+	if mediaType(r.Header.Get("Content-Type")) != "application/json" {
+		return nil
+	}
+
+	return d.decode(media.Schema, raw, "body")
+}
+
+// response checks status and body against op
+func (d *Document) response(op *Operation, status int, contentType string, body []byte) error {
+	r, ok := op.Responses[strconv.Itoa(status)]
+	if !ok {
+		return fmt.Errorf("status %d is not documented", status)
+	}
+
+	if r.Ref != "" {
+		r, ok = d.Components.Responses[strings.TrimPrefix(r.Ref, "#/components/responses/")]
+		if !ok {
+			return fmt.Errorf("response of status %d is not defined", status)
+		}
+	}
+
+	if len(body) == 0 {
+		return nil
+	}
+
+	if len(r.Content) == 0 {
+		return fmt.Errorf("status %d has no documented body", status)
+	}
+
+	media, ok := r.Content[mediaType(contentType)]
+	if !ok {
+		return fmt.Errorf("content type %q is not documented", contentType)
+	}
+
+	if mediaType(contentType) != "application/json" {
+		return nil
+	}
+
+	return d.decode(media.Schema, body, "response")
+}
+
+func mediaType(contentType string) string {
+	media, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+	return media
+}
+
+func (d *Document) decode(s *Schema, raw []byte, path string) error {
+	var value interface{}
+
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return fmt.Errorf("%s is not valid JSON", path)
+	}
+
+	return d.validate(s, value, path)
+}
+
+// param checks a query or header value, typed from the text
+func (d *Document) param(s *Schema, value, path string) error {
+	if s == nil {
+		return nil
+	}
+
+	switch s.Type {
+	case "integer":
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("%s must be an integer", path)
+		}
+		return nil
+	case "number":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("%s must be a number", path)
+		}
+		return nil
+	case "boolean":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%s must be a boolean", path)
+		}
+		return nil
+	}
+
+	return d.validate(s, value, path)
+}
+
+// validate checks a value decoded from JSON against s
+func (d *Document) validate(s *Schema, v interface{}, path string) error {
+	if s == nil {
+		return nil
+	}
+
+	if s.Ref != "" {
+		shared, ok := d.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+		if !ok {
+			return fmt.Errorf("%s: schema %s is not defined", path, s.Ref)
+		}
+		return d.validate(shared, v, path)
+	}
+
+	if v == nil {
+		if s.Nullable || (s.Type == "" && len(s.OneOf) == 0) {
+			return nil
+		}
+		return fmt.Errorf("%s must not be null", path)
+	}
+
+	if len(s.OneOf) > 0 {
+		matches := 0
+		for _, one := range s.OneOf {
+			if d.validate(one, v, path) == nil {
+				matches++
+			}
+		}
+		if matches != 1 {
+			return fmt.Errorf("%s must match exactly one schema", path)
+		}
+	}
+
+	switch s.Type {
+	case "object":
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s must be an object", path)
+		}
+
+		for _, name := range s.Required {
+			if _, ok := m[name]; !ok {
+				return fmt.Errorf("%s.%s is required", path, name)
+			}
+		}
+
+		for name, value := range m {
+			property, ok := s.Properties[name]
+			if !ok {
+				property = s.AdditionalProperties
+			}
+			if err := d.validate(property, value, path+"."+name); err != nil {
+				return err
+			}
+		}
+	case "array":
+		a, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s must be an array", path)
+		}
+
+		for i, item := range a {
+			if err := d.validate(s.Items, item, path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s must be a string", path)
+		}
+
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				return fmt.Errorf("%s must be an RFC 3339 date-time", path)
+			}
+		}
+	case "integer":
+		n, ok := v.(float64)
+		if !ok || n != math.Trunc(n) {
+			return fmt.Errorf("%s must be an integer", path)
+		}
+	case "number":
+		if _, ok := v.(float64); !ok {
+			return fmt.Errorf("%s must be a number", path)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s must be a boolean", path)
+		}
+	}
+
+	if len(s.Enum) > 0 {
+		for _, e := range s.Enum {
+			if e == v {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s must be one of %v", path, s.Enum)
+	}
+
+	return nil
+}
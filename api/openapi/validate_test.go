@@ -0,0 +1,175 @@
+package openapi
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func planets() *Document {
+	d := New(Info{Title: "test", Version: "1"})
+
+	d.Components.Schemas["Planet"] = Object(map[string]*Schema{
+		"name":    String(),
+		"climate": {OneOf: []*Schema{Array(String()), String()}},
+		"films":   Integer(),
+	}, "name")
+	d.Components.Parameters["IfMatch"] = &Parameter{Name: "If-Match", In: "header", Schema: String()}
+	d.Components.Responses["BadRequest"] = Reply("Bad request", Object(map[string]*Schema{"error": String()}))
+
+	d.add(http.MethodGet, "/planets", Operation{
+		Parameters: []Parameter{
+			Query("limit", "", Integer()),
+			Query("action", "", Enum("create", "delete")),
+			Query("from", "", DateTime()),
+		},
+		Responses: map[string]*Response{
+			"200": Reply("Ok", Array(Ref("Planet"))),
+			"400": ResponseRef("BadRequest"),
+		},
+	}, nil)
+
+	d.add(http.MethodPost, "/planets", Operation{
+		Parameters:  []Parameter{ParameterRef("IfMatch")},
+		RequestBody: Body(Ref("Planet")),
+		Responses: map[string]*Response{
+			"201": Reply("Created", Ref("Planet")),
+			"400": ResponseRef("BadRequest"),
+		},
+	}, nil)
+
+	d.add(http.MethodPost, "/imports", Operation{
+		RequestBody: &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"multipart/form-data": {Schema: Object(map[string]*Schema{"file": String()}, "file")}},
+		},
+		Responses: map[string]*Response{"202": Reply("Accepted", nil)},
+	}, nil)
+
+	d.add(http.MethodDelete, "/planets/{id}", Operation{
+		Responses: map[string]*Response{"200": Reply("Ok", nil)},
+	}, nil)
+
+	return d
+}
+
+func TestValidate(t *testing.T) {
+	router := gin.New()
+	v1 := router.Group("/v1", Validate(planets(), "/v1"))
+
+	var received string
+	v1.POST("/planets", func(c *gin.Context) {
+		body, _ := ioutil.ReadAll(c.Request.Body)
+		received = string(body)
+		c.Status(http.StatusCreated)
+	})
+	v1.GET("/planets", func(c *gin.Context) {
+		c.JSON(http.StatusOK, []gin.H{})
+	})
+	v1.GET("/undocumented", func(c *gin.Context) {
+		c.Status(http.StatusTeapot)
+	})
+
+	type test struct {
+		name   string
+		method string
+		target string
+		body   string
+		status int
+		error  string
+	}
+
+	tests := []test{
+		{name: "valid query", method: "GET", target: "/v1/planets?limit=3&action=create&from=2020-08-07T10:00:00Z", status: 200},
+		{name: "integer", method: "GET", target: "/v1/planets?limit=three", status: 400, error: "query limit must be an integer"},
+		{name: "enum", method: "GET", target: "/v1/planets?action=purge", status: 400, error: "query action must be one of [create delete]"},
+		{name: "date-time", method: "GET", target: "/v1/planets?from=yesterday", status: 400, error: "query from must be an RFC 3339 date-time"},
+		{name: "valid body", method: "POST", target: "/v1/planets", body: `{"name":"Alderaan","climate":"temperate","films":2}`, status: 201},
+		{name: "missing body", method: "POST", target: "/v1/planets", status: 400, error: "body is required"},
+		{name: "malformed body", method: "POST", target: "/v1/planets", body: `{"name":`, status: 400, error: "body is not valid JSON"},
+		{name: "required property", method: "POST", target: "/v1/planets", body: `{"climate":["arid"]}`, status: 400, error: "body.name is required"},
+		{name: "property type", method: "POST", target: "/v1/planets", body: `{"name":"Hoth","films":1.5}`, status: 400, error: "body.films must be an integer"},
+		{name: "one of", method: "POST", target: "/v1/planets", body: `{"name":"Hoth","climate":[1]}`, status: 400, error: "body.climate must match exactly one schema"},
+		{name: "undocumented route", method: "GET", target: "/v1/undocumented", status: 418},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.error != "" {
+				assert.JSONEq(t, `{"error":"`+tt.error+`"}`, w.Body.String())
+			}
+		})
+	}
+
+	t.Run("handlers read the validated body", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/v1/planets", strings.NewReader(`{"name":"Hoth"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		assert.Equal(t, `{"name":"Hoth"}`, received)
+	})
+
+	t.Run("uploads are left to the handler", func(t *testing.T) {
+		v1.POST("/imports", func(c *gin.Context) { c.Status(http.StatusAccepted) })
+
+		req := httptest.NewRequest("POST", "/v1/imports", strings.NewReader("--x\r\n\r\nname;climate;terrain\r\n--x--\r\n"))
+		req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+	})
+}
+
+func TestResponse(t *testing.T) {
+	d := planets()
+	list := d.Operation(http.MethodGet, "/planets")
+	post := d.Operation(http.MethodPost, "/planets")
+	del := d.Operation(http.MethodDelete, "/planets/{id}")
+
+	type test struct {
+		name        string
+		op          *Operation
+		status      int
+		contentType string
+		body        string
+		error       string
+	}
+
+	tests := []test{
+		{name: "null not allowed", op: list, status: 200, contentType: "application/json; charset=utf-8", body: `[{"name":"Hoth","climate":null}]`, error: "response[0].climate must not be null"},
+		{name: "list", op: list, status: 200, contentType: "application/json; charset=utf-8", body: `[{"name":"Hoth","films":3}]`},
+		{name: "shared response", op: list, status: 400, contentType: "application/json", body: `{"error":"limit is invalid"}`},
+		{name: "undocumented status", op: post, status: 200, contentType: "application/json", body: `{"name":"Hoth"}`, error: "status 200 is not documented"},
+		{name: "wrong shape", op: post, status: 201, contentType: "application/json", body: `[]`, error: "response must be an object"},
+		{name: "undocumented content type", op: post, status: 201, contentType: "text/plain", body: `Hoth`, error: `content type "text/plain" is not documented`},
+		{name: "empty body", op: del, status: 200},
+		{name: "undocumented body", op: del, status: 200, contentType: "application/json", body: `{}`, error: "status 200 has no documented body"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := d.response(tt.op, tt.status, tt.contentType, []byte(tt.body))
+
+			if tt.error == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.error)
+			}
+		})
+	}
+}
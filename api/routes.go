@@ -1,13 +1,38 @@
 package api
 
 import (
+	"context"
 	"net/http"
 	"star-wars/api/controller"
+	"star-wars/api/graph"
+	"star-wars/api/handler"
+	"star-wars/api/openapi"
+	"star-wars/api/serializer"
+	"star-wars/apikey"
+	"star-wars/audit"
+	"star-wars/auth"
+	"star-wars/clientip"
+	"star-wars/cors"
+	"star-wars/database"
+	"star-wars/entity"
 	"star-wars/env"
+	"star-wars/health"
+	"star-wars/idempotency"
+	"star-wars/importer"
+	"star-wars/job"
+	"star-wars/logger"
+	"star-wars/metrics"
 	"star-wars/planet"
+	"star-wars/ratelimit"
+	"star-wars/requestid"
+	"star-wars/rpc"
 	"star-wars/swapi"
+	"star-wars/tracing"
+	"sync"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
 )
 
 func Config() *gin.Engine {
@@ -15,39 +40,389 @@ func Config() *gin.Engine {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	router := gin.Default()
-	router.Use(configCors)
+	gin.DebugPrintRouteFunc = func(method, path, handler string, _ int) {
+		logger.L().Debug("route", zap.String("method", method), zap.String("path", path), zap.String("handler", handler))
+	}
+
+	router := gin.New()
+	router.Use(openapi.CustomMethods(router))
+	router.Use(requestid.Middleware)
+	router.Use(clientip.Middleware(trustedProxies()))
+	router.Use(tracing.Middleware)
+	router.Use(logger.Middleware)
+	router.Use(logger.Recovery)
+	router.Use(metrics.HTTP)
+	router.Use(cors.Middleware(corsPolicy(), router.Routes))
+
+	if env.Vars.Auth.Enabled {
+		router.Use(authentication())
+	}
+
+	if env.Vars.RateLimit.Enabled {
+		router.Use(rateLimit())
+	}
+
+	v1 := version{
+		prefix:      "/v1",
+		serializer:  serializer.V1(),
+		deprecation: env.Vars.Versions.V1.Deprecation,
+		sunset:      env.Vars.Versions.V1.Sunset,
+		successor:   "/v2",
+		doc:         spec("1.0.0", "/v1", "Deprecated in favour of v2, also served without the /v1 prefix"),
+	}
+	v2 := version{
+		prefix:     "/v2",
+		serializer: serializer.V2(),
+		doc:        spec("2.0.0", "/v2", "Collections are wrapped in an envelope with the data and the page requested"),
+	}
+
+	// probes and metrics live outside the versions, both specs document them
+	root := openapi.NewRouter(router, v1.doc, v2.doc).Servers(openapi.Server{URL: "/"})
+	root.GET("/metrics", metricsOp(), gin.WrapH(metrics.Handler()))
+	root.GET("/health-check", healthCheck(), healthCtrl().HealthCheck)
+	root.GET("/livez", livez(), healthCtrl().Livez)
+	root.GET("/readyz", readyz(), healthCtrl().Readyz)
+
+	// one schema for every version, GraphQL evolves it without prefixes
+	root.POST("/graphql", graphQL(), authorize(auth.RoleReader), graphQLCtrl().Post)
+
+	// one store for every version; the fingerprint keeps the prefix, so a
+	// retry through another one gets 422 rather than a body of another shape
+	idempotent := idempotencyKeys()
+
+	// unversioned paths stay v1 for the clients written before versioning
+	for _, v := range []version{v1, v2, v1.alias()} {
+		g := router.Group(v.prefix, v.headers, handler.Negotiate)
+
+		if env.Vars.OpenAPI.Validate {
+			g.Use(openapi.Validate(v.doc, v.prefix))
+		}
 
-	router.GET("/health-check", healthCtrl().HealthCheck)
-	router.GET("/planets", planetsCtrl().All)
-	router.GET("/planets/:id", planetsCtrl().ByID)
-	router.POST("/planets", planetsCtrl().Post)
-	router.DELETE("/planets/:id", planetsCtrl().Delete)
+		resources(v.router(g), v, idempotent)
+	}
 
 	return router
 }
 
-func configCors(c *gin.Context) {
-	c.Header("Access-Control-Allow-Origin", "*")
-	c.Header("Access-Control-Allow-Methods", "*")
-	c.Header("Access-Control-Allow-Headers", "*")
-	c.Header("Content-Type", "application/json")
-	if c.Request.Method != "OPTIONS" {
-		c.Next()
-	} else {
-		c.AbortWithStatus(http.StatusOK)
+// resources registers the versioned routes, serializing collections as v does;
+// the routes creating planets replay their responses through idempotent
+func resources(r *openapi.Router, v version, idempotent gin.HandlerFunc) {
+	planets := planetsCtrl(v.serializer)
+	audit := auditCtrl(v.serializer)
+	apiKeys := apiKeysCtrl(v.serializer)
+	jobs := jobsCtrl()
+	imports := importsCtrl(v.serializer)
+	d, s := v.doc, v.serializer
+
+	// handle documents the role a route requires next to the check enforcing it
+	handle := func(method, path string, role auth.Role, op openapi.Operation, h ...gin.HandlerFunc) {
+		r.Handle(method, path, requires(role, resourceOp(op)), append([]gin.HandlerFunc{authorize(role)}, h...)...)
+	}
+
+	handle(http.MethodGet, "/planets", auth.RoleReader, listPlanets(d, s), planets.All)
+	handle(http.MethodGet, "/planets/:id", auth.RoleReader, getPlanet(), planets.ByID)
+	handle(http.MethodPost, "/planets", auth.RoleEditor, idempotentOp(postPlanet()), idempotent, planets.Post)
+	handle(http.MethodPost, openapi.CustomMethod("/planets", "batch"), auth.RoleEditor, idempotentOp(postPlanets(d, s)), idempotent, planets.PostBatch)
+	handle(http.MethodDelete, openapi.CustomMethod("/planets", "batch"), auth.RoleEditor, deletePlanets(d, s), planets.DeleteBatch)
+	handle(http.MethodPut, "/planets/:id", auth.RoleEditor, putPlanet(), planets.Put)
+	handle(http.MethodDelete, "/planets/:id", auth.RoleEditor, deletePlanet(), planets.Delete)
+	handle(http.MethodPost, "/planets/:id/restore", auth.RoleEditor, restorePlanet(), planets.Restore)
+	handle(http.MethodPost, "/admin/planets/purge", auth.RoleAdmin, purgePlanets(), planets.Purge)
+	handle(http.MethodGet, "/jobs/:id", auth.RoleEditor, getJob(), jobs.ByID)
+	handle(http.MethodPost, "/imports", auth.RoleEditor, postImport(), imports.Post)
+	handle(http.MethodGet, "/imports/:id", auth.RoleEditor, getImport(), imports.ByID)
+	handle(http.MethodGet, "/imports/:id/errors", auth.RoleEditor, importErrors(d, s), imports.Errors)
+	handle(http.MethodGet, "/planets/:id/history", auth.RoleReader, planetHistory(d, s), audit.History)
+	handle(http.MethodGet, "/audit", auth.RoleAdmin, listAudit(d, s), audit.All)
+	handle(http.MethodPost, "/admin/api-keys", auth.RoleAdmin, postAPIKey(), apiKeys.Post)
+	handle(http.MethodGet, "/admin/api-keys", auth.RoleAdmin, listAPIKeys(d, s), apiKeys.All)
+	handle(http.MethodPost, "/admin/api-keys/:id/rotate", auth.RoleAdmin, rotateAPIKey(), apiKeys.Rotate)
+	handle(http.MethodDelete, "/admin/api-keys/:id", auth.RoleAdmin, revokeAPIKey(), apiKeys.Revoke)
+	handle(http.MethodGet, "/climates", auth.RoleReader, terminology(d, s, "climates"), planets.Climates)
+	handle(http.MethodGet, "/terrains", auth.RoleReader, terminology(d, s, "terrains"), planets.Terrains)
+
+	r.GET("/openapi.json", getSpec(), openapi.Serve(d))
+}
+
+func corsPolicy() cors.Policy {
+	cfg := env.Vars.Cors
+	return cors.Policy{
+		Origins:     cfg.Origins,
+		Methods:     cfg.Methods,
+		Headers:     cfg.Headers,
+		Expose:      cfg.Expose,
+		Credentials: cfg.Credentials,
+		MaxAge:      cfg.MaxAge,
+	}
+}
+
+// trustedProxies whose forwarding headers tell the client address, none by
+// default: the rate limit and idempotency buckets are keyed on the peer
+func trustedProxies() clientip.Proxies {
+	proxies, err := clientip.ParseProxies(env.Vars.Api.TrustedProxies)
+	if err != nil {
+		logger.L().Fatal("invalid trusted proxies", zap.Error(err))
+	}
+	return proxies
+}
+
+// authentication of the HTTP requests, see authenticators
+func authentication() gin.HandlerFunc {
+	anonymous, authenticators := authenticators()
+	return auth.Middleware(anonymous, authenticators...)
+}
+
+// authenticators accept static and stored API keys and, when a JWKS is
+// configured, JWTs; callers without credentials get the anonymous role
+func authenticators() (auth.Role, []auth.Authenticator) {
+	cfg := env.Vars.Auth
+
+	anonymous, ok := auth.ParseRole(cfg.AnonymousRole)
+	if !ok && cfg.AnonymousRole != "" {
+		logger.L().Fatal("unknown anonymous role", zap.String("role", cfg.AnonymousRole))
+	}
+
+	keys, err := auth.NewAPIKeys(cfg.ApiKeys, apiKeySrv())
+	if err != nil {
+		logger.L().Fatal("invalid api keys", zap.Error(err))
+	}
+
+	authenticators := []auth.Authenticator{keys}
+
+	if cfg.Jwks != "" {
+		jwks, err := auth.LoadJWKS(context.Background(), cfg.Jwks)
+		if err != nil {
+			logger.L().Fatal("invalid jwks", zap.Error(err))
+		}
+
+		authenticators = append(authenticators, auth.NewJWT(jwks, cfg.Issuer, cfg.Audience, cfg.RolesClaim))
+	}
+
+	return anonymous, authenticators
+}
+
+// authorize enforces role on a route, a no-op while auth is disabled
+func authorize(role auth.Role) gin.HandlerFunc {
+	if !env.Vars.Auth.Enabled {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return auth.Require(role)
+}
+
+// rateLimit runs after authentication so clients are keyed by principal when there is one
+func rateLimit() gin.HandlerFunc {
+	cfg := env.Vars.RateLimit
+
+	def, err := ratelimit.ParseLimit(cfg.Default)
+	if err != nil {
+		logger.L().Fatal("invalid rate limit", zap.Error(err))
+	}
+
+	routes, err := ratelimit.ParseRoutes(cfg.Routes)
+	if err != nil {
+		logger.L().Fatal("invalid rate limit", zap.Error(err))
+	}
+
+	var store ratelimit.Store
+
+	switch cfg.Store {
+	case "memory":
+		store = ratelimit.NewMemoryStore()
+	case "redis":
+		store = ratelimit.NewRedisStore(redisClient())
+	default:
+		logger.L().Fatal("unknown rate limit store", zap.String("store", cfg.Store))
+	}
+
+	return ratelimit.Middleware(store, def, routes, "/metrics", "/health-check", "/livez", "/readyz")
+}
+
+// idempotencyKeys holds a key for the server write timeout, which outlasts
+// any handler, and keeps its response for idempotency.ttl
+func idempotencyKeys() gin.HandlerFunc {
+	cfg := env.Vars.Idempotency
+
+	var store idempotency.Store
+
+	switch cfg.Store {
+	case "memory":
+		store = idempotency.NewMemoryStore()
+	case "mongodb":
+		store = idempotency.NewMongoStore()
+	default:
+		logger.L().Fatal("unknown idempotency store", zap.String("store", cfg.Store))
+	}
+
+	return idempotency.Middleware(store, cfg.TTL, env.Vars.Api.WriteTimeout)
+}
+
+var (
+	redisOnce sync.Once
+	redisConn *redis.Client
+)
+
+// redisClient shared by the rate limiter and its readiness check
+func redisClient() *redis.Client {
+	redisOnce.Do(func() {
+		opt, err := redis.ParseURL(env.Vars.RateLimit.Redis)
+		if err != nil {
+			logger.L().Fatal("invalid redis url", zap.Error(err))
+		}
+		redisConn = redis.NewClient(opt)
+	})
+	return redisConn
+}
+
+// usesRedis tells whether Config talks to Redis
+func usesRedis() bool {
+	return env.Vars.RateLimit.Enabled && env.Vars.RateLimit.Store == "redis"
+}
+
+// CloseRedis releases the Redis connections, if Config opened any
+func CloseRedis(_ context.Context) error {
+	if !usesRedis() {
+		return nil
 	}
+	return redisClient().Close()
 }
 
 func healthCtrl() controller.HealthCheck {
 	return controller.HealthCheck{
-		DB: planet.NewRepository(),
+		DB:        planet.NewRepository(),
+		Readiness: readiness(),
+	}
+}
+
+// readiness dependencies; register cache and queue backends here as they are added
+func readiness() health.Service {
+	deps := []health.Dependency{
+		{
+			Name:     entity.CheckMongoDB,
+			Critical: true,
+			Checker:  health.CheckerFunc(database.Ping),
+		},
+		health.Dependency{
+			Name:    "swapi",
+			Checker: health.Cached(health.CheckerFunc(swapi.NewInstrumented(swapi.New()).Ping), env.Vars.Health.SwapiTTL),
+		},
+	}
+
+	// the limiter lets requests through while Redis is down, so it only degrades
+	if usesRedis() {
+		deps = append(deps, health.Dependency{
+			Name: "redis",
+			Checker: health.CheckerFunc(func(ctx context.Context) error {
+				return redisClient().Ping(ctx).Err()
+			}),
+		})
+	}
+
+	return health.NewService(deps...)
+}
+
+func auditSrv() audit.Service {
+	return audit.NewService(audit.NewRepository())
+}
+
+func swapiSrv() swapi.Service {
+	return swapi.NewRetry(
+		swapi.NewInstrumented(swapi.New()),
+		env.Vars.Swapi.Retries,
+		env.Vars.Swapi.Backoff,
+	)
+}
+
+func planetSrv() planet.Service {
+	return planet.NewTracedService(
+		planet.NewService(planet.NewInstrumentedRepository(planet.NewRepository()), swapiSrv(), auditSrv()),
+	)
+}
+
+// GRPC serves the planets to internal services, on a port of its own, with
+// the credentials and roles of the HTTP API
+func GRPC() *rpc.Server {
+	if !env.Vars.Auth.Enabled {
+		return rpc.NewServer(planetSrv(), nil)
 	}
+
+	anonymous, authenticators := authenticators()
+	return rpc.NewServer(planetSrv(), &rpc.Authentication{Anonymous: anonymous, Authenticators: authenticators})
 }
 
-func planetsCtrl() controller.Planets {
-	s := planet.NewService(planet.NewRepository(), swapi.New())
+func planetsCtrl(s serializer.Serializer) controller.Planets {
 	return controller.Planets{
-		Srv: s,
+		Srv:        planetSrv(),
+		Jobs:       jobSrv(),
+		Serializer: s,
+	}
+}
+
+var (
+	jobsOnce sync.Once
+	jobPool  *job.Pool
+)
+
+// Jobs pool running the work the API queues, started and stopped by the caller
+func Jobs() *job.Pool {
+	jobsOnce.Do(func() {
+		jobPool = job.NewPool(job.NewRepository(), env.Vars.Jobs.Workers, env.Vars.Jobs.Poll)
+		jobPool.Handle(entity.JobCreatePlanet, env.Vars.Timeouts.Job, planet.CreateJob(planetSrv()))
+		jobPool.Handle(entity.JobImportPlanets, env.Vars.Timeouts.Import, importer.Job(importerSrv()))
+	})
+	return jobPool
+}
+
+func jobSrv() job.Service {
+	return job.NewService(job.NewRepository(), Jobs().Wake)
+}
+
+func jobsCtrl() controller.Jobs {
+	return controller.Jobs{
+		Srv: jobSrv(),
+	}
+}
+
+func importerSrv() importer.Service {
+	return importer.NewInstrumented(importer.NewImporter(planetSrv(), swapiSrv()))
+}
+
+func importsCtrl(s serializer.Serializer) controller.Imports {
+	return controller.Imports{
+		Jobs:       jobSrv(),
+		Serializer: s,
+	}
+}
+
+func graphQLCtrl() controller.GraphQL {
+	var check graph.Authorize
+	if env.Vars.Auth.Enabled {
+		check = auth.Check
+	}
+
+	executor, err := graph.New(planetSrv(), swapiSrv(), check)
+	if err != nil {
+		logger.L().Fatal("invalid graphql schema", zap.Error(err))
+	}
+
+	return controller.GraphQL{
+		Executor: executor,
+	}
+}
+
+func apiKeySrv() apikey.Service {
+	return apikey.NewService(apikey.NewRepository())
+}
+
+func apiKeysCtrl(s serializer.Serializer) controller.APIKeys {
+	return controller.APIKeys{
+		Srv:        apiKeySrv(),
+		Serializer: s,
+	}
+}
+
+func auditCtrl(s serializer.Serializer) controller.Audit {
+	return controller.Audit{
+		Srv:        auditSrv(),
+		Serializer: s,
 	}
 }
@@ -0,0 +1,71 @@
+package serializer
+
+import "star-wars/api/openapi"
+
+// Page of a paginated collection
+type Page struct {
+	Limit int64 `json:"limit"`
+	Skip  int64 `json:"skip"`
+	Count int   `json:"count"`
+}
+
+// Envelope wraps collections from v2 on, so metadata can grow without breaking clients
+type Envelope struct {
+	Data interface{} `json:"data"`
+	Page *Page       `json:"page,omitempty"`
+}
+
+// Items wrapped, what tabular encodings write
+func (e Envelope) Items() interface{} {
+	return e.Data
+}
+
+// Serializer shapes collection responses for an API version
+type Serializer interface {
+	// List shapes items; page is nil for collections that are not paginated
+	List(items interface{}, page *Page) interface{}
+	// Schema documents what List answers for items in d
+	Schema(d *openapi.Document, items *openapi.Schema, paged bool) *openapi.Schema
+}
+
+type v1 struct{}
+
+// V1 answers collections as bare arrays
+func V1() Serializer {
+	return &v1{}
+}
+
+func (v1) List(items interface{}, _ *Page) interface{} {
+	return items
+}
+
+func (v1) Schema(_ *openapi.Document, items *openapi.Schema, _ bool) *openapi.Schema {
+	return openapi.Array(items)
+}
+
+type v2 struct{}
+
+// V2 answers collections in an Envelope
+func V2() Serializer {
+	return &v2{}
+}
+
+func (v2) List(items interface{}, page *Page) interface{} {
+	return Envelope{Data: items, Page: page}
+}
+
+func (v2) Schema(d *openapi.Document, items *openapi.Schema, paged bool) *openapi.Schema {
+	properties := map[string]*openapi.Schema{"data": openapi.Array(items)}
+	if paged {
+		properties["page"] = d.Schema(Page{})
+	}
+	return openapi.Object(properties, "data")
+}
+
+// Or returns s, or V1 when s is nil
+func Or(s Serializer) Serializer {
+	if s == nil {
+		return V1()
+	}
+	return s
+}
@@ -0,0 +1,46 @@
+package serializer
+
+import (
+	"encoding/json"
+	"star-wars/api/openapi"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestList(t *testing.T) {
+	items := []string{"arid", "temperate"}
+
+	type test struct {
+		name       string
+		serializer Serializer
+		page       *Page
+		want       string
+	}
+
+	tests := []test{
+		{name: "v1", serializer: V1(), page: &Page{Limit: 3}, want: `["arid","temperate"]`},
+		{name: "v2 paginated", serializer: V2(), page: &Page{Limit: 3, Skip: 0, Count: 2}, want: `{"data":["arid","temperate"],"page":{"limit":3,"skip":0,"count":2}}`},
+		{name: "v2 not paginated", serializer: V2(), want: `{"data":["arid","temperate"]}`},
+		{name: "nil falls back to v1", serializer: Or(nil), want: `["arid","temperate"]`},
+	}
+
+	for _, tt := range tests {
+		raw, err := json.Marshal(tt.serializer.List(items, tt.page))
+
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, string(raw), tt.name)
+	}
+}
+
+func TestSchema(t *testing.T) {
+	d := openapi.New(openapi.Info{Title: "test", Version: "1"})
+	items := openapi.String()
+
+	assert.Equal(t, openapi.Array(items), V1().Schema(d, items, true))
+	assert.Equal(t, openapi.Object(map[string]*openapi.Schema{"data": openapi.Array(items)}, "data"), V2().Schema(d, items, false))
+
+	paged := V2().Schema(d, items, true)
+	assert.Equal(t, openapi.Ref("Page"), paged.Properties["page"])
+	assert.Contains(t, d.Components.Schemas, "Page")
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"star-wars/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultShutdownTimeout used when Timeout is not set
+const DefaultShutdownTimeout = 15 * time.Second
+
+// Closer releases a resource when the server stops
+type Closer func(ctx context.Context) error
+
+type closer struct {
+	name string
+	fn   Closer
+}
+
+// Server HTTP server with graceful shutdown
+type Server struct {
+	HTTP *http.Server
+	// Timeout to drain the requests, and then again for each closer
+	Timeout time.Duration
+	closers []closer
+}
+
+// OnShutdown registers a closer; closers run after the requests drain,
+// in reverse order of registration
+func (s *Server) OnShutdown(name string, fn Closer) {
+	s.closers = append(s.closers, closer{name: name, fn: fn})
+}
+
+// Run serves until a signal arrives on stop or the listener fails
+func (s *Server) Run(stop <-chan os.Signal) error {
+	errc := make(chan error, 1)
+
+	go func() {
+		errc <- s.HTTP.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		if errors.Is(err, http.ErrServerClosed) {
+			err = nil
+		}
+		return s.close(err)
+	case sig := <-stop:
+		logger.L().Info("shutting down", zap.String("signal", sig.String()))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout())
+	defer cancel()
+
+	var err error
+	if shutdownErr := s.HTTP.Shutdown(ctx); shutdownErr != nil {
+		err = fmt.Errorf("http: %w", shutdownErr)
+	}
+
+	return s.close(err)
+}
+
+func (s *Server) timeout() time.Duration {
+	if s.Timeout <= 0 {
+		return DefaultShutdownTimeout
+	}
+	return s.Timeout
+}
+
+// close runs every closer even after a failure and keeps the first error;
+// each one gets a budget of its own, whatever the ones before it took
+func (s *Server) close(err error) error {
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		c := s.closers[i]
+
+		if closeErr := s.run(c); closeErr != nil {
+			logger.L().Error("error closing", zap.String("resource", c.name), zap.Error(closeErr))
+
+			if err == nil {
+				err = fmt.Errorf("%s: %w", c.name, closeErr)
+			}
+		}
+	}
+
+	return err
+}
+
+func (s *Server) run(c closer) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout())
+	defer cancel()
+
+	return c.fn(ctx)
+}
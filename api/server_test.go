@@ -0,0 +1,161 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newServer(t *testing.T, handler http.Handler, timeout time.Duration) (*Server, string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	return &Server{
+		HTTP:    &http.Server{Addr: addr, Handler: handler},
+		Timeout: timeout,
+	}, addr
+}
+
+func waitListening(t *testing.T, addr string) {
+	for i := 0; i < 100; i++ {
+		if conn, err := net.Dial("tcp", addr); err == nil {
+			conn.Close()
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("server did not start")
+}
+
+func TestRun(t *testing.T) {
+	t.Run("drains requests and closes in reverse order", func(t *testing.T) {
+		started := make(chan struct{})
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			time.Sleep(100 * time.Millisecond)
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		s, addr := newServer(t, handler, time.Second)
+
+		var order []string
+		s.OnShutdown("mongodb", func(ctx context.Context) error {
+			order = append(order, "mongodb")
+			return nil
+		})
+		s.OnShutdown("scheduler", func(ctx context.Context) error {
+			order = append(order, "scheduler")
+			return nil
+		})
+
+		stop := make(chan os.Signal, 1)
+		done := make(chan error, 1)
+		go func() { done <- s.Run(stop) }()
+		waitListening(t, addr)
+
+		status := make(chan int, 1)
+		go func() {
+			res, err := http.Get("http://" + addr)
+			if err != nil {
+				status <- 0
+				return
+			}
+			res.Body.Close()
+			status <- res.StatusCode
+		}()
+
+		<-started
+		stop <- syscall.SIGTERM
+
+		assert.Nil(t, <-done)
+		assert.Equal(t, http.StatusNoContent, <-status)
+		assert.Equal(t, []string{"scheduler", "mongodb"}, order)
+	})
+
+	t.Run("when requests do not drain in time", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+		})
+
+		s, addr := newServer(t, handler, 50*time.Millisecond)
+
+		var live []bool
+		s.OnShutdown("mongodb", func(ctx context.Context) error {
+			live = append(live, ctx.Err() == nil)
+			return nil
+		})
+		s.OnShutdown("jobs", func(ctx context.Context) error {
+			live = append(live, ctx.Err() == nil)
+			<-ctx.Done()
+			return nil
+		})
+
+		stop := make(chan os.Signal, 1)
+		done := make(chan error, 1)
+		go func() { done <- s.Run(stop) }()
+		waitListening(t, addr)
+
+		go http.Get("http://" + addr)
+
+		<-started
+		stop <- syscall.SIGTERM
+
+		err := <-done
+		close(release)
+
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.Equal(t, []bool{true, true}, live, "each closer has a budget of its own")
+	})
+
+	t.Run("when a closer fails", func(t *testing.T) {
+		s, addr := newServer(t, http.NotFoundHandler(), time.Second)
+
+		s.OnShutdown("mongodb", func(ctx context.Context) error {
+			return errors.New("disconnect error")
+		})
+
+		stop := make(chan os.Signal, 1)
+		done := make(chan error, 1)
+		go func() { done <- s.Run(stop) }()
+		waitListening(t, addr)
+
+		stop <- syscall.SIGTERM
+
+		assert.Equal(t, "mongodb: disconnect error", (<-done).Error())
+	})
+
+	t.Run("when the listener fails", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer l.Close()
+
+		s := &Server{HTTP: &http.Server{Addr: l.Addr().String()}}
+
+		closed := false
+		s.OnShutdown("mongodb", func(ctx context.Context) error {
+			closed = true
+			return nil
+		})
+
+		err = s.Run(make(chan os.Signal))
+
+		assert.NotNil(t, err)
+		assert.True(t, closed)
+	})
+}
@@ -0,0 +1,621 @@
+package api
+
+import (
+	"star-wars/api/handler"
+	"star-wars/api/openapi"
+	"star-wars/api/serializer"
+	"star-wars/auth"
+	"star-wars/entity"
+	"strings"
+)
+
+// spec of a version, the operations are added as resources registers them
+func spec(version, prefix, description string) *openapi.Document {
+	d := openapi.New(
+		openapi.Info{Title: "Star Wars", Version: version, Description: description},
+		openapi.Server{URL: prefix},
+	)
+
+	d.Tags = []openapi.Tag{
+		{Name: "planets", Description: "All about the planets"},
+		{Name: "jobs", Description: "Work queued by asynchronous requests"},
+		{Name: "imports", Description: "Planets uploaded in CSV or JSON files"},
+		{Name: "audit", Description: "Who changed which planet and when"},
+		{Name: "api-keys", Description: "Machine credentials for partners"},
+		{Name: "graphql", Description: "Planets with their films and residents in a single query"},
+		{Name: "health", Description: "Probes for the orchestrator"},
+		{Name: "docs", Description: "This document"},
+	}
+
+	d.Security = []openapi.Requirement{{}, {"bearerAuth": {}}, {"apiKey": {}}}
+
+	c := &d.Components
+
+	c.SecuritySchemes["bearerAuth"] = &openapi.SecurityScheme{
+		Type:         "http",
+		Scheme:       "bearer",
+		BearerFormat: "JWT",
+		Description:  "JWT signed by a key in the configured JWKS, roles read from the roles claim",
+	}
+	c.SecuritySchemes["apiKey"] = &openapi.SecurityScheme{Type: "apiKey", In: "header", Name: auth.APIKeyHeader}
+
+	c.Schemas["Error"] = openapi.Object(map[string]*openapi.Schema{
+		"error":     {Type: "string", Example: "message"},
+		"requestId": {Type: "string", Example: "6f1c2a4e-6d0b-4a8e-9a55-1b2f3c4d5e6f"},
+	}, "error")
+
+	c.Schemas["PlanetPost"] = openapi.Object(map[string]*openapi.Schema{
+		"name":    {Type: "string", Example: "Alderaan"},
+		"climate": terms("temperate"),
+		"terrain": terms("grasslands", "mountains"),
+	})
+
+	c.Schemas["PlanetDelete"] = openapi.Object(map[string]*openapi.Schema{
+		"id":      {Type: "string", Example: "5f2c88567563c4bae600d7e0"},
+		"version": {Type: "integer", Description: "Version being deleted, as If-Match; any when missing", Example: 2},
+	}, "id")
+
+	c.Schemas["APIKeyPost"] = openapi.Object(map[string]*openapi.Schema{
+		"name":      {Type: "string", Example: "partner"},
+		"scopes":    openapi.Array(openapi.Enum(entity.ScopePlanetsRead, entity.ScopePlanetsWrite)),
+		"expiresAt": openapi.DateTime(),
+	}, "name", "scopes")
+
+	d.Schema(entity.Planet{})
+	d.Schema(entity.AuditEntry{})
+	d.Schema(entity.APIKey{})
+	d.Schema(entity.HealthCheck{})
+	d.Schema(entity.BatchResult{})
+	d.Schema(entity.Job{})
+	d.Schema(entity.ImportError{})
+
+	c.Responses["BadRequest"] = openapi.Reply("Bad request", openapi.Ref("Error"))
+	c.Responses["NotFound"] = openapi.Reply("Not Found", openapi.Ref("Error"))
+	c.Responses["PreconditionFailed"] = openapi.Reply("Precondition Failed - the planet was modified since it was read", openapi.Ref("Error"))
+	c.Responses["Internal"] = openapi.Reply("Internal Server Error", openapi.Ref("Error"))
+	c.Responses["NotAcceptable"] = openapi.Reply("None of the media types in Accept can represent the response", openapi.Ref("Error"))
+	c.Responses["Forbidden"] = openapi.Reply("The principal lacks the role the route requires", openapi.Ref("Error"))
+	c.Responses["IdempotencyConflict"] = openapi.Reply("A request with the same Idempotency-Key is still in progress", openapi.Ref("Error"))
+	c.Responses["IdempotencyMismatch"] = openapi.Reply("The Idempotency-Key was used for a request with another body", openapi.Ref("Error"))
+
+	c.Responses["Unauthorized"] = openapi.Reply("Missing or invalid credentials", openapi.Ref("Error"))
+	c.Responses["Unauthorized"].Headers = map[string]*openapi.Header{
+		"WWW-Authenticate": {Schema: &openapi.Schema{Type: "string", Example: `Bearer realm="star-wars"`}},
+	}
+
+	c.Responses["TooManyRequests"] = openapi.Reply("The client used up its rate limit for the route", openapi.Ref("Error"))
+	c.Responses["TooManyRequests"].Headers = map[string]*openapi.Header{
+		"Retry-After":         {Description: "Seconds until a request is accepted again", Schema: &openapi.Schema{Type: "integer", Example: 6}},
+		"RateLimit-Limit":     openapi.HeaderRef("RateLimitLimit"),
+		"RateLimit-Remaining": openapi.HeaderRef("RateLimitRemaining"),
+		"RateLimit-Reset":     openapi.HeaderRef("RateLimitReset"),
+	}
+
+	c.Parameters["IfMatch"] = &openapi.Parameter{
+		Name: "If-Match", In: "header", Description: "ETag of the version being changed, in any media type", Example: `"2"`, Schema: openapi.String(),
+	}
+	c.Parameters["IdempotencyKey"] = &openapi.Parameter{
+		Name: "Idempotency-Key", In: "header", Example: "3f0b7c1e-2a4d-4b8e-9c6f-5d1e2f3a4b5c", Schema: openapi.String(),
+		Description: "Picked by the client for a request and its retries, which get the first response back " +
+			"with Idempotent-Replayed: true for idempotency.ttl, 24h by default",
+	}
+	c.Parameters["IfNoneMatch"] = &openapi.Parameter{
+		Name: "If-None-Match", In: "header", Description: "ETag of the cached copy", Example: `"2"`, Schema: openapi.String(),
+	}
+	c.Parameters["IfModifiedSince"] = &openapi.Parameter{
+		Name: "If-Modified-Since", In: "header", Description: "Last-Modified of the cached copy, ignored when If-None-Match is sent",
+		Example: "Fri, 07 Aug 2020 10:00:00 GMT", Schema: openapi.String(),
+	}
+
+	c.Headers["RateLimitLimit"] = &openapi.Header{Description: "Requests allowed per window for the route and client", Schema: openapi.Integer()}
+	c.Headers["RateLimitRemaining"] = &openapi.Header{Description: "Requests left before the client is limited", Schema: openapi.Integer()}
+	c.Headers["RateLimitReset"] = &openapi.Header{Description: "Seconds until the client has the whole limit again", Schema: openapi.Integer()}
+	c.Headers["ETag"] = &openapi.Header{Description: "Planet version, suffixed by the media type when not JSON: \"2-csv\"", Schema: &openapi.Schema{Type: "string", Example: `"2"`}}
+	c.Headers["ListETag"] = &openapi.Header{Description: "Hash of the returned page and its media type", Schema: openapi.String()}
+	c.Headers["LastModified"] = &openapi.Header{Description: "Last change of the planet", Schema: openapi.String()}
+	c.Headers["CacheControl"] = &openapi.Header{Description: "Cache policy from the cache section of the config", Schema: openapi.String()}
+
+	return d
+}
+
+// terms accepted as a list or a comma-joined string
+func terms(example ...string) *openapi.Schema {
+	return &openapi.Schema{
+		Description: "Array or comma-joined string",
+		OneOf:       []*openapi.Schema{openapi.Array(openapi.String()), openapi.String()},
+		Example:     example,
+	}
+}
+
+// requires documents the role authorize enforces on op
+func requires(role auth.Role, op openapi.Operation) openapi.Operation {
+	op.Description = "Requires the " + string(role) + " role"
+	op.Responses["401"] = openapi.ResponseRef("Unauthorized")
+	op.Responses["403"] = openapi.ResponseRef("Forbidden")
+	return op
+}
+
+// idempotentOp documents the Idempotency-Key op honors
+func idempotentOp(op openapi.Operation) openapi.Operation {
+	op.Parameters = append(op.Parameters, openapi.ParameterRef("IdempotencyKey"))
+	op.Responses["409"] = openapi.ResponseRef("IdempotencyConflict")
+	op.Responses["422"] = openapi.ResponseRef("IdempotencyMismatch")
+	return op
+}
+
+// resourceOp adds the answers every resource may give, in every media type
+// handler.ResponseSuccess negotiates besides JSON
+func resourceOp(op openapi.Operation) openapi.Operation {
+	op.Responses["406"] = openapi.ResponseRef("NotAcceptable")
+	op.Responses["429"] = openapi.ResponseRef("TooManyRequests")
+	op.Responses["500"] = openapi.ResponseRef("Internal")
+
+	for status, r := range op.Responses {
+		json, ok := r.Content["application/json"]
+		if !ok || !strings.HasPrefix(status, "2") {
+			continue
+		}
+
+		for _, media := range handler.MediaTypes() {
+			if _, ok := r.Content[media]; !ok && media != "text/csv" {
+				r.Content[media] = json
+			}
+		}
+	}
+
+	return op
+}
+
+// tabular documents the planets of r in the importer CSV layout as well
+func tabular(r *openapi.Response) *openapi.Response {
+	r.Content["text/csv"] = openapi.MediaType{Schema: &openapi.Schema{
+		Type:        "string",
+		Description: "Header and one row per planet, separated by ;",
+		Example:     "name;climate;terrain\nAlderaan;temperate;grasslands, mountains\n",
+	}}
+	return r
+}
+
+var (
+	planetID = openapi.Parameter{Name: "id", In: "path", Required: true, Description: "Planet ID", Example: "5f2c88567563c4bae600d7e0", Schema: openapi.String()}
+	jobID    = openapi.Parameter{Name: "id", In: "path", Required: true, Description: "Job ID", Example: "5f3a1c2b9a9e070b1ef2e29a", Schema: openapi.String()}
+	importID = openapi.Parameter{Name: "id", In: "path", Required: true, Description: "Import ID", Example: "5f3a1c2b9a9e070b1ef2e29a", Schema: openapi.String()}
+	apiKeyID = openapi.Parameter{Name: "id", In: "path", Required: true, Description: "API key ID", Example: "5f3080961f4799f091e3c515", Schema: openapi.String()}
+)
+
+func page(limit string) []openapi.Parameter {
+	return []openapi.Parameter{
+		openapi.Query("limit", "Results limit - Default "+limit, openapi.Integer()),
+		openapi.Query("skip", "Skip items - Default 0", openapi.Integer()),
+	}
+}
+
+func period() []openapi.Parameter {
+	return []openapi.Parameter{
+		openapi.Query("from", "Entries at or after", openapi.DateTime()),
+		openapi.Query("to", "Entries before", openapi.DateTime()),
+	}
+}
+
+func badRequest() *openapi.Response { return openapi.ResponseRef("BadRequest") }
+
+func notFound() *openapi.Response { return openapi.ResponseRef("NotFound") }
+
+func preconditionFailed() *openapi.Response { return openapi.ResponseRef("PreconditionFailed") }
+
+// planetReply answers a planet with its version in the ETag
+func planetReply(description string) *openapi.Response {
+	r := openapi.Reply(description, openapi.Ref("Planet"))
+	r.Headers = map[string]*openapi.Header{"ETag": openapi.HeaderRef("ETag")}
+	return r
+}
+
+func cached(r *openapi.Response, etag string) *openapi.Response {
+	r.Headers = map[string]*openapi.Header{
+		"ETag":          openapi.HeaderRef(etag),
+		"Cache-Control": openapi.HeaderRef("CacheControl"),
+	}
+	return r
+}
+
+// modified adds the Last-Modified of a single resource to a cached response
+func modified(r *openapi.Response) *openapi.Response {
+	r.Headers["Last-Modified"] = openapi.HeaderRef("LastModified")
+	return r
+}
+
+func notModified() *openapi.Response {
+	return openapi.Reply("Not Modified - the client copy is still fresh", nil)
+}
+
+func secret(description string) *openapi.Response {
+	r := openapi.Reply(description, openapi.Ref("APIKey"))
+	r.Headers = map[string]*openapi.Header{
+		"Cache-Control": {Description: "Secrets are never cached", Schema: &openapi.Schema{Type: "string", Example: "no-store"}},
+	}
+	return r
+}
+
+func listPlanets(d *openapi.Document, s serializer.Serializer) openapi.Operation {
+	return openapi.Operation{
+		Tags:    []string{"planets"},
+		Summary: "List planets",
+		Parameters: append(page("3"),
+			openapi.Query("search", "Planet name, answers only that planet or 400 when it does not exist", openapi.String()),
+			openapi.ParameterRef("IfNoneMatch"),
+		),
+		Responses: map[string]*openapi.Response{
+			"200": tabular(cached(openapi.Reply("Ok", s.Schema(d, openapi.Ref("Planet"), true)), "ListETag")),
+			"304": notModified(),
+			"400": badRequest(),
+		},
+	}
+}
+
+func getPlanet() openapi.Operation {
+	return openapi.Operation{
+		Tags:       []string{"planets"},
+		Summary:    "Get planet by id",
+		Parameters: []openapi.Parameter{planetID, openapi.ParameterRef("IfNoneMatch"), openapi.ParameterRef("IfModifiedSince")},
+		Responses: map[string]*openapi.Response{
+			"200": tabular(modified(cached(openapi.Reply("Ok", openapi.Ref("Planet")), "ETag"))),
+			"304": notModified(),
+			"400": badRequest(),
+			"404": notFound(),
+		},
+	}
+}
+
+func postPlanet() openapi.Operation {
+	return openapi.Operation{
+		Tags:        []string{"planets"},
+		Summary:     "Add planet",
+		Parameters:  []openapi.Parameter{async},
+		RequestBody: openapi.Body(openapi.Ref("PlanetPost")),
+		Responses: map[string]*openapi.Response{
+			"201": planetReply("Created"),
+			"202": jobReply(),
+			"400": badRequest(),
+		},
+	}
+}
+
+var async = openapi.Query("async", "Queue the creation instead of waiting on SWAPI - Default false", openapi.Boolean())
+
+// jobReply the job queued, followed at its Location
+func jobReply() *openapi.Response {
+	r := openapi.Reply("Queued, GET the Location until the job is done", openapi.Ref("Job"))
+	r.Headers = map[string]*openapi.Header{
+		"Location": {Description: "Job of the request", Schema: &openapi.Schema{Type: "string", Example: "/v2/jobs/5f3a1c2b9a9e070b1ef2e29a"}},
+	}
+	return r
+}
+
+func postImport() openapi.Operation {
+	upload := openapi.Object(map[string]*openapi.Schema{
+		"file": {
+			Type:        "string",
+			Format:      "binary",
+			Description: "CSV in the name;climate;terrain layout with its header, or a JSON array of planets; told apart by the .csv or .json extension or the content type",
+		},
+	}, "file")
+
+	return openapi.Operation{
+		Tags:    []string{"imports"},
+		Summary: "Import the planets of a file in the background",
+		RequestBody: &openapi.RequestBody{
+			Description: "At most imports.max-rows planets, 10000 by default, in 10 MB",
+			Required:    true,
+			Content:     map[string]openapi.MediaType{"multipart/form-data": {Schema: upload}},
+		},
+		Responses: map[string]*openapi.Response{
+			"202": importReply(),
+			"400": badRequest(),
+		},
+	}
+}
+
+// importReply the import queued, followed at its Location
+func importReply() *openapi.Response {
+	r := openapi.Reply("Queued, the rows that are not valid planets already reported", openapi.Ref("Job"))
+	r.Headers = map[string]*openapi.Header{
+		"Location": {Description: "Import of the request", Schema: &openapi.Schema{Type: "string", Example: "/v2/imports/5f3a1c2b9a9e070b1ef2e29a"}},
+	}
+	return r
+}
+
+func getImport() openapi.Operation {
+	return openapi.Operation{
+		Tags:       []string{"imports"},
+		Summary:    "Progress of an import, with the rows that failed so far",
+		Parameters: []openapi.Parameter{importID},
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Reply("Ok", openapi.Ref("Job")),
+			"404": notFound(),
+		},
+	}
+}
+
+func importErrors(d *openapi.Document, s serializer.Serializer) openapi.Operation {
+	r := openapi.Reply("Rows that failed, in the order of the file", s.Schema(d, openapi.Ref("ImportError"), false))
+	r.Content["text/csv"] = openapi.MediaType{Schema: &openapi.Schema{
+		Type:        "string",
+		Description: "Header and one row per error, separated by ;, sent as an attachment",
+		Example:     "row;name;status;error\n3;Hoth;400;non-existent planet\n",
+	}}
+
+	return openapi.Operation{
+		Tags:       []string{"imports"},
+		Summary:    "Error report of an import",
+		Parameters: []openapi.Parameter{importID},
+		Responses: map[string]*openapi.Response{
+			"200": r,
+			"404": notFound(),
+		},
+	}
+}
+
+func getJob() openapi.Operation {
+	return openapi.Operation{
+		Tags:       []string{"jobs"},
+		Summary:    "Status of a job, with the planet or the error once it is done",
+		Parameters: []openapi.Parameter{jobID},
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Reply("Ok", openapi.Ref("Job")),
+			"404": notFound(),
+		},
+	}
+}
+
+var atomic = openapi.Query("atomic", "All the items or none - Default false, needs a replica set", openapi.Boolean())
+
+// batchBody items of a batch, at most planets.batch-size of them
+func batchBody(item string) *openapi.RequestBody {
+	body := openapi.Body(openapi.Array(openapi.Ref(item)))
+	body.Description = "At most planets.batch-size items, 100 by default"
+	return body
+}
+
+func postPlanets(d *openapi.Document, s serializer.Serializer) openapi.Operation {
+	return openapi.Operation{
+		Tags:        []string{"planets"},
+		Summary:     "Add planets in a batch",
+		Parameters:  []openapi.Parameter{atomic},
+		RequestBody: batchBody("PlanetPost"),
+		Responses: map[string]*openapi.Response{
+			"207": openapi.Reply("Outcome of each planet, in the order sent", s.Schema(d, openapi.Ref("BatchResult"), false)),
+			"400": badRequest(),
+		},
+	}
+}
+
+func deletePlanets(d *openapi.Document, s serializer.Serializer) openapi.Operation {
+	return openapi.Operation{
+		Tags:        []string{"planets"},
+		Summary:     "Delete planets in a batch - soft delete, can be restored",
+		Parameters:  []openapi.Parameter{atomic},
+		RequestBody: batchBody("PlanetDelete"),
+		Responses: map[string]*openapi.Response{
+			"207": openapi.Reply("Outcome of each planet, in the order sent", s.Schema(d, openapi.Ref("BatchResult"), false)),
+			"400": badRequest(),
+		},
+	}
+}
+
+func putPlanet() openapi.Operation {
+	return openapi.Operation{
+		Tags:        []string{"planets"},
+		Summary:     "Update planet",
+		Parameters:  []openapi.Parameter{planetID, openapi.ParameterRef("IfMatch")},
+		RequestBody: openapi.Body(openapi.Ref("PlanetPost")),
+		Responses: map[string]*openapi.Response{
+			"200": planetReply("Ok"),
+			"400": badRequest(),
+			"404": notFound(),
+			"412": preconditionFailed(),
+		},
+	}
+}
+
+func deletePlanet() openapi.Operation {
+	return openapi.Operation{
+		Tags:       []string{"planets"},
+		Summary:    "Delete planet - soft delete, can be restored",
+		Parameters: []openapi.Parameter{planetID, openapi.ParameterRef("IfMatch")},
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Reply("Ok", nil),
+			"400": badRequest(),
+			"404": notFound(),
+			"412": preconditionFailed(),
+		},
+	}
+}
+
+func restorePlanet() openapi.Operation {
+	return openapi.Operation{
+		Tags:       []string{"planets"},
+		Summary:    "Restore a deleted planet",
+		Parameters: []openapi.Parameter{planetID, openapi.ParameterRef("IfMatch")},
+		Responses: map[string]*openapi.Response{
+			"200": planetReply("Ok"),
+			"400": badRequest(),
+			"404": notFound(),
+			"412": preconditionFailed(),
+		},
+	}
+}
+
+func purgePlanets() openapi.Operation {
+	retention := openapi.Query("retention", "Retention window - Default from config", openapi.String())
+	retention.Example = "720h"
+
+	return openapi.Operation{
+		Tags:       []string{"planets"},
+		Summary:    "Permanently remove planets deleted before the retention window",
+		Parameters: []openapi.Parameter{retention},
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Reply("Ok", openapi.Object(map[string]*openapi.Schema{"purged": openapi.Integer()})),
+			"400": badRequest(),
+		},
+	}
+}
+
+func planetHistory(d *openapi.Document, s serializer.Serializer) openapi.Operation {
+	return openapi.Operation{
+		Tags:       []string{"audit"},
+		Summary:    "List the changes of a planet",
+		Parameters: append(append([]openapi.Parameter{planetID}, period()...), page("20")...),
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Reply("Ok", s.Schema(d, openapi.Ref("AuditEntry"), true)),
+			"400": badRequest(),
+		},
+	}
+}
+
+func listAudit(d *openapi.Document, s serializer.Serializer) openapi.Operation {
+	filters := []openapi.Parameter{
+		openapi.Query("planetId", "Planet ID", openapi.String()),
+		openapi.Query("actor", "API key name or token subject", openapi.String()),
+		openapi.Query("action", "", openapi.Enum(entity.ActionCreate, entity.ActionUpdate, entity.ActionDelete, entity.ActionRestore, entity.ActionPurge)),
+	}
+
+	return openapi.Operation{
+		Tags:       []string{"audit"},
+		Summary:    "List audit entries",
+		Parameters: append(append(filters, period()...), page("20")...),
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Reply("Ok", s.Schema(d, openapi.Ref("AuditEntry"), true)),
+			"400": badRequest(),
+		},
+	}
+}
+
+func terminology(d *openapi.Document, s serializer.Serializer, field string) openapi.Operation {
+	return openapi.Operation{
+		Tags:    []string{"planets"},
+		Summary: "List the " + field + " in use",
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Reply("Ok", s.Schema(d, openapi.String(), false)),
+		},
+	}
+}
+
+func postAPIKey() openapi.Operation {
+	return openapi.Operation{
+		Tags:        []string{"api-keys"},
+		Summary:     "Issue an API key - the secret is only returned here and on rotation",
+		RequestBody: openapi.Body(openapi.Ref("APIKeyPost")),
+		Responses: map[string]*openapi.Response{
+			"201": secret("Created"),
+			"400": badRequest(),
+		},
+	}
+}
+
+func listAPIKeys(d *openapi.Document, s serializer.Serializer) openapi.Operation {
+	return openapi.Operation{
+		Tags:    []string{"api-keys"},
+		Summary: "List API keys without their secrets",
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Reply("Ok", s.Schema(d, openapi.Ref("APIKey"), false)),
+		},
+	}
+}
+
+func rotateAPIKey() openapi.Operation {
+	return openapi.Operation{
+		Tags:       []string{"api-keys"},
+		Summary:    "Replace the secret of an API key, the old one stops working at once",
+		Parameters: []openapi.Parameter{apiKeyID},
+		Responses: map[string]*openapi.Response{
+			"200": secret("Ok"),
+			"404": openapi.Reply("Not found or already revoked", openapi.Ref("Error")),
+		},
+	}
+}
+
+func revokeAPIKey() openapi.Operation {
+	return openapi.Operation{
+		Tags:       []string{"api-keys"},
+		Summary:    "Revoke an API key",
+		Parameters: []openapi.Parameter{apiKeyID},
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Reply("Ok", nil),
+			"404": openapi.Reply("Not found or already revoked", openapi.Ref("Error")),
+		},
+	}
+}
+
+func graphQL() openapi.Operation {
+	op := requires(auth.RoleReader, resourceOp(openapi.Operation{
+		Tags:    []string{"graphql"},
+		Summary: "Run a GraphQL query or mutation",
+		RequestBody: openapi.Body(openapi.Object(map[string]*openapi.Schema{
+			"query":         {Type: "string", Example: "{ planets(limit: 2) { name films { title } } }"},
+			"operationName": openapi.String(),
+			"variables":     {Type: "object"},
+		}, "query")),
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Reply("The data resolved and the errors met, coded in their extensions", openapi.Object(map[string]*openapi.Schema{
+				"data":   {Type: "object", Nullable: true},
+				"errors": openapi.Array(&openapi.Schema{Type: "object"}),
+			})),
+			"400": badRequest(),
+		},
+	}))
+	op.Description += ", mutations the editor role"
+	return op
+}
+
+func getSpec() openapi.Operation {
+	return openapi.Operation{
+		Tags:    []string{"docs"},
+		Summary: "OpenAPI document of this version",
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Reply("Ok", &openapi.Schema{Type: "object"}),
+		},
+	}
+}
+
+func healthCheck() openapi.Operation {
+	return openapi.Operation{
+		Tags:       []string{"health"},
+		Summary:    "Health of the database, replaced by /readyz",
+		Deprecated: true,
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Reply("Ok", openapi.Ref("HealthCheck")),
+			"500": openapi.Reply("The database is down", openapi.Ref("HealthCheck")),
+		},
+	}
+}
+
+func livez() openapi.Operation {
+	return openapi.Operation{
+		Tags:    []string{"health"},
+		Summary: "Liveness - the process is up",
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Reply("Ok", openapi.Object(map[string]*openapi.Schema{"status": {Type: "string", Example: "ok"}})),
+		},
+	}
+}
+
+func readyz() openapi.Operation {
+	return openapi.Operation{
+		Tags:    []string{"health"},
+		Summary: "Readiness - critical failures answer 503, others degrade the status",
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Reply("Ok or degraded", openapi.Ref("HealthCheck")),
+			"503": openapi.Reply("A critical dependency is down", openapi.Ref("HealthCheck")),
+		},
+	}
+}
+
+func metricsOp() openapi.Operation {
+	return openapi.Operation{
+		Tags:    []string{"health"},
+		Summary: "Prometheus metrics",
+		Responses: map[string]*openapi.Response{
+			"200": {Description: "Ok", Content: map[string]openapi.MediaType{"text/plain": {Schema: openapi.String()}}},
+		},
+	}
+}
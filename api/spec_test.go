@@ -0,0 +1,123 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"star-wars/api/openapi"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "rewrite docs/open-api from the routes")
+
+func served(t *testing.T, router *gin.Engine, version string) []byte {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/"+version+"/openapi.json", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("/%s/openapi.json answered %d", version, w.Code)
+	}
+
+	return w.Body.Bytes()
+}
+
+// TestSpec fails when the routes change and docs/open-api was not
+// regenerated with make openapi
+func TestSpec(t *testing.T) {
+	router := Config()
+
+	for _, version := range []string{"v1", "v2"} {
+		t.Run(version, func(t *testing.T) {
+			got := served(t, router, version)
+			file := filepath.Join("..", "docs", "open-api", version+".json")
+
+			if *update {
+				var indented bytes.Buffer
+				if err := json.Indent(&indented, got, "", "  "); err != nil {
+					t.Fatal(err)
+				}
+				indented.WriteString("\n")
+
+				if err := ioutil.WriteFile(file, indented.Bytes(), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.JSONEq(t, string(want), string(got), "%s is stale, run make openapi", file)
+		})
+	}
+}
+
+// TestRoutesDocumented fails when a route is registered around the specs or
+// the specs document a route the router does not serve
+func TestRoutesDocumented(t *testing.T) {
+	router := Config()
+	docs := map[string]*openapi.Document{}
+
+	for _, version := range []string{"v1", "v2"} {
+		var d openapi.Document
+		if err := json.Unmarshal(served(t, router, version), &d); err != nil {
+			t.Fatal(err)
+		}
+		docs["/"+version] = &d
+	}
+
+	routes := map[string]bool{}
+
+	for _, r := range router.Routes() {
+		routes[r.Method+" "+r.Path] = true
+
+		d, path := docs["/v1"], r.Path
+		for prefix, doc := range docs {
+			if strings.HasPrefix(r.Path, prefix+"/") {
+				d, path = doc, strings.TrimPrefix(r.Path, prefix)
+			}
+		}
+
+		// unversioned routes are probes, documented everywhere, or v1 aliases
+		assert.NotNil(t, d.Operation(r.Method, openapi.Path(path)), "%s %s is not documented", r.Method, r.Path)
+	}
+
+	for prefix, d := range docs {
+		for path, item := range d.Paths {
+			base := prefix
+			if len(item.Servers) > 0 {
+				base = strings.TrimSuffix(item.Servers[0].URL, "/")
+			}
+
+			for method, op := range map[string]*openapi.Operation{
+				http.MethodGet:    item.Get,
+				http.MethodPut:    item.Put,
+				http.MethodPost:   item.Post,
+				http.MethodDelete: item.Delete,
+				http.MethodPatch:  item.Patch,
+			} {
+				if op == nil {
+					continue
+				}
+
+				route := method + " " + base + ginPath(path)
+				assert.True(t, routes[route], "%s is documented but not served", route)
+			}
+		}
+	}
+}
+
+// ginPath converts /planets/{id} back to /planets/:id and /planets:batch to
+// the path its custom method is registered with
+func ginPath(path string) string {
+	return strings.NewReplacer("{", ":", "}", "", ":", "%3A").Replace(path)
+}
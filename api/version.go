@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/http"
+	"star-wars/api/openapi"
+	"star-wars/api/serializer"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// version of the API mounted under prefix
+type version struct {
+	prefix     string
+	serializer serializer.Serializer
+	// deprecation and sunset are sent once set, successor is the prefix replacing this one
+	deprecation time.Time
+	sunset      time.Time
+	successor   string
+	// doc is served by every mount of the version and documented by the canonical one
+	doc     *openapi.Document
+	aliased bool
+}
+
+// alias mounts v at the root too
+func (v version) alias() version {
+	v.prefix = ""
+	v.aliased = true
+	return v
+}
+
+// router registers on g, documenting in the spec unless v is an alias
+func (v version) router(g gin.IRoutes) *openapi.Router {
+	if v.aliased {
+		return openapi.NewRouter(g)
+	}
+	return openapi.NewRouter(g, v.doc)
+}
+
+// headers announces the deprecation (RFC 9745) and sunset (RFC 8594) of the version
+func (v version) headers(c *gin.Context) {
+	if !v.deprecation.IsZero() {
+		c.Header("Deprecation", "@"+strconv.FormatInt(v.deprecation.Unix(), 10))
+
+		if v.successor != "" {
+			path := v.successor + strings.TrimPrefix(c.Request.URL.Path, v.prefix)
+			c.Header("Link", "<"+path+`>; rel="successor-version"`)
+		}
+	}
+
+	if !v.sunset.IsZero() {
+		c.Header("Sunset", v.sunset.UTC().Format(http.TimeFormat))
+	}
+
+	c.Next()
+}
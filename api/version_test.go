@@ -0,0 +1,71 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionHeaders(t *testing.T) {
+	deprecated := version{
+		prefix:      "/v1",
+		deprecation: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		sunset:      time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC),
+		successor:   "/v2",
+	}
+
+	type test struct {
+		name            string
+		version         version
+		path            string
+		wantDeprecation string
+		wantSunset      string
+		wantLink        string
+	}
+
+	tests := []test{
+		{
+			name:            "when the version is deprecated",
+			version:         deprecated,
+			path:            "/v1/planets/5f2c891e9a9e070b1ef2e28c",
+			wantDeprecation: "@1609459200",
+			wantSunset:      "Thu, 01 Jul 2021 00:00:00 GMT",
+			wantLink:        `</v2/planets/5f2c891e9a9e070b1ef2e28c>; rel="successor-version"`,
+		},
+		{
+			name:            "when the unversioned alias is deprecated",
+			version:         deprecated.alias(),
+			path:            "/planets",
+			wantDeprecation: "@1609459200",
+			wantSunset:      "Thu, 01 Jul 2021 00:00:00 GMT",
+			wantLink:        `</v2/planets>; rel="successor-version"`,
+		},
+		{
+			name:    "when the version is current",
+			version: version{prefix: "/v2"},
+			path:    "/v2/planets",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Group(tt.version.prefix, tt.version.headers).GET("/planets/*rest", func(c *gin.Context) { c.Status(http.StatusOK) })
+			r.Group(tt.version.prefix, tt.version.headers).GET("/planets", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+
+			assert.Equal(t, 200, w.Code)
+			assert.Equal(t, tt.wantDeprecation, w.Header().Get("Deprecation"))
+			assert.Equal(t, tt.wantSunset, w.Header().Get("Sunset"))
+			assert.Equal(t, tt.wantLink, w.Header().Get("Link"))
+		})
+	}
+}
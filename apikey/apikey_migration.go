@@ -0,0 +1,25 @@
+package apikey
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EnsureIndexes creates the unique index keys are looked up by
+func EnsureIndexes(ctx context.Context) error {
+	coll, err := cnx(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "hash", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	return err
+}
@@ -0,0 +1,180 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"star-wars/database"
+	"star-wars/entity"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Repository contract
+type Repository interface {
+	Insert(ctx context.Context, key *entity.APIKey) error
+	FindAll(ctx context.Context) (*[]entity.APIKey, error)
+	FindByHash(ctx context.Context, hash string) (*entity.APIKey, error)
+	Rotate(ctx context.Context, id string, prefix string, hash string, at time.Time) (*entity.APIKey, error)
+	Revoke(ctx context.Context, id string, at time.Time) error
+	Touch(ctx context.Context, id string, at time.Time) error
+}
+
+// ErrNotFound the key does not exist or was already revoked
+var ErrNotFound = errors.New("api key not found")
+
+type repo struct{}
+
+// NewRepository api keys
+func NewRepository() Repository {
+	return &repo{}
+}
+
+// cnx is patched in tests, keep it out of line
+//
+//go:noinline
+func cnx(ctx context.Context) (*mongo.Collection, error) {
+	return database.Collection(ctx, "apikeys")
+}
+
+func (r repo) Insert(ctx context.Context, key *entity.APIKey) error {
+	coll, err := cnx(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	result, err := coll.InsertOne(ctx, key)
+
+	if err != nil {
+		return err
+	}
+
+	oid, _ := result.InsertedID.(primitive.ObjectID)
+	key.ID = oid.Hex()
+
+	return nil
+}
+
+func (r repo) FindAll(ctx context.Context) (*[]entity.APIKey, error) {
+	coll, err := cnx(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	opt := options.Find()
+	opt.SetSort(bson.D{{Key: "createdAt", Value: -1}})
+
+	cr, err := coll.Find(ctx, bson.M{}, opt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keys := &[]entity.APIKey{}
+
+	err = cr.All(ctx, keys)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (r repo) FindByHash(ctx context.Context, hash string) (*entity.APIKey, error) {
+	coll, err := cnx(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var key entity.APIKey
+
+	err = coll.FindOne(ctx, bson.M{"hash": hash}).Decode(&key)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func (r repo) Rotate(ctx context.Context, id string, prefix string, hash string, at time.Time) (*entity.APIKey, error) {
+	_id, err := primitive.ObjectIDFromHex(id)
+
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	coll, err := cnx(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	opt := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var key entity.APIKey
+
+	err = coll.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": _id, "revokedAt": nil},
+		bson.M{"$set": bson.M{"prefix": prefix, "hash": hash, "rotatedAt": at}},
+		opt,
+	).Decode(&key)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func (r repo) Revoke(ctx context.Context, id string, at time.Time) error {
+	return r.set(ctx, id, bson.M{"revokedAt": nil}, bson.M{"revokedAt": at})
+}
+
+func (r repo) Touch(ctx context.Context, id string, at time.Time) error {
+	return r.set(ctx, id, bson.M{}, bson.M{"lastUsedAt": at})
+}
+
+func (r repo) set(ctx context.Context, id string, filter bson.M, fields bson.M) error {
+	_id, err := primitive.ObjectIDFromHex(id)
+
+	if err != nil {
+		return ErrNotFound
+	}
+
+	coll, err := cnx(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	filter["_id"] = _id
+
+	result, err := coll.UpdateOne(ctx, filter, bson.M{"$set": fields})
+
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"star-wars/entity"
+	"star-wars/planet/monkey_patch/mongo_db"
+	"testing"
+	"time"
+
+	"bou.ke/monkey"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func monkeyCnx(guard *monkey.PatchGuard, err bool) *monkey.PatchGuard {
+	guard = monkey.Patch(cnx, func(ctx context.Context) (*mongo.Collection, error) {
+		if err {
+			return nil, errors.New("connection error")
+		}
+
+		c, _ := mongo.NewClient()
+		coll := c.Database("").Collection("")
+
+		return coll, nil
+	})
+	return guard
+}
+
+func TestInsert_Repository(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, false)
+
+		var guardInsertOne monkey.PatchGuard
+		mongo_db.InsertOne(&guardInsertOne, "5f3080961f4799f091e3c515", false)
+
+		key := &entity.APIKey{Name: "partner"}
+
+		repo := NewRepository()
+		err := repo.Insert(ctx, key)
+
+		assert.Equal(t, nil, err)
+		assert.Equal(t, "5f3080961f4799f091e3c515", key.ID)
+	})
+
+	t.Run("when connection error", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, true)
+
+		repo := NewRepository()
+		err := repo.Insert(ctx, &entity.APIKey{})
+
+		assert.Equal(t, "connection error", err.Error())
+	})
+}
+
+func TestFindAll_Repository(t *testing.T) {
+	var guardCnx monkey.PatchGuard
+	monkeyCnx(&guardCnx, false)
+
+	var guardFind monkey.PatchGuard
+	mongo_db.Find(&guardFind, false)
+
+	var guardAll monkey.PatchGuard
+	mongo_db.All(&guardAll, false)
+
+	repo := NewRepository()
+	_, err := repo.FindAll(ctx)
+
+	assert.Equal(t, nil, err)
+}
+
+func TestRevoke_Repository(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, false)
+
+		var guardUpdateOne monkey.PatchGuard
+		mongo_db.UpdateOne(&guardUpdateOne, 1, false)
+
+		repo := NewRepository()
+		err := repo.Revoke(ctx, "5f3080961f4799f091e3c515", time.Now())
+
+		assert.Equal(t, nil, err)
+	})
+
+	t.Run("when the key is missing or already revoked", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, false)
+
+		var guardUpdateOne monkey.PatchGuard
+		mongo_db.UpdateOne(&guardUpdateOne, 0, false)
+
+		repo := NewRepository()
+		err := repo.Revoke(ctx, "5f3080961f4799f091e3c515", time.Now())
+
+		assert.Equal(t, ErrNotFound, err)
+	})
+
+	t.Run("when id is invalid", func(t *testing.T) {
+		repo := NewRepository()
+		err := repo.Revoke(ctx, "partner", time.Now())
+
+		assert.Equal(t, ErrNotFound, err)
+	})
+}
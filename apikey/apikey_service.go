@@ -0,0 +1,191 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"star-wars/api/handler"
+	"star-wars/auth"
+	"star-wars/entity"
+	"star-wars/logger"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// keyPrefix marks keys issued by this service, anything else skips the database
+const keyPrefix = "sw_"
+
+// touchInterval limits lastUsedAt writes to one per key and interval
+const touchInterval = time.Minute
+
+// Service contract
+type Service interface {
+	Create(ctx context.Context, key *entity.APIKey) error
+	List(ctx context.Context) (*[]entity.APIKey, error)
+	Rotate(ctx context.Context, id string) (*entity.APIKey, error)
+	Revoke(ctx context.Context, id string) error
+	Lookup(ctx context.Context, key string) (auth.Principal, bool, error)
+}
+
+type srv struct {
+	repo Repository
+	now  func() time.Time
+}
+
+// NewService returns an api key service instance
+func NewService(r Repository) Service {
+	return &srv{
+		repo: r,
+		now:  func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// generate a new secret and the prefix shown to tell keys apart
+func generate() (key string, prefix string, err error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	key = keyPrefix + hex.EncodeToString(b)
+	return key, key[:len(keyPrefix)+8], nil
+}
+
+// Create issues a key; the secret is only returned here and on Rotate
+func (s srv) Create(ctx context.Context, key *entity.APIKey) error {
+	key.Name = strings.TrimSpace(key.Name)
+
+	if key.Name == "" {
+		return handler.BadRequest{Message: "name is invalid"}
+	}
+
+	if len(key.Scopes) == 0 {
+		return handler.BadRequest{Message: "scopes are required"}
+	}
+
+	for _, scope := range key.Scopes {
+		if !entity.ValidScope(scope) {
+			return handler.BadRequest{Message: "scope " + scope + " is invalid"}
+		}
+	}
+
+	now := s.now()
+
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(now) {
+		return handler.BadRequest{Message: "expiresAt must be in the future"}
+	}
+
+	secret, prefix, err := generate()
+	if err != nil {
+		return handler.InternalServer{Message: err.Error()}
+	}
+
+	key.ID = ""
+	key.Prefix = prefix
+	key.Hash = hash(secret)
+	key.CreatedAt = &now
+	key.RotatedAt = nil
+	key.LastUsedAt = nil
+	key.RevokedAt = nil
+
+	if err := s.repo.Insert(ctx, key); err != nil {
+		return handler.InternalServer{Message: err.Error()}
+	}
+
+	key.Key = secret
+
+	return nil
+}
+
+// List every key, revoked and expired ones included
+func (s srv) List(ctx context.Context) (*[]entity.APIKey, error) {
+	keys, err := s.repo.FindAll(ctx)
+
+	if err != nil {
+		return nil, handler.InternalServer{Message: err.Error()}
+	}
+
+	return keys, nil
+}
+
+// Rotate replaces the secret of a key, the old one stops working at once
+func (s srv) Rotate(ctx context.Context, id string) (*entity.APIKey, error) {
+	secret, prefix, err := generate()
+	if err != nil {
+		return nil, handler.InternalServer{Message: err.Error()}
+	}
+
+	key, err := s.repo.Rotate(ctx, id, prefix, hash(secret), s.now())
+
+	if err != nil {
+		return nil, repoError(err)
+	}
+
+	key.Key = secret
+
+	return key, nil
+}
+
+// Revoke disables a key for good
+func (s srv) Revoke(ctx context.Context, id string) error {
+	if err := s.repo.Revoke(ctx, id, s.now()); err != nil {
+		return repoError(err)
+	}
+	return nil
+}
+
+// Lookup resolves an active key to its principal: planets:write grants the
+// editor role, planets:read the reader role
+func (s srv) Lookup(ctx context.Context, secret string) (auth.Principal, bool, error) {
+	if !strings.HasPrefix(secret, keyPrefix) {
+		return auth.Principal{}, false, nil
+	}
+
+	key, err := s.repo.FindByHash(ctx, hash(secret))
+
+	if err == ErrNotFound {
+		return auth.Principal{}, false, nil
+	}
+
+	if err != nil {
+		return auth.Principal{}, false, err
+	}
+
+	now := s.now()
+
+	if !key.Active(now) {
+		return auth.Principal{}, false, nil
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= touchInterval {
+		if err := s.repo.Touch(ctx, key.ID, now); err != nil {
+			logger.FromContext(ctx).Warn("api key last use not recorded", zap.String("apiKey", key.ID), zap.Error(err))
+		}
+	}
+
+	p := auth.Principal{Subject: "apikey:" + key.ID, Name: key.Name, Method: "api-key"}
+
+	switch {
+	case key.HasScope(entity.ScopePlanetsWrite):
+		p.Role = auth.RoleEditor
+	case key.HasScope(entity.ScopePlanetsRead):
+		p.Role = auth.RoleReader
+	}
+
+	return p, true, nil
+}
+
+func repoError(err error) error {
+	if err == ErrNotFound {
+		return handler.NotFound{Message: err.Error()}
+	}
+	return handler.InternalServer{Message: err.Error()}
+}
@@ -0,0 +1,218 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"star-wars/api/handler"
+	"star-wars/apikey/mock_apikey"
+	"star-wars/auth"
+	"star-wars/entity"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	ctx, cancel = context.WithTimeout(context.Background(), 2*time.Second)
+	now         = time.Date(2020, 8, 7, 10, 0, 0, 0, time.UTC)
+)
+
+func configDep(t *testing.T) (*gomock.Controller, *mock_apikey.MockRepository, Service) {
+	c := gomock.NewController(t)
+	r := mock_apikey.NewMockRepository(c)
+	return c, r, &srv{repo: r, now: func() time.Time { return now }}
+}
+
+func TestCreate(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		c, r, s := configDep(t)
+		defer c.Finish()
+		defer cancel()
+
+		var stored string
+
+		r.EXPECT().Insert(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, key *entity.APIKey) error {
+			assert.Equal(t, "", key.Key)
+			stored = key.Hash
+			key.ID = "5f3080961f4799f091e3c515"
+			return nil
+		})
+
+		key := &entity.APIKey{Name: " partner ", Scopes: []string{entity.ScopePlanetsRead}}
+		err := s.Create(ctx, key)
+
+		assert.Equal(t, nil, err)
+		assert.Equal(t, "partner", key.Name)
+		assert.True(t, strings.HasPrefix(key.Key, keyPrefix))
+		assert.Equal(t, key.Key[:len(keyPrefix)+8], key.Prefix)
+		assert.Equal(t, hash(key.Key), stored)
+		assert.Equal(t, &now, key.CreatedAt)
+	})
+
+	invalid := []struct {
+		name string
+		key  entity.APIKey
+		want error
+	}{
+		{"when name is empty", entity.APIKey{Scopes: []string{entity.ScopePlanetsRead}}, handler.BadRequest{Message: "name is invalid"}},
+		{"when scopes are missing", entity.APIKey{Name: "partner"}, handler.BadRequest{Message: "scopes are required"}},
+		{"when a scope is unknown", entity.APIKey{Name: "partner", Scopes: []string{"planets:delete"}}, handler.BadRequest{Message: "scope planets:delete is invalid"}},
+		{"when the key is already expired", entity.APIKey{Name: "partner", Scopes: []string{entity.ScopePlanetsRead}, ExpiresAt: &now}, handler.BadRequest{Message: "expiresAt must be in the future"}},
+	}
+
+	for _, tt := range invalid {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, s := configDep(t)
+			defer c.Finish()
+
+			err := s.Create(ctx, &tt.key)
+
+			assert.Equal(t, tt.want, err)
+		})
+	}
+
+	t.Run("when db returns error", func(t *testing.T) {
+		c, r, s := configDep(t)
+		defer c.Finish()
+
+		r.EXPECT().Insert(ctx, gomock.Any()).Return(errors.New("insert error"))
+
+		key := &entity.APIKey{Name: "partner", Scopes: []string{entity.ScopePlanetsRead}}
+		err := s.Create(ctx, key)
+
+		assert.Equal(t, handler.InternalServer{Message: "insert error"}, err)
+		assert.Equal(t, "", key.Key)
+	})
+}
+
+func TestRotate(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		c, r, s := configDep(t)
+		defer c.Finish()
+
+		var stored string
+
+		r.EXPECT().Rotate(ctx, "5f3080961f4799f091e3c515", gomock.Any(), gomock.Any(), now).DoAndReturn(
+			func(_ context.Context, id string, prefix string, hash string, at time.Time) (*entity.APIKey, error) {
+				stored = hash
+				return &entity.APIKey{ID: id, Prefix: prefix, Hash: hash, RotatedAt: &at}, nil
+			})
+
+		key, err := s.Rotate(ctx, "5f3080961f4799f091e3c515")
+
+		assert.Equal(t, nil, err)
+		assert.Equal(t, hash(key.Key), stored)
+	})
+
+	t.Run("when the key is revoked or missing", func(t *testing.T) {
+		c, r, s := configDep(t)
+		defer c.Finish()
+
+		r.EXPECT().Rotate(ctx, "5f3080961f4799f091e3c515", gomock.Any(), gomock.Any(), now).Return(nil, ErrNotFound)
+
+		_, err := s.Rotate(ctx, "5f3080961f4799f091e3c515")
+
+		assert.Equal(t, handler.NotFound{Message: "api key not found"}, err)
+	})
+}
+
+func TestRevoke(t *testing.T) {
+	c, r, s := configDep(t)
+	defer c.Finish()
+
+	r.EXPECT().Revoke(ctx, "5f3080961f4799f091e3c515", now).Return(nil)
+	r.EXPECT().Revoke(ctx, "5f3080961f4799f091e3c516", now).Return(ErrNotFound)
+	r.EXPECT().Revoke(ctx, "5f3080961f4799f091e3c517", now).Return(errors.New("update one error"))
+
+	assert.Equal(t, nil, s.Revoke(ctx, "5f3080961f4799f091e3c515"))
+	assert.Equal(t, handler.NotFound{Message: "api key not found"}, s.Revoke(ctx, "5f3080961f4799f091e3c516"))
+	assert.Equal(t, handler.InternalServer{Message: "update one error"}, s.Revoke(ctx, "5f3080961f4799f091e3c517"))
+}
+
+func TestLookup(t *testing.T) {
+	const secret = keyPrefix + "0123456789abcdef"
+
+	recently := now.Add(-time.Second)
+	past := now.Add(-time.Hour)
+
+	type test struct {
+		name      string
+		secret    string
+		key       *entity.APIKey
+		err       error
+		touch     bool
+		want      auth.Principal
+		wantFound bool
+		wantErr   error
+	}
+
+	tests := []test{
+		{
+			name:      "when the key can write",
+			secret:    secret,
+			key:       &entity.APIKey{ID: "1", Name: "partner", Scopes: []string{entity.ScopePlanetsRead, entity.ScopePlanetsWrite}},
+			touch:     true,
+			want:      auth.Principal{Subject: "apikey:1", Name: "partner", Role: auth.RoleEditor, Method: "api-key"},
+			wantFound: true,
+		},
+		{
+			name:      "when the key was used a moment ago",
+			secret:    secret,
+			key:       &entity.APIKey{ID: "1", Name: "partner", Scopes: []string{entity.ScopePlanetsRead}, LastUsedAt: &recently},
+			want:      auth.Principal{Subject: "apikey:1", Name: "partner", Role: auth.RoleReader, Method: "api-key"},
+			wantFound: true,
+		},
+		{
+			name:   "when the key is revoked",
+			secret: secret,
+			key:    &entity.APIKey{ID: "1", Name: "partner", Scopes: []string{entity.ScopePlanetsRead}, RevokedAt: &past},
+		},
+		{
+			name:   "when the key is expired",
+			secret: secret,
+			key:    &entity.APIKey{ID: "1", Name: "partner", Scopes: []string{entity.ScopePlanetsRead}, ExpiresAt: &past},
+		},
+		{
+			name:   "when the key is unknown",
+			secret: secret,
+			err:    ErrNotFound,
+		},
+		{
+			name:    "when db returns error",
+			secret:  secret,
+			err:     errors.New("decode error"),
+			wantErr: errors.New("decode error"),
+		},
+		{
+			name:   "when the key was not issued by us",
+			secret: "static-key",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			c, r, s := configDep(t)
+			defer c.Finish()
+
+			if strings.HasPrefix(tt.secret, keyPrefix) {
+				r.EXPECT().FindByHash(ctx, hash(tt.secret)).Return(tt.key, tt.err)
+			}
+			if tt.touch {
+				r.EXPECT().Touch(ctx, tt.key.ID, now).Return(nil)
+			}
+
+			got, found, err := s.Lookup(ctx, tt.secret)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantFound, found)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
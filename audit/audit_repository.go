@@ -0,0 +1,112 @@
+package audit
+
+import (
+	"context"
+	"star-wars/database"
+	"star-wars/entity"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Repository contract
+type Repository interface {
+	Insert(ctx context.Context, entry *entity.AuditEntry) error
+	Find(ctx context.Context, filter entity.AuditFilter) (*[]entity.AuditEntry, error)
+}
+
+type repo struct{}
+
+// NewRepository audit
+func NewRepository() Repository {
+	return &repo{}
+}
+
+// cnx is patched in tests, keep it out of line
+//
+//go:noinline
+func cnx(ctx context.Context) (*mongo.Collection, error) {
+	return database.Collection(ctx, "audit")
+}
+
+func (r repo) Insert(ctx context.Context, entry *entity.AuditEntry) error {
+	coll, err := cnx(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	result, err := coll.InsertOne(ctx, entry)
+
+	if err != nil {
+		return err
+	}
+
+	oid, _ := result.InsertedID.(primitive.ObjectID)
+	entry.ID = oid.Hex()
+
+	return nil
+}
+
+func (r repo) Find(ctx context.Context, filter entity.AuditFilter) (*[]entity.AuditEntry, error) {
+	coll, err := cnx(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	opt := options.Find()
+	opt.SetSort(bson.D{{Key: "timestamp", Value: -1}})
+	opt.SetLimit(filter.Limit)
+	opt.SetSkip(filter.Skip)
+
+	cr, err := coll.Find(ctx, query(filter), opt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	entries := &[]entity.AuditEntry{}
+
+	err = cr.All(ctx, entries)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func query(filter entity.AuditFilter) bson.M {
+	q := bson.M{}
+
+	if filter.PlanetID != "" {
+		q["planetId"] = filter.PlanetID
+	}
+
+	if filter.Actor != "" {
+		q["actor"] = filter.Actor
+	}
+
+	if filter.Action != "" {
+		q["action"] = filter.Action
+	}
+
+	timestamp := bson.M{}
+
+	if !filter.From.IsZero() {
+		timestamp["$gte"] = filter.From
+	}
+
+	if !filter.To.IsZero() {
+		timestamp["$lte"] = filter.To
+	}
+
+	if len(timestamp) > 0 {
+		q["timestamp"] = timestamp
+	}
+
+	return q
+}
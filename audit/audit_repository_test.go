@@ -0,0 +1,141 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"star-wars/entity"
+	"star-wars/planet/monkey_patch/mongo_db"
+	"testing"
+	"time"
+
+	"bou.ke/monkey"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func monkeyCnx(guard *monkey.PatchGuard, err bool) *monkey.PatchGuard {
+	guard = monkey.Patch(cnx, func(ctx context.Context) (*mongo.Collection, error) {
+		if err {
+			return nil, errors.New("connection error")
+		}
+
+		c, _ := mongo.NewClient()
+		coll := c.Database("").Collection("")
+
+		return coll, nil
+	})
+	return guard
+}
+
+func TestInsert_Repository(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, false)
+
+		var guardInsertOne monkey.PatchGuard
+		mongo_db.InsertOne(&guardInsertOne, "5f3080961f4799f091e3c515", false)
+
+		defer cancel()
+
+		entry := &entity.AuditEntry{Action: entity.ActionCreate}
+
+		repo := NewRepository()
+		err := repo.Insert(ctx, entry)
+
+		assert.Equal(t, nil, err)
+		assert.Equal(t, "5f3080961f4799f091e3c515", entry.ID)
+	})
+
+	t.Run("when insert one returns error", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, false)
+
+		var guardInsertOne monkey.PatchGuard
+		mongo_db.InsertOne(&guardInsertOne, "", true)
+
+		defer cancel()
+
+		repo := NewRepository()
+		err := repo.Insert(ctx, &entity.AuditEntry{})
+
+		assert.Equal(t, "insert error", err.Error())
+	})
+
+	t.Run("when connection error", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, true)
+
+		defer cancel()
+
+		repo := NewRepository()
+		err := repo.Insert(ctx, &entity.AuditEntry{})
+
+		assert.Equal(t, "connection error", err.Error())
+	})
+}
+
+func TestFind_Repository(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, false)
+
+		var guardFind monkey.PatchGuard
+		mongo_db.Find(&guardFind, false)
+
+		var guardAll monkey.PatchGuard
+		mongo_db.All(&guardAll, false)
+
+		defer cancel()
+
+		repo := NewRepository()
+		_, err := repo.Find(ctx, entity.AuditFilter{Limit: 20})
+
+		assert.Equal(t, nil, err)
+	})
+
+	t.Run("when find returns error", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, false)
+
+		var guardFind monkey.PatchGuard
+		mongo_db.Find(&guardFind, true)
+
+		defer cancel()
+
+		repo := NewRepository()
+		_, err := repo.Find(ctx, entity.AuditFilter{Limit: 20})
+
+		assert.Equal(t, "find error", err.Error())
+	})
+
+	t.Run("when connection error", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, true)
+
+		defer cancel()
+
+		repo := NewRepository()
+		_, err := repo.Find(ctx, entity.AuditFilter{Limit: 20})
+
+		assert.Equal(t, "connection error", err.Error())
+	})
+}
+
+func TestQuery(t *testing.T) {
+	from := time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	q := query(entity.AuditFilter{
+		PlanetID: "5f3080961f4799f091e3c515",
+		Actor:    "luke",
+		Action:   entity.ActionDelete,
+		From:     from,
+	})
+
+	assert.Equal(t, bson.M{
+		"planetId":  "5f3080961f4799f091e3c515",
+		"actor":     "luke",
+		"action":    entity.ActionDelete,
+		"timestamp": bson.M{"$gte": from},
+	}, q)
+}
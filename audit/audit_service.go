@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"context"
+	"star-wars/api/handler"
+	"star-wars/entity"
+	"star-wars/requestid"
+	"time"
+)
+
+// Service contract
+type Service interface {
+	Record(ctx context.Context, action string, planetID string, before *entity.Planet, after *entity.Planet) error
+	Find(ctx context.Context, filter entity.AuditFilter) (*[]entity.AuditEntry, error)
+}
+
+type srv struct {
+	repo Repository
+}
+
+// NewService returns an audit service instance
+func NewService(r Repository) Service {
+	return &srv{
+		repo: r,
+	}
+}
+
+// Record appends an entry with the actor and request ID found in ctx
+func (s srv) Record(ctx context.Context, action string, planetID string, before *entity.Planet, after *entity.Planet) error {
+	entry := &entity.AuditEntry{
+		PlanetID:  planetID,
+		Action:    action,
+		Actor:     ActorFrom(ctx),
+		RequestID: requestid.From(ctx),
+		Timestamp: time.Now().UTC(),
+		Before:    before,
+		After:     after,
+	}
+
+	if err := s.repo.Insert(ctx, entry); err != nil {
+		return handler.InternalServer{Message: err.Error()}
+	}
+
+	return nil
+}
+
+// Find get audit entries
+func (s srv) Find(ctx context.Context, filter entity.AuditFilter) (*[]entity.AuditEntry, error) {
+	if filter.Limit <= 0 {
+		return nil, handler.BadRequest{Message: "limit is invalid"}
+	}
+
+	if filter.Skip < 0 {
+		return nil, handler.BadRequest{Message: "skip is invalid"}
+	}
+
+	entries, err := s.repo.Find(ctx, filter)
+	if err != nil {
+		return nil, handler.InternalServer{Message: err.Error()}
+	}
+
+	return entries, nil
+}
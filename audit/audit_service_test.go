@@ -0,0 +1,135 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"star-wars/api/handler"
+	"star-wars/audit/mock_audit"
+	"star-wars/entity"
+	"star-wars/requestid"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	ctx, cancel = context.WithTimeout(context.Background(), 2*time.Second)
+)
+
+func configDep(t *testing.T) (*gomock.Controller, *mock_audit.MockRepository) {
+	c := gomock.NewController(t)
+	r := mock_audit.NewMockRepository(c)
+	return c, r
+}
+
+func TestRecord(t *testing.T) {
+	planet := &entity.Planet{ID: "5f2c88567563c4bae600d7df", Name: "Tatooine"}
+
+	t.Run("happy path", func(t *testing.T) {
+		c, r := configDep(t)
+		defer c.Finish()
+		defer cancel()
+
+		reqCtx := requestid.With(WithActor(ctx, "luke"), "req-1")
+
+		r.EXPECT().Insert(reqCtx, gomock.Any()).DoAndReturn(func(_ context.Context, entry *entity.AuditEntry) error {
+			assert.Equal(t, "5f2c88567563c4bae600d7df", entry.PlanetID)
+			assert.Equal(t, entity.ActionCreate, entry.Action)
+			assert.Equal(t, "luke", entry.Actor)
+			assert.Equal(t, "req-1", entry.RequestID)
+			assert.Nil(t, entry.Before)
+			assert.Equal(t, planet, entry.After)
+			assert.False(t, entry.Timestamp.IsZero())
+			return nil
+		})
+
+		srv := NewService(r)
+		err := srv.Record(reqCtx, entity.ActionCreate, planet.ID, nil, planet)
+
+		assert.Equal(t, nil, err)
+	})
+
+	t.Run("when actor is missing", func(t *testing.T) {
+		c, r := configDep(t)
+		defer c.Finish()
+		defer cancel()
+
+		r.EXPECT().Insert(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, entry *entity.AuditEntry) error {
+			assert.Equal(t, Anonymous, entry.Actor)
+			return nil
+		})
+
+		srv := NewService(r)
+		err := srv.Record(ctx, entity.ActionDelete, planet.ID, planet, nil)
+
+		assert.Equal(t, nil, err)
+	})
+
+	t.Run("when db returns error", func(t *testing.T) {
+		c, r := configDep(t)
+		defer c.Finish()
+		defer cancel()
+
+		r.EXPECT().Insert(ctx, gomock.Any()).Return(errors.New("insert error"))
+
+		srv := NewService(r)
+		err := srv.Record(ctx, entity.ActionDelete, planet.ID, planet, nil)
+
+		assert.Equal(t, handler.InternalServer{Message: "insert error"}, err)
+	})
+}
+
+func TestFind(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		c, r := configDep(t)
+		defer c.Finish()
+		defer cancel()
+
+		filter := entity.AuditFilter{PlanetID: "5f2c88567563c4bae600d7df", Limit: 20}
+		expected := &[]entity.AuditEntry{{PlanetID: "5f2c88567563c4bae600d7df", Action: entity.ActionCreate}}
+		r.EXPECT().Find(ctx, filter).Return(expected, nil)
+
+		srv := NewService(r)
+		result, err := srv.Find(ctx, filter)
+
+		assert.Equal(t, nil, err)
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("when limit is invalid", func(t *testing.T) {
+		c, r := configDep(t)
+		defer c.Finish()
+		defer cancel()
+
+		srv := NewService(r)
+		_, err := srv.Find(ctx, entity.AuditFilter{})
+
+		assert.Equal(t, "limit is invalid", err.Error())
+	})
+
+	t.Run("when skip is invalid", func(t *testing.T) {
+		c, r := configDep(t)
+		defer c.Finish()
+		defer cancel()
+
+		srv := NewService(r)
+		_, err := srv.Find(ctx, entity.AuditFilter{Limit: 1, Skip: -1})
+
+		assert.Equal(t, "skip is invalid", err.Error())
+	})
+
+	t.Run("when db returns error", func(t *testing.T) {
+		c, r := configDep(t)
+		defer c.Finish()
+		defer cancel()
+
+		r.EXPECT().Find(ctx, gomock.Any()).Return(nil, errors.New("find error"))
+
+		srv := NewService(r)
+		_, err := srv.Find(ctx, entity.AuditFilter{Limit: 1})
+
+		assert.Equal(t, "internal server error", err.Error())
+	})
+}
@@ -0,0 +1,23 @@
+package audit
+
+import "context"
+
+type contextKey string
+
+const actorKey contextKey = "actor"
+
+// Anonymous actor used when the request is not authenticated
+const Anonymous = "anonymous"
+
+// WithActor stores who is performing the request
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// ActorFrom returns the actor, anonymous when missing
+func ActorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey).(string); ok && actor != "" {
+		return actor
+	}
+	return Anonymous
+}
@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"star-wars/api/handler"
+	"star-wars/audit"
+	"star-wars/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ErrNoCredentials the request carries no credentials for the authenticator
+var ErrNoCredentials = errors.New("no credentials")
+
+// ErrInvalidCredentials the credentials are malformed, expired or unknown
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// Authenticator resolves the principal from the request credentials
+type Authenticator interface {
+	Authenticate(r *http.Request) (Principal, error)
+}
+
+// Authenticate resolves the principal of r with the first authenticator that
+// finds credentials in it; requests without any are anonymous with the given role
+func Authenticate(r *http.Request, anonymous Role, authenticators ...Authenticator) (Principal, error) {
+	for _, a := range authenticators {
+		p, err := a.Authenticate(r)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		if err != nil {
+			return Principal{}, err
+		}
+
+		// a caller whose credentials grant no known role can still do what
+		// an anonymous one can
+		if ranks[p.Role] < ranks[anonymous] {
+			p.Role = anonymous
+		}
+
+		return p, nil
+	}
+
+	return Principal{Subject: audit.Anonymous, Role: anonymous}, nil
+}
+
+// Login stores the principal in ctx, as the actor of the audit as well
+func Login(ctx context.Context, p Principal) context.Context {
+	return audit.WithActor(With(ctx, p), p.Subject)
+}
+
+// Middleware authenticates the request, see Authenticate
+func Middleware(anonymous Role, authenticators ...Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, err := Authenticate(c.Request, anonymous, authenticators...)
+
+		if errors.Is(err, ErrInvalidCredentials) {
+			logger.FromContext(c.Request.Context()).Info("authentication failed", zap.Error(err))
+			unauthorized(c)
+			return
+		}
+
+		if err != nil {
+			handler.ResponseError(handler.InternalServer{Message: err.Error()}, c)
+			c.Abort()
+			return
+		}
+
+		c.Request = c.Request.WithContext(Login(c.Request.Context(), p))
+
+		c.Next()
+	}
+}
+
+// Require aborts with 401 for anonymous requests and 403 for principals without role
+func Require(role Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch err := Check(c.Request.Context(), role); err.(type) {
+		case nil:
+			c.Next()
+		case handler.Unauthorized:
+			unauthorized(c)
+		default:
+			handler.ResponseError(err, c)
+			c.Abort()
+		}
+	}
+}
+
+// Check tells why the principal in ctx cannot act as role, nil when it can
+func Check(ctx context.Context, role Role) error {
+	p, _ := From(ctx)
+
+	if p.Has(role) {
+		return nil
+	}
+
+	if !p.Authenticated() {
+		return handler.Unauthorized{Message: "authentication required"}
+	}
+
+	return handler.Forbidden{Message: "requires the " + string(role) + " role"}
+}
+
+func unauthorized(c *gin.Context) {
+	c.Header("WWW-Authenticate", `Bearer realm="star-wars"`)
+	handler.ResponseError(handler.Unauthorized{Message: "authentication required"}, c)
+	c.Abort()
+}
@@ -0,0 +1,184 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"star-wars/audit"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	keys, err := NewAPIKeys(map[string]string{
+		"ci":    "editor@ci-secret",
+		"ops":   "admin@ops-secret",
+		"stats": "reader@stats-secret",
+	}, store{"sw_roleless": {Subject: "roleless", Method: "jwt"}})
+	assert.NoError(t, err)
+
+	type test struct {
+		name           string
+		anonymous      Role
+		apiKey         string
+		require        Role
+		wantStatusCode int
+		wantActor      string
+		wantAuth       string
+	}
+
+	tests := []test{
+		{
+			name:           "when an anonymous request reads with the anonymous role",
+			anonymous:      RoleReader,
+			require:        RoleReader,
+			wantStatusCode: 200,
+			wantActor:      audit.Anonymous,
+		},
+		{
+			name:           "when an anonymous request writes",
+			anonymous:      RoleReader,
+			require:        RoleEditor,
+			wantStatusCode: 401,
+			wantAuth:       `Bearer realm="star-wars"`,
+		},
+		{
+			name:           "when anonymous requests are not allowed",
+			require:        RoleReader,
+			wantStatusCode: 401,
+			wantAuth:       `Bearer realm="star-wars"`,
+		},
+		{
+			name:           "when an editor key writes",
+			apiKey:         "ci-secret",
+			require:        RoleEditor,
+			wantStatusCode: 200,
+			wantActor:      "ci",
+		},
+		{
+			name:           "when an admin key writes",
+			apiKey:         "ops-secret",
+			require:        RoleEditor,
+			wantStatusCode: 200,
+			wantActor:      "ops",
+		},
+		{
+			name:           "when a reader key writes",
+			apiKey:         "stats-secret",
+			require:        RoleEditor,
+			wantStatusCode: 403,
+		},
+		{
+			name:           "when the credentials grant no known role",
+			anonymous:      RoleReader,
+			apiKey:         "sw_roleless",
+			require:        RoleReader,
+			wantStatusCode: 200,
+			wantActor:      "roleless",
+		},
+		{
+			name:           "when the credentials grant no known role and anonymous requests are not allowed",
+			apiKey:         "sw_roleless",
+			require:        RoleReader,
+			wantStatusCode: 403,
+		},
+		{
+			name:           "when the key is unknown",
+			anonymous:      RoleReader,
+			apiKey:         "guess",
+			require:        RoleReader,
+			wantStatusCode: 401,
+			wantAuth:       `Bearer realm="star-wars"`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var actor string
+
+			router := gin.New()
+			router.Use(Middleware(tt.anonymous, keys))
+			router.GET("/", Require(tt.require), func(c *gin.Context) {
+				actor = audit.ActorFrom(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.apiKey != "" {
+				req.Header.Set(APIKeyHeader, tt.apiKey)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.Equal(t, tt.wantActor, actor)
+			assert.Equal(t, tt.wantAuth, w.Header().Get("WWW-Authenticate"))
+		})
+	}
+}
+
+type store map[string]Principal
+
+func (s store) Lookup(_ context.Context, key string) (Principal, bool, error) {
+	if key == "broken" {
+		return Principal{}, false, errors.New("connection error")
+	}
+	p, ok := s[key]
+	return p, ok, nil
+}
+
+func TestAPIKeysStore(t *testing.T) {
+	keys, err := NewAPIKeys(
+		map[string]string{"ci": "editor@ci-secret"},
+		store{"sw_partner": {Subject: "partner", Role: RoleReader, Method: "api-key"}},
+	)
+	assert.NoError(t, err)
+
+	authenticate := func(key string) (Principal, error) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(APIKeyHeader, key)
+		return keys.Authenticate(req)
+	}
+
+	p, err := authenticate("ci-secret")
+	assert.NoError(t, err)
+	assert.Equal(t, "ci", p.Subject)
+
+	p, err = authenticate("sw_partner")
+	assert.NoError(t, err)
+	assert.Equal(t, "partner", p.Subject)
+
+	_, err = authenticate("sw_unknown")
+	assert.Equal(t, ErrInvalidCredentials, err)
+
+	_, err = authenticate("broken")
+	assert.EqualError(t, err, "connection error")
+
+	router := gin.New()
+	router.Use(Middleware(RoleReader, keys))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(APIKeyHeader, "broken")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestNewAPIKeys(t *testing.T) {
+	_, err := NewAPIKeys(map[string]string{"ci": "secret"})
+	assert.EqualError(t, err, `api key "ci" must be role@key`)
+
+	_, err = NewAPIKeys(map[string]string{"ci": "owner@secret"})
+	assert.EqualError(t, err, `api key "ci" has unknown role "owner"`)
+}
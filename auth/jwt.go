@@ -0,0 +1,237 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// leeway tolerated on exp and nbf for clock skew between us and the issuer
+const leeway = time.Minute
+
+// refreshInterval limits how often an unknown kid triggers a JWKS download
+const refreshInterval = time.Minute
+
+// asymmetric algorithms only: a JWKS holds public keys, never shared secrets
+var algorithms = map[string]bool{
+	string(jose.RS256): true, string(jose.RS384): true, string(jose.RS512): true,
+	string(jose.PS256): true, string(jose.PS384): true, string(jose.PS512): true,
+	string(jose.ES256): true, string(jose.ES384): true, string(jose.ES512): true,
+	string(jose.EdDSA): true,
+}
+
+// JWKS signing keys read from a file or downloaded from a URL; keys served
+// from a URL are downloaded again when a token names an unknown kid
+type JWKS struct {
+	location string
+	client   *http.Client
+	now      func() time.Time
+
+	mu      sync.RWMutex
+	set     jose.JSONWebKeySet
+	fetched time.Time
+}
+
+// LoadJWKS reads the key set at location, a file path or an http(s) URL
+func LoadJWKS(ctx context.Context, location string) (*JWKS, error) {
+	k := &JWKS{location: location, client: http.DefaultClient, now: time.Now}
+
+	if err := k.load(ctx); err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+func (k *JWKS) remote() bool {
+	return strings.HasPrefix(k.location, "http://") || strings.HasPrefix(k.location, "https://")
+}
+
+func (k *JWKS) load(ctx context.Context) error {
+	var raw []byte
+	var err error
+
+	if k.remote() {
+		raw, err = k.download(ctx)
+	} else {
+		raw, err = ioutil.ReadFile(k.location)
+	}
+
+	if err != nil {
+		return fmt.Errorf("jwks %s: %w", k.location, err)
+	}
+
+	var set jose.JSONWebKeySet
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return fmt.Errorf("jwks %s: %w", k.location, err)
+	}
+
+	k.mu.Lock()
+	k.set = set
+	k.fetched = k.now()
+	k.mu.Unlock()
+
+	return nil
+}
+
+func (k *JWKS) download(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, k.location, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := k.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("answered %d", resp.StatusCode)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+func (k *JWKS) lookup(kid string) (jose.JSONWebKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	keys := k.set.Key(kid)
+	if len(keys) == 0 {
+		return jose.JSONWebKey{}, false
+	}
+	return keys[0], true
+}
+
+// Key returns the verification key for kid
+func (k *JWKS) Key(ctx context.Context, kid string) (jose.JSONWebKey, error) {
+	if key, ok := k.lookup(kid); ok {
+		return key, nil
+	}
+
+	k.mu.RLock()
+	stale := k.now().Sub(k.fetched) >= refreshInterval
+	k.mu.RUnlock()
+
+	if k.remote() && stale {
+		if err := k.load(ctx); err != nil {
+			return jose.JSONWebKey{}, err
+		}
+		if key, ok := k.lookup(kid); ok {
+			return key, nil
+		}
+	}
+
+	return jose.JSONWebKey{}, fmt.Errorf("%w: unknown key %q", ErrInvalidCredentials, kid)
+}
+
+type jwtAuth struct {
+	keys     *JWKS
+	issuer   string
+	audience string
+	claim    []string
+	now      func() time.Time
+}
+
+// NewJWT authenticates bearer tokens signed by a key in keys; issuer and
+// audience are checked when set, and the role is the highest one found in the
+// rolesClaim, which may be a dotted path such as realm_access.roles
+func NewJWT(keys *JWKS, issuer, audience, rolesClaim string) Authenticator {
+	return &jwtAuth{
+		keys:     keys,
+		issuer:   issuer,
+		audience: audience,
+		claim:    strings.Split(rolesClaim, "."),
+		now:      time.Now,
+	}
+}
+
+func bearer(r *http.Request) (string, bool) {
+	h := r.Header.Get("Authorization")
+	if len(h) < 7 || !strings.EqualFold(h[:7], "Bearer ") {
+		return "", false
+	}
+	return strings.TrimSpace(h[7:]), true
+}
+
+func (a jwtAuth) Authenticate(r *http.Request) (Principal, error) {
+	raw, ok := bearer(r)
+	if !ok {
+		return Principal{}, ErrNoCredentials
+	}
+
+	tok, err := jwt.ParseSigned(raw)
+	if err != nil || len(tok.Headers) != 1 {
+		return Principal{}, fmt.Errorf("%w: malformed token", ErrInvalidCredentials)
+	}
+
+	header := tok.Headers[0]
+	if !algorithms[header.Algorithm] {
+		return Principal{}, fmt.Errorf("%w: algorithm %q not allowed", ErrInvalidCredentials, header.Algorithm)
+	}
+
+	key, err := a.keys.Key(r.Context(), header.KeyID)
+	if err != nil {
+		return Principal{}, err
+	}
+
+	var std jwt.Claims
+	custom := map[string]interface{}{}
+
+	if err := tok.Claims(key.Public().Key, &std, &custom); err != nil {
+		return Principal{}, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	if std.Expiry == nil || std.Subject == "" {
+		return Principal{}, fmt.Errorf("%w: exp and sub are required", ErrInvalidCredentials)
+	}
+
+	expected := jwt.Expected{Issuer: a.issuer, Time: a.now()}
+	if a.audience != "" {
+		expected.Audience = jwt.Audience{a.audience}
+	}
+
+	if err := std.ValidateWithLeeway(expected, leeway); err != nil {
+		return Principal{}, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	return Principal{Subject: std.Subject, Role: highest(roles(custom, a.claim)), Method: "jwt"}, nil
+}
+
+// roles found at path, either a list or a space separated string
+func roles(claims map[string]interface{}, path []string) []string {
+	var v interface{} = claims
+
+	for _, name := range path {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[name]
+	}
+
+	switch value := v.(type) {
+	case string:
+		return strings.Fields(value)
+	case []interface{}:
+		names := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				names = append(names, s)
+			}
+		}
+		return names
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+type issuer struct {
+	key *rsa.PrivateKey
+	kid string
+}
+
+func newIssuer(t *testing.T, kid string) issuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	return issuer{key: key, kid: kid}
+}
+
+func (i issuer) jwks(t *testing.T) []byte {
+	raw, err := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+		{Key: i.key.Public(), KeyID: i.kid, Algorithm: string(jose.RS256), Use: "sig"},
+	}})
+	assert.NoError(t, err)
+	return raw
+}
+
+func (i issuer) sign(t *testing.T, claims ...interface{}) string {
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: i.key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", i.kid),
+	)
+	assert.NoError(t, err)
+
+	builder := jwt.Signed(signer)
+	for _, c := range claims {
+		builder = builder.Claims(c)
+	}
+
+	raw, err := builder.CompactSerialize()
+	assert.NoError(t, err)
+	return raw
+}
+
+func TestJWT(t *testing.T) {
+	t.Parallel()
+
+	iss := newIssuer(t, "k1")
+	other := newIssuer(t, "k1")
+
+	dir, err := ioutil.TempDir("", "jwks")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "jwks.json")
+	assert.NoError(t, ioutil.WriteFile(path, iss.jwks(t), 0600))
+
+	keys, err := LoadJWKS(context.Background(), path)
+	assert.NoError(t, err)
+
+	a := NewJWT(keys, "https://issuer.test", "star-wars", "realm_access.roles")
+
+	now := time.Now()
+	valid := jwt.Claims{
+		Subject:  "luke",
+		Issuer:   "https://issuer.test",
+		Audience: jwt.Audience{"star-wars"},
+		Expiry:   jwt.NewNumericDate(now.Add(time.Hour)),
+	}
+	roles := map[string]interface{}{"realm_access": map[string]interface{}{"roles": []string{"offline", "editor"}}}
+
+	expired := valid
+	expired.Expiry = jwt.NewNumericDate(now.Add(-time.Hour))
+
+	wrongAudience := valid
+	wrongAudience.Audience = jwt.Audience{"other-api"}
+
+	noExpiry := valid
+	noExpiry.Expiry = nil
+
+	type test struct {
+		name          string
+		authorization string
+		want          Principal
+		wantErr       error
+	}
+
+	tests := []test{
+		{
+			name:          "when the token is valid",
+			authorization: "Bearer " + iss.sign(t, valid, roles),
+			want:          Principal{Subject: "luke", Role: RoleEditor, Method: "jwt"},
+		},
+		{
+			name:          "when the token has no known role",
+			authorization: "bearer " + iss.sign(t, valid),
+			want:          Principal{Subject: "luke", Method: "jwt"},
+		},
+		{
+			name:    "when there is no bearer token",
+			wantErr: ErrNoCredentials,
+		},
+		{
+			name:          "when the token is expired",
+			authorization: "Bearer " + iss.sign(t, expired, roles),
+			wantErr:       ErrInvalidCredentials,
+		},
+		{
+			name:          "when the token is for another audience",
+			authorization: "Bearer " + iss.sign(t, wrongAudience, roles),
+			wantErr:       ErrInvalidCredentials,
+		},
+		{
+			name:          "when the token never expires",
+			authorization: "Bearer " + iss.sign(t, noExpiry, roles),
+			wantErr:       ErrInvalidCredentials,
+		},
+		{
+			name:          "when the signature does not match",
+			authorization: "Bearer " + other.sign(t, valid, roles),
+			wantErr:       ErrInvalidCredentials,
+		},
+		{
+			name:          "when the token is garbage",
+			authorization: "Bearer not.a.token",
+			wantErr:       ErrInvalidCredentials,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			got, err := a.Authenticate(req)
+
+			assert.Equal(t, tt.want, got)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestJWKSRefresh(t *testing.T) {
+	first := newIssuer(t, "k1")
+	rotated := newIssuer(t, "k2")
+
+	served := first.jwks(t)
+	downloads := 0
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads++
+		_, _ = w.Write(served)
+	}))
+	defer ts.Close()
+
+	keys, err := LoadJWKS(context.Background(), ts.URL)
+	assert.NoError(t, err)
+
+	now := time.Now()
+	keys.now = func() time.Time { return now }
+
+	served = rotated.jwks(t)
+
+	_, err = keys.Key(context.Background(), "k2")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.Equal(t, 1, downloads)
+
+	now = now.Add(refreshInterval)
+
+	key, err := keys.Key(context.Background(), "k2")
+	assert.NoError(t, err)
+	assert.Equal(t, "k2", key.KeyID)
+	assert.Equal(t, 2, downloads)
+}
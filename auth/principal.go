@@ -0,0 +1,67 @@
+package auth
+
+import "context"
+
+// Role grants access to a group of routes; each role includes the ones below it
+type Role string
+
+// Roles from the least to the most privileged
+const (
+	RoleReader Role = "reader"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+)
+
+var ranks = map[Role]int{RoleReader: 1, RoleEditor: 2, RoleAdmin: 3}
+
+// ParseRole returns the role named s, false when unknown
+func ParseRole(s string) (Role, bool) {
+	r := Role(s)
+	_, ok := ranks[r]
+	return r, ok
+}
+
+// highest of the known roles in names, empty when none is known
+func highest(names []string) Role {
+	var role Role
+	for _, name := range names {
+		if r, ok := ParseRole(name); ok && ranks[r] > ranks[role] {
+			role = r
+		}
+	}
+	return role
+}
+
+// Principal who is performing the request
+type Principal struct {
+	// Subject identifies the principal in rate limits, idempotency keys and audit
+	Subject string
+	// Name to show for the principal, unlike Subject it may be shared
+	Name string
+	Role Role
+	// Method that authenticated the principal, empty for anonymous requests
+	Method string
+}
+
+// Authenticated tells whether the request carried valid credentials
+func (p Principal) Authenticated() bool {
+	return p.Method != ""
+}
+
+// Has tells whether the principal role includes r
+func (p Principal) Has(r Role) bool {
+	return ranks[p.Role] >= ranks[r]
+}
+
+type contextKey struct{}
+
+// With stores the principal
+func With(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// From returns the principal, false when the request went through no auth middleware
+func From(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(contextKey{}).(Principal)
+	return p, ok
+}
@@ -0,0 +1,126 @@
+package clientip
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Proxies trusted to forward the client address in X-Forwarded-For and X-Real-IP
+type Proxies []*net.IPNet
+
+// ParseProxies reads addresses and CIDR ranges
+func ParseProxies(list []string) (Proxies, error) {
+	var proxies Proxies
+
+	for _, s := range list {
+		if !strings.Contains(s, "/") {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid proxy %q", s)
+			}
+
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy %q: %w", s, err)
+		}
+		proxies = append(proxies, n)
+	}
+
+	return proxies, nil
+}
+
+func (p Proxies) trusts(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+
+	for _, n := range p {
+		if n.Contains(parsed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type contextKey struct{}
+
+// With stores the client address
+func With(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, contextKey{}, ip)
+}
+
+// From returns the client address, empty when missing
+func From(ctx context.Context) string {
+	ip, _ := ctx.Value(contextKey{}).(string)
+	return ip
+}
+
+// Of r: the address stored by Middleware, the peer when there is none
+func Of(r *http.Request) string {
+	if ip := From(r.Context()); ip != "" {
+		return ip
+	}
+	return peer(r)
+}
+
+func peer(r *http.Request) string {
+	host, _, err := net.SplitHostPort(strings.TrimSpace(r.RemoteAddr))
+	if err != nil {
+		return strings.TrimSpace(r.RemoteAddr)
+	}
+	return host
+}
+
+// resolve the client of r: the peer, unless it is a trusted proxy; then the
+// right-most X-Forwarded-For address not trusted, or else X-Real-IP
+func (p Proxies) resolve(r *http.Request) string {
+	ip := peer(r)
+	if !p.trusts(ip) {
+		return ip
+	}
+
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		hops := strings.Split(forwarded, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if net.ParseIP(hop) == nil {
+				break
+			}
+			ip = hop
+			if !p.trusts(hop) {
+				break
+			}
+		}
+		return ip
+	}
+
+	if real := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(real) != nil {
+		return real
+	}
+
+	return ip
+}
+
+// Middleware stores the client address of the request; forwarding headers are
+// believed only when they come from one of the trusted proxies
+func Middleware(trusted Proxies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(With(c.Request.Context(), trusted.resolve(c.Request)))
+		c.Next()
+	}
+}
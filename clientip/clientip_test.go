@@ -0,0 +1,99 @@
+package clientip
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProxies(t *testing.T) {
+	proxies, err := ParseProxies([]string{"10.0.0.1", "172.16.0.0/12", "::1"})
+
+	assert.NoError(t, err)
+	assert.True(t, proxies.trusts("10.0.0.1"))
+	assert.False(t, proxies.trusts("10.0.0.2"))
+	assert.True(t, proxies.trusts("172.20.1.1"))
+	assert.True(t, proxies.trusts("::1"))
+
+	_, err = ParseProxies([]string{"proxy.local"})
+	assert.EqualError(t, err, `invalid proxy "proxy.local"`)
+}
+
+func TestMiddleware(t *testing.T) {
+	trusted, _ := ParseProxies([]string{"10.0.0.0/8"})
+
+	type test struct {
+		name    string
+		peer    string
+		headers map[string]string
+		want    string
+	}
+
+	tests := []test{
+		{name: "when there is no proxy", peer: "192.0.2.1:4242", want: "192.0.2.1"},
+		{
+			name:    "when an untrusted peer forwards an address",
+			peer:    "192.0.2.1:4242",
+			headers: map[string]string{"X-Forwarded-For": "198.51.100.7", "X-Real-IP": "198.51.100.8"},
+			want:    "192.0.2.1",
+		},
+		{
+			name:    "when a trusted proxy forwards an address",
+			peer:    "10.0.0.1:4242",
+			headers: map[string]string{"X-Forwarded-For": "198.51.100.7"},
+			want:    "198.51.100.7",
+		},
+		{
+			name:    "when the client forges the left of the chain",
+			peer:    "10.0.0.1:4242",
+			headers: map[string]string{"X-Forwarded-For": "203.0.113.9, 198.51.100.7, 10.0.0.2"},
+			want:    "198.51.100.7",
+		},
+		{
+			name:    "when a trusted proxy sends X-Real-IP",
+			peer:    "10.0.0.1:4242",
+			headers: map[string]string{"X-Real-IP": "198.51.100.7"},
+			want:    "198.51.100.7",
+		},
+		{
+			name:    "when a trusted proxy forwards garbage",
+			peer:    "10.0.0.1:4242",
+			headers: map[string]string{"X-Forwarded-For": "unknown"},
+			want:    "10.0.0.1",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+
+			router := gin.New()
+			router.Use(Middleware(trusted))
+			router.GET("/", func(c *gin.Context) {
+				got = Of(c.Request)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.peer
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestOf(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.0.2.1:4242"
+	req.Header.Set("X-Forwarded-For", "198.51.100.7")
+
+	assert.Equal(t, "192.0.2.1", Of(req), "without the middleware only the peer is known")
+}
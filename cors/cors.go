@@ -0,0 +1,163 @@
+package cors
+
+import (
+	"net/http"
+	"star-wars/api/handler"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Policy for cross-origin requests; an origin of "*" allows any site, and a
+// leading "*." allows the subdomains of a site, such as https://*.example.com
+type Policy struct {
+	Origins     []string
+	Methods     []string
+	Headers     []string
+	Expose      []string
+	Credentials bool
+	MaxAge      time.Duration
+}
+
+func (p Policy) allowOrigin(origin string) bool {
+	for _, allowed := range p.Origins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+
+		// https://*.example.com matches https://api.example.com, not https://example.com
+		if i := strings.Index(allowed, "*."); i >= 0 {
+			prefix, suffix := allowed[:i], allowed[i+1:]
+			if strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) &&
+				len(origin) > len(prefix)+len(suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware applies the policy; routes lists the registered routes, read on the
+// first request, so preflights are only answered for paths and methods that exist
+func Middleware(p Policy, routes func() gin.RoutesInfo) gin.HandlerFunc {
+	var once sync.Once
+	var known gin.RoutesInfo
+
+	methods := strings.Join(p.Methods, ", ")
+	headers := strings.Join(p.Headers, ", ")
+	expose := strings.Join(p.Expose, ", ")
+	maxAge := strconv.Itoa(int(p.MaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		method := c.GetHeader("Access-Control-Request-Method")
+
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Origin")
+
+		if c.Request.Method != http.MethodOptions || method == "" {
+			if p.allowOrigin(origin) {
+				allow(c, p, origin)
+				if expose != "" {
+					c.Header("Access-Control-Expose-Headers", expose)
+				}
+			}
+			c.Next()
+			return
+		}
+
+		once.Do(func() { known = routes() })
+
+		if !routed(known, method, c.Request.URL.Path) {
+			handler.ResponseError(handler.NotFound{Message: "route not found"}, c)
+			c.Abort()
+			return
+		}
+
+		if !p.allowOrigin(origin) || !contains(p.Methods, method) {
+			handler.ResponseError(handler.Forbidden{Message: "cross-origin request not allowed"}, c)
+			c.Abort()
+			return
+		}
+
+		for _, h := range strings.Split(c.GetHeader("Access-Control-Request-Headers"), ",") {
+			if h = strings.TrimSpace(h); h != "" && !contains(p.Headers, h) {
+				handler.ResponseError(handler.Forbidden{Message: "header " + h + " not allowed"}, c)
+				c.Abort()
+				return
+			}
+		}
+
+		allow(c, p, origin)
+		c.Header("Access-Control-Allow-Methods", methods)
+		if headers != "" {
+			c.Header("Access-Control-Allow-Headers", headers)
+		}
+		if p.MaxAge > 0 {
+			c.Header("Access-Control-Max-Age", maxAge)
+		}
+
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// allow the origin; credentials rule out the "*" answer, so the origin is echoed
+func allow(c *gin.Context, p Policy, origin string) {
+	if contains(p.Origins, "*") && !p.Credentials {
+		c.Header("Access-Control-Allow-Origin", "*")
+	} else {
+		c.Header("Access-Control-Allow-Origin", origin)
+	}
+
+	if p.Credentials {
+		c.Header("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// routed tells whether a route answers method on path
+func routed(routes gin.RoutesInfo, method string, path string) bool {
+	for _, r := range routes {
+		if r.Method == method && match(r.Path, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func match(pattern string, path string) bool {
+	ps := strings.Split(strings.Trim(pattern, "/"), "/")
+	xs := strings.Split(strings.Trim(path, "/"), "/")
+
+	for i, p := range ps {
+		if strings.HasPrefix(p, "*") {
+			return true
+		}
+		if i >= len(xs) {
+			return false
+		}
+		if !strings.HasPrefix(p, ":") && p != xs[i] {
+			return false
+		}
+		if strings.HasPrefix(p, ":") && xs[i] == "" {
+			return false
+		}
+	}
+
+	return len(ps) == len(xs)
+}
@@ -0,0 +1,191 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func router(p Policy) *gin.Engine {
+	r := gin.New()
+	r.Use(Middleware(p, r.Routes))
+
+	r.GET("/planets", func(c *gin.Context) { c.JSON(http.StatusOK, []string{}) })
+	r.PUT("/planets/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/planets.csv", func(c *gin.Context) { c.Data(http.StatusOK, "text/csv", []byte("name\n")) })
+
+	return r
+}
+
+func TestMiddleware(t *testing.T) {
+	policy := Policy{
+		Origins: []string{"https://app.example.com", "https://*.partner.com"},
+		Methods: []string{"GET", "PUT"},
+		Headers: []string{"Content-Type", "If-Match"},
+		Expose:  []string{"ETag", "X-Request-ID"},
+		MaxAge:  10 * time.Minute,
+	}
+
+	type test struct {
+		name           string
+		policy         Policy
+		method         string
+		path           string
+		headers        map[string]string
+		wantStatusCode int
+		wantHeaders    map[string]string
+	}
+
+	tests := []test{
+		{
+			name:           "when a simple request comes from an allowed origin",
+			policy:         policy,
+			method:         "GET",
+			path:           "/planets",
+			headers:        map[string]string{"Origin": "https://app.example.com"},
+			wantStatusCode: 200,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":   "https://app.example.com",
+				"Access-Control-Expose-Headers": "ETag, X-Request-ID",
+				"Vary":                          "Origin",
+				"Content-Type":                  "application/json; charset=utf-8",
+			},
+		},
+		{
+			name:           "when the origin is a subdomain of an allowed site",
+			policy:         policy,
+			method:         "GET",
+			path:           "/planets",
+			headers:        map[string]string{"Origin": "https://shop.partner.com"},
+			wantStatusCode: 200,
+			wantHeaders:    map[string]string{"Access-Control-Allow-Origin": "https://shop.partner.com"},
+		},
+		{
+			name:           "when the origin is not allowed",
+			policy:         policy,
+			method:         "GET",
+			path:           "/planets",
+			headers:        map[string]string{"Origin": "https://evil.com"},
+			wantStatusCode: 200,
+			wantHeaders:    map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:           "when the response is not json",
+			policy:         policy,
+			method:         "GET",
+			path:           "/planets.csv",
+			headers:        map[string]string{"Origin": "https://app.example.com"},
+			wantStatusCode: 200,
+			wantHeaders:    map[string]string{"Content-Type": "text/csv"},
+		},
+		{
+			name:   "when the preflight is allowed",
+			policy: policy,
+			method: "OPTIONS",
+			path:   "/planets/5f2c891e9a9e070b1ef2e28c",
+			headers: map[string]string{
+				"Origin":                         "https://app.example.com",
+				"Access-Control-Request-Method":  "PUT",
+				"Access-Control-Request-Headers": "content-type, if-match",
+			},
+			wantStatusCode: 204,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://app.example.com",
+				"Access-Control-Allow-Methods":     "GET, PUT",
+				"Access-Control-Allow-Headers":     "Content-Type, If-Match",
+				"Access-Control-Max-Age":           "600",
+				"Access-Control-Allow-Credentials": "",
+			},
+		},
+		{
+			name:   "when the preflight targets a path without that route",
+			policy: policy,
+			method: "OPTIONS",
+			path:   "/planets",
+			headers: map[string]string{
+				"Origin":                        "https://app.example.com",
+				"Access-Control-Request-Method": "PUT",
+			},
+			wantStatusCode: 404,
+			wantHeaders:    map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:   "when the preflight comes from another origin",
+			policy: policy,
+			method: "OPTIONS",
+			path:   "/planets",
+			headers: map[string]string{
+				"Origin":                        "https://evil.com",
+				"Access-Control-Request-Method": "GET",
+			},
+			wantStatusCode: 403,
+			wantHeaders:    map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:   "when the preflight asks for a header out of the policy",
+			policy: policy,
+			method: "OPTIONS",
+			path:   "/planets",
+			headers: map[string]string{
+				"Origin":                         "https://app.example.com",
+				"Access-Control-Request-Method":  "GET",
+				"Access-Control-Request-Headers": "X-Debug",
+			},
+			wantStatusCode: 403,
+		},
+		{
+			name:   "when any origin is allowed with credentials",
+			policy: Policy{Origins: []string{"*"}, Methods: []string{"GET"}, Credentials: true},
+			method: "GET",
+			path:   "/planets",
+			headers: map[string]string{
+				"Origin": "https://app.example.com",
+			},
+			wantStatusCode: 200,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://app.example.com",
+				"Access-Control-Allow-Credentials": "true",
+			},
+		},
+		{
+			name:           "when any origin is allowed",
+			policy:         Policy{Origins: []string{"*"}, Methods: []string{"GET"}},
+			method:         "GET",
+			path:           "/planets",
+			headers:        map[string]string{"Origin": "https://app.example.com"},
+			wantStatusCode: 200,
+			wantHeaders:    map[string]string{"Access-Control-Allow-Origin": "*"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			router(tt.policy).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			for k, v := range tt.wantHeaders {
+				assert.Equal(t, v, w.Header().Get(k), k)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	assert.True(t, match("/planets/:id/history", "/planets/5f2c/history"))
+	assert.True(t, match("/static/*file", "/static/css/app.css"))
+	assert.False(t, match("/planets/:id", "/planets"))
+	assert.False(t, match("/planets/:id", "/planets/1/history"))
+	assert.False(t, match("/planets/:id", "/planets//"))
+}
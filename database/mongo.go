@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"star-wars/env"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+var (
+	mu     sync.Mutex
+	client *mongo.Client
+)
+
+// Client returns the shared mongo client, connecting on first use
+func Client(ctx context.Context) (*mongo.Client, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if client != nil {
+		return client, nil
+	}
+
+	c, err := mongo.NewClient(options.Client().ApplyURI(env.Vars.Database.Host).SetMonitor(newMonitor()))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+
+	client = c
+	return client, nil
+}
+
+// Collection from the configured database
+func Collection(ctx context.Context, name string) (*mongo.Collection, error) {
+	c, err := Client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Database(env.Vars.Database.Name).Collection(name), nil
+}
+
+// Ping checks the primary is reachable
+func Ping(ctx context.Context) error {
+	c, err := Client(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.Ping(ctx, readpref.Primary())
+}
+
+// Close disconnects the shared client, the next Client call connects again
+func Close(ctx context.Context) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if client == nil {
+		return nil
+	}
+
+	err := client.Disconnect(ctx)
+	client = nil
+	return err
+}
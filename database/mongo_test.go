@@ -0,0 +1,29 @@
+package database
+
+import (
+	"context"
+	"star-wars/env"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	env.Vars.Database.Host = "mongodb://localhost:27017"
+	ctx := context.Background()
+
+	first, err := Client(ctx)
+	assert.Nil(t, err)
+
+	second, err := Client(ctx)
+	assert.Nil(t, err)
+	assert.Same(t, first, second)
+
+	assert.Nil(t, Close(ctx))
+	assert.Nil(t, Close(ctx))
+
+	third, err := Client(ctx)
+	assert.Nil(t, err)
+	assert.NotSame(t, first, third)
+	assert.Nil(t, Close(ctx))
+}
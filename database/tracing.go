@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"star-wars/tracing"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// monitor opens a client span per Mongo command, children of the caller's span
+type monitor struct {
+	spans sync.Map
+}
+
+func newMonitor() *event.CommandMonitor {
+	m := &monitor{}
+
+	return &event.CommandMonitor{
+		Started:   m.started,
+		Succeeded: m.succeeded,
+		Failed:    m.failed,
+	}
+}
+
+func (m *monitor) started(ctx context.Context, evt *event.CommandStartedEvent) {
+	attrs := []attribute.KeyValue{
+		attribute.String("db.system", "mongodb"),
+		attribute.String("db.name", evt.DatabaseName),
+		attribute.String("db.operation", evt.CommandName),
+	}
+
+	if coll, ok := evt.Command.Lookup(evt.CommandName).StringValueOK(); ok {
+		attrs = append(attrs, attribute.String("db.mongodb.collection", coll))
+	}
+
+	_, span := tracing.Tracer().Start(ctx, "mongodb."+evt.CommandName,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+
+	m.spans.Store(evt.RequestID, span)
+}
+
+func (m *monitor) succeeded(ctx context.Context, evt *event.CommandSucceededEvent) {
+	if span, ok := m.spans.Load(evt.RequestID); ok {
+		m.spans.Delete(evt.RequestID)
+		tracing.End(span.(trace.Span), nil)
+	}
+}
+
+func (m *monitor) failed(ctx context.Context, evt *event.CommandFailedEvent) {
+	if span, ok := m.spans.Load(evt.RequestID); ok {
+		m.spans.Delete(evt.RequestID)
+		tracing.End(span.(trace.Span), errors.New(evt.Failure))
+	}
+}
@@ -0,0 +1,37 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestMonitor(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	command, _ := bson.Marshal(bson.D{{Key: "find", Value: "planets"}})
+	m := newMonitor()
+	ctx := context.Background()
+
+	m.Started(ctx, &event.CommandStartedEvent{Command: command, DatabaseName: "star-wars", CommandName: "find", RequestID: 1})
+	m.Started(ctx, &event.CommandStartedEvent{Command: command, DatabaseName: "star-wars", CommandName: "find", RequestID: 2})
+	m.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "find", RequestID: 1}})
+	m.Failed(ctx, &event.CommandFailedEvent{CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "find", RequestID: 2}, Failure: "timeout"})
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "mongodb.find", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("db.mongodb.collection", "planets"))
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "timeout", spans[1].Status().Description)
+}
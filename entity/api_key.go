@@ -0,0 +1,48 @@
+package entity
+
+import "time"
+
+// API key scopes
+const (
+	ScopePlanetsRead  = "planets:read"
+	ScopePlanetsWrite = "planets:write"
+)
+
+// ValidScope tells whether scope is known
+func ValidScope(scope string) bool {
+	return scope == ScopePlanetsRead || scope == ScopePlanetsWrite
+}
+
+// APIKey machine credential; only the hash of the secret is stored
+type APIKey struct {
+	ID         string     `json:"id" bson:"_id,omitempty"`
+	Name       string     `json:"name" bson:"name"`
+	Prefix     string     `json:"prefix" bson:"prefix"`
+	Hash       string     `json:"-" bson:"hash"`
+	Scopes     []string   `json:"scopes" bson:"scopes"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty" bson:"expiresAt,omitempty"`
+	CreatedAt  *time.Time `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+	RotatedAt  *time.Time `json:"rotatedAt,omitempty" bson:"rotatedAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty" bson:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty" bson:"revokedAt,omitempty"`
+	// Key plain secret, only returned when the key is created or rotated
+	Key string `json:"key,omitempty" bson:"-"`
+}
+
+// Active tells whether the key can still authenticate at t
+func (k APIKey) Active(t time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || t.Before(*k.ExpiresAt)
+}
+
+// HasScope tells whether the key was granted scope
+func (k APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
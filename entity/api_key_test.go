@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyActive(t *testing.T) {
+	now := time.Date(2020, 8, 7, 10, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	assert.True(t, APIKey{}.Active(now))
+	assert.True(t, APIKey{ExpiresAt: &future}.Active(now))
+	assert.False(t, APIKey{ExpiresAt: &past}.Active(now))
+	assert.False(t, APIKey{ExpiresAt: &now}.Active(now))
+	assert.False(t, APIKey{RevokedAt: &past}.Active(now))
+}
+
+func TestAPIKeyHasScope(t *testing.T) {
+	key := APIKey{Scopes: []string{ScopePlanetsRead}}
+
+	assert.True(t, key.HasScope(ScopePlanetsRead))
+	assert.False(t, key.HasScope(ScopePlanetsWrite))
+	assert.True(t, ValidScope(ScopePlanetsWrite))
+	assert.False(t, ValidScope("planets:delete"))
+}
@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// Audit actions
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+	ActionPurge   = "purge"
+)
+
+// AuditEntry entity
+type AuditEntry struct {
+	ID        string    `json:"id" bson:"_id,omitempty"`
+	PlanetID  string    `json:"planetId" bson:"planetId"`
+	Action    string    `json:"action" bson:"action"`
+	Actor     string    `json:"actor" bson:"actor"`
+	RequestID string    `json:"requestId,omitempty" bson:"requestId,omitempty"`
+	Timestamp time.Time `json:"timestamp" bson:"timestamp"`
+	Before    *Planet   `json:"before,omitempty" bson:"before,omitempty"`
+	After     *Planet   `json:"after,omitempty" bson:"after,omitempty"`
+}
+
+// AuditFilter entity
+type AuditFilter struct {
+	PlanetID string
+	Actor    string
+	Action   string
+	From     time.Time
+	To       time.Time
+	Limit    int64
+	Skip     int64
+}
@@ -0,0 +1,10 @@
+package entity
+
+// BatchResult outcome of an item of a batch, in the order of the request
+type BatchResult struct {
+	Index  int     `json:"index"`
+	Status int     `json:"status"`
+	ID     string  `json:"id,omitempty"`
+	Planet *Planet `json:"planet,omitempty"`
+	Error  string  `json:"error,omitempty"`
+}
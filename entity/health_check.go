@@ -2,10 +2,21 @@ package entity
 
 import "net/http"
 
+// Health statuses
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+	StatusError    = "error"
+)
+
+// CheckMongoDB name of the database check, mirrored in Dependencies
+const CheckMongoDB = "mongodb"
+
 // HealthCheck entity
 type HealthCheck struct {
 	Status       string       `json:"status"`
 	Dependencies Dependencies `json:"dependencies"`
+	Checks       []Check      `json:"checks,omitempty"`
 }
 
 // CheckDependencies externals
@@ -17,7 +28,43 @@ func (h *HealthCheck) CheckDependencies() int {
 	return http.StatusInternalServerError
 }
 
+// Readiness sets the status from the checks: a failing critical check fails
+// the service, any other failure only degrades it
+func (h *HealthCheck) Readiness() int {
+	h.Status = StatusOK
+
+	for _, check := range h.Checks {
+		if check.Name == CheckMongoDB {
+			h.Dependencies.MongoDB = check.Status
+		}
+	}
+
+	for _, check := range h.Checks {
+		if check.Status == StatusOK {
+			continue
+		}
+
+		if check.Critical {
+			h.Status = StatusError
+			return http.StatusServiceUnavailable
+		}
+
+		h.Status = StatusDegraded
+	}
+
+	return http.StatusOK
+}
+
 // Dependencies entity
 type Dependencies struct {
 	MongoDB string `json:"mongoDb"`
 }
+
+// Check result of a single dependency
+type Check struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Critical bool   `json:"critical"`
+	Latency  string `json:"latency"`
+	Error    string `json:"error,omitempty"`
+}
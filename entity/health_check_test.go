@@ -33,3 +33,54 @@ func TestCheckDependencies(t *testing.T) {
 		assert.Equal(t, 500, status)
 	})
 }
+
+func TestReadiness(t *testing.T) {
+	type test struct {
+		name        string
+		checks      []Check
+		wantCode    int
+		wantStatus  string
+		wantMongoDB string
+	}
+
+	tests := []test{
+		{
+			name:        "when every check passes",
+			checks:      []Check{{Name: "mongodb", Status: StatusOK, Critical: true}, {Name: "swapi", Status: StatusOK}},
+			wantCode:    200,
+			wantStatus:  StatusOK,
+			wantMongoDB: StatusOK,
+		},
+		{
+			name:        "when a non-critical check fails",
+			checks:      []Check{{Name: "mongodb", Status: StatusOK, Critical: true}, {Name: "swapi", Status: StatusError}},
+			wantCode:    200,
+			wantStatus:  StatusDegraded,
+			wantMongoDB: StatusOK,
+		},
+		{
+			name:        "when a critical check fails",
+			checks:      []Check{{Name: "swapi", Status: StatusError}, {Name: "mongodb", Status: StatusError, Critical: true}},
+			wantCode:    503,
+			wantStatus:  StatusError,
+			wantMongoDB: StatusError,
+		},
+		{
+			name:       "when there are no checks",
+			wantCode:   200,
+			wantStatus: StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			hc := HealthCheck{Checks: tt.checks}
+
+			assert.Equal(t, tt.wantCode, hc.Readiness())
+			assert.Equal(t, tt.wantStatus, hc.Status)
+			assert.Equal(t, tt.wantMongoDB, hc.Dependencies.MongoDB)
+		})
+	}
+}
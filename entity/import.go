@@ -0,0 +1,27 @@
+package entity
+
+// Import upload of planets run as a job, with its progress
+type Import struct {
+	Format   string        `json:"format" bson:"format"`
+	Total    int           `json:"total" bson:"total"`
+	Imported int           `json:"imported" bson:"imported"`
+	Failed   int           `json:"failed" bson:"failed"`
+	Errors   []ImportError `json:"errors" bson:"errors"`
+	// Rows valid rows not imported yet, what a worker taking the job over runs
+	Rows []ImportRow `json:"-" bson:"rows,omitempty"`
+}
+
+// ImportRow planet of an upload, numbered from 1 in the order sent
+type ImportRow struct {
+	Row    int    `bson:"row"`
+	Planet Planet `bson:"planet"`
+}
+
+// ImportError row of an upload that was not imported, with the status the
+// planet would have been answered with on its own
+type ImportError struct {
+	Row    int    `json:"row" bson:"row" csv:"row"`
+	Name   string `json:"name" bson:"name" csv:"name"`
+	Status int    `json:"status" bson:"status" csv:"status"`
+	Error  string `json:"error" bson:"error" csv:"error"`
+}
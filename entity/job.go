@@ -0,0 +1,49 @@
+package entity
+
+import "time"
+
+// Job statuses
+const (
+	JobPending   = "pending"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+// Job kinds
+const (
+	JobCreatePlanet  = "planet.create"
+	JobImportPlanets = "planets.import"
+)
+
+// Job work queued to run in the background; the actor and request ID of the
+// request that queued it are restored while it runs
+type Job struct {
+	ID        string `json:"id" bson:"_id,omitempty"`
+	Kind      string `json:"kind" bson:"kind"`
+	Status    string `json:"status" bson:"status"`
+	Actor     string `json:"-" bson:"actor"`
+	RequestID string `json:"requestId,omitempty" bson:"requestId,omitempty"`
+	Attempts  int    `json:"attempts" bson:"attempts"`
+	// Planet sent to planet.create, the planet saved once it succeeds
+	Planet     *Planet    `json:"planet,omitempty" bson:"planet,omitempty"`
+	Import     *Import    `json:"import,omitempty" bson:"import,omitempty"`
+	Error      *JobError  `json:"error,omitempty" bson:"error,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt" bson:"createdAt"`
+	StartedAt  *time.Time `json:"startedAt,omitempty" bson:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty" bson:"finishedAt,omitempty"`
+	// LockedUntil lease of the worker running the job, another one takes it
+	// over once it ends
+	LockedUntil *time.Time `json:"-" bson:"lockedUntil,omitempty"`
+}
+
+// JobError why a job failed, with the status the request would have answered
+type JobError struct {
+	Status  int    `json:"status" bson:"status"`
+	Message string `json:"message" bson:"message"`
+}
+
+// Done tells whether the job finished, either way
+func (j Job) Done() bool {
+	return j.Status == JobSucceeded || j.Status == JobFailed
+}
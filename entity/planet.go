@@ -4,15 +4,31 @@ import (
 	"errors"
 	"reflect"
 	"star-wars/swapi/adapter"
+	"time"
 )
 
 // Planet entity
 type Planet struct {
-	ID         string `json:"id" bson:"_id,omitempty"`
-	Name       string `json:"name" bson:"name,omitempty"`
-	Climate    string `json:"climate" bson:"climate,omitempty"`
-	Terrain    string `json:"terrain" bson:"terrain,omitempty"`
-	TotalFilms int    `json:"totalFilms" bson:"totalFilms,omitempty"`
+	ID         string     `json:"id" bson:"_id,omitempty"`
+	Name       string     `json:"name" bson:"name,omitempty" validate:"required,max=60,charset" csv:"name"`
+	Climate    Terms      `json:"climate" bson:"climate,omitempty" validate:"min=1,max=10,dive,vocabulary=climate" csv:"climate"`
+	Terrain    Terms      `json:"terrain" bson:"terrain,omitempty" validate:"min=1,max=10,dive,vocabulary=terrain" csv:"terrain"`
+	TotalFilms int        `json:"totalFilms" bson:"totalFilms,omitempty"`
+	CreatedAt  *time.Time `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+	UpdatedAt  *time.Time `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
+	DeletedAt  *time.Time `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"`
+	Version    int64      `json:"version,omitempty" bson:"version,omitempty"`
+}
+
+// PlanetRef a planet and the version expected of it, handler.AnyVersion for any
+type PlanetRef struct {
+	ID      string
+	Version int64
+}
+
+// Validate checks fields against the validate tag rules
+func (p Planet) Validate() error {
+	return validateStruct(p)
 }
 
 // IsEmpty validate fields
@@ -27,6 +43,19 @@ func (p Planet) IsEmpty(fields []string) bool {
 	return false
 }
 
+// LastModified last write time, zero when unknown
+func (p Planet) LastModified() time.Time {
+	if p.UpdatedAt != nil {
+		return *p.UpdatedAt
+	}
+
+	if p.CreatedAt != nil {
+		return *p.CreatedAt
+	}
+
+	return time.Time{}
+}
+
 // TotalAppearances counts film appearances
 func (p Planet) TotalAppearances(adapter []adapter.Planet) (int, error) {
 	if len(adapter) != 1 {
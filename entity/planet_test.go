@@ -1,8 +1,11 @@
 package entity
 
 import (
+	"encoding/json"
 	"errors"
+	"io/ioutil"
 	"star-wars/swapi/adapter"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,8 +16,8 @@ func TestIsEmpty(t *testing.T) {
 		valid := Planet{
 			ID:         "5f25e9782b148406adb55727",
 			Name:       "Tatooine",
-			Climate:    "arid",
-			Terrain:    "desert",
+			Climate:    Terms{"arid"},
+			Terrain:    Terms{"desert"},
 			TotalFilms: 1,
 		}.IsEmpty([]string{"ID", "Name", "Climate", "Terrain", "TotalFilms"})
 
@@ -26,8 +29,8 @@ func TestIsEmpty(t *testing.T) {
 			valid := Planet{
 				ID:         "",
 				Name:       "Tatooine",
-				Climate:    "arid",
-				Terrain:    "desert",
+				Climate:    Terms{"arid"},
+				Terrain:    Terms{"desert"},
 				TotalFilms: 5,
 			}.IsEmpty([]string{"ID"})
 
@@ -38,8 +41,8 @@ func TestIsEmpty(t *testing.T) {
 			valid := Planet{
 				ID:         "5f25e9782b148406adb55727",
 				Name:       "Tatooine",
-				Climate:    "arid",
-				Terrain:    "desert",
+				Climate:    Terms{"arid"},
+				Terrain:    Terms{"desert"},
 				TotalFilms: 0,
 			}.IsEmpty([]string{"TotalFilms"})
 
@@ -76,3 +79,72 @@ func TestTotalAppearances(t *testing.T) {
 		assert.Equal(t, errors.New("search did not return the planet"), err)
 	})
 }
+
+func TestValidate(t *testing.T) {
+	type test struct {
+		name    string
+		planet  Planet
+		wantErr string
+	}
+
+	tests := []test{
+		{
+			name:   "when all fields are valid",
+			planet: Planet{Name: "Yavin IV", Climate: Terms{"temperate", "tropical"}, Terrain: Terms{"jungle", "rainforests"}},
+		},
+		{
+			name:    "when a field is empty",
+			planet:  Planet{Name: "Tatooine", Climate: Terms{}, Terrain: Terms{"desert"}},
+			wantErr: "name, climate and terrain is required",
+		},
+		{
+			name:    "when name is too long",
+			planet:  Planet{Name: strings.Repeat("a", 61), Climate: Terms{"arid"}, Terrain: Terms{"desert"}},
+			wantErr: "name must have at most 60 characters",
+		},
+		{
+			name:    "when name has invalid characters",
+			planet:  Planet{Name: "<Tatooine>", Climate: Terms{"arid"}, Terrain: Terms{"desert"}},
+			wantErr: "name has invalid characters",
+		},
+		{
+			name:    "when climate has unknown value",
+			planet:  Planet{Name: "Tatooine", Climate: Terms{"arid", "sunny"}, Terrain: Terms{"desert"}},
+			wantErr: `climate has unknown value: "sunny"`,
+		},
+		{
+			name:    "when terrain has too many values",
+			planet:  Planet{Name: "Tatooine", Climate: Terms{"arid"}, Terrain: ParseTerms("desert,desert,desert,desert,desert,desert,desert,desert,desert,desert,desert")},
+			wantErr: "terrain must have at most 10 values",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.planet.Validate()
+
+			if tt.wantErr == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.Equal(t, ValidationError{Message: tt.wantErr}, err)
+			}
+		})
+	}
+}
+
+func TestValidateSwapiPlanets(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/swapi_planets.json")
+	assert.Nil(t, err)
+
+	var planets []adapter.Planet
+	assert.Nil(t, json.Unmarshal(data, &planets))
+	assert.Len(t, planets, 60)
+
+	for _, p := range planets {
+		planet := Planet{Name: p.Name, Climate: ParseTerms(p.Climate), Terrain: ParseTerms(p.Terrain)}
+
+		assert.Nil(t, planet.Validate(), p.Name)
+	}
+}
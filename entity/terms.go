@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Terms list of climate or terrain values
+type Terms []string
+
+// ParseTerms splits a comma-joined list, trimming and lowercasing each value
+func ParseTerms(list string) Terms {
+	return NewTerms(strings.Split(list, ",")...)
+}
+
+// NewTerms normalizes values, skipping the empty ones
+func NewTerms(values ...string) Terms {
+	terms := Terms{}
+
+	for _, v := range values {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if v != "" {
+			terms = append(terms, v)
+		}
+	}
+
+	return terms
+}
+
+// String joins values with a comma
+func (t Terms) String() string {
+	return strings.Join(t, ", ")
+}
+
+// UnmarshalJSON accepts an array or a comma-joined string
+func (t *Terms) UnmarshalJSON(data []byte) error {
+	var list string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*t = ParseTerms(list)
+		return nil
+	}
+
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	*t = NewTerms(values...)
+	return nil
+}
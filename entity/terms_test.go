@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTerms(t *testing.T) {
+	terms := ParseTerms(" Grasslands, mountains ,, ")
+
+	assert.Equal(t, Terms{"grasslands", "mountains"}, terms)
+	assert.Equal(t, "grasslands, mountains", terms.String())
+}
+
+func TestTermsUnmarshalJSON(t *testing.T) {
+	t.Run("when comma-joined string", func(t *testing.T) {
+		var terms Terms
+		err := json.Unmarshal([]byte(`"temperate, tropical"`), &terms)
+
+		assert.Nil(t, err)
+		assert.Equal(t, Terms{"temperate", "tropical"}, terms)
+	})
+
+	t.Run("when array", func(t *testing.T) {
+		var terms Terms
+		err := json.Unmarshal([]byte(`[" Temperate", "tropical "]`), &terms)
+
+		assert.Nil(t, err)
+		assert.Equal(t, Terms{"temperate", "tropical"}, terms)
+	})
+
+	t.Run("when invalid", func(t *testing.T) {
+		var terms Terms
+		err := json.Unmarshal([]byte(`{}`), &terms)
+
+		assert.NotNil(t, err)
+	})
+}
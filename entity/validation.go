@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	validate  = newValidator()
+	nameChars = regexp.MustCompile(`^[\p{L}\p{N}][\p{L}\p{N} '.\-]*$`)
+)
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		return strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+	})
+
+	_ = v.RegisterValidation("charset", func(fl validator.FieldLevel) bool {
+		return nameChars.MatchString(fl.Field().String())
+	})
+
+	_ = v.RegisterValidation("vocabulary", func(fl validator.FieldLevel) bool {
+		return Known(fl.Param(), fl.Field().String())
+	})
+
+	return v
+}
+
+// ValidationError invalid entity
+type ValidationError struct {
+	Message string
+}
+
+func (v ValidationError) Error() string {
+	return v.Message
+}
+
+func validateStruct(s interface{}) error {
+	err := validate.Struct(s)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return err
+	}
+
+	return ValidationError{Message: message(fieldErrors[0])}
+}
+
+func message(fe validator.FieldError) string {
+	field := strings.SplitN(fe.Field(), "[", 2)[0]
+
+	switch fe.Tag() {
+	case "required", "min":
+		return "name, climate and terrain is required"
+	case "max":
+		if fe.Kind() == reflect.Slice {
+			return fmt.Sprintf("%s must have at most %s values", field, fe.Param())
+		}
+		return fmt.Sprintf("%s must have at most %s characters", field, fe.Param())
+	case "charset":
+		return fmt.Sprintf("%s has invalid characters", field)
+	case "vocabulary":
+		return fmt.Sprintf("%s has unknown value: %q", field, fe.Value())
+	default:
+		return fmt.Sprintf("%s is invalid", field)
+	}
+}
@@ -0,0 +1,106 @@
+package entity
+
+// Climates known values: the ones of the SWAPI planets, spelled as SWAPI does,
+// plus the dictionary spelling of arctic and subarctic
+var Climates = []string{
+	"arid",
+	"arctic",
+	"artic",
+	"artificial temperate",
+	"frigid",
+	"frozen",
+	"hot",
+	"humid",
+	"moist",
+	"murky",
+	"polluted",
+	"rocky",
+	"subarctic",
+	"subartic",
+	"superheated",
+	"temperate",
+	"tropical",
+	"unknown",
+	"windy",
+}
+
+// Terrains known values: the ones of the SWAPI planets
+var Terrains = []string{
+	"acid pools",
+	"airless asteroid",
+	"ash",
+	"barren",
+	"bogs",
+	"canyons",
+	"caves",
+	"cities",
+	"cityscape",
+	"cliffs",
+	"desert",
+	"deserts",
+	"fields",
+	"forests",
+	"fungus forests",
+	"gas giant",
+	"glaciers",
+	"grass",
+	"grasslands",
+	"grassy hills",
+	"hills",
+	"ice canyons",
+	"ice caves",
+	"islands",
+	"jungle",
+	"jungles",
+	"lakes",
+	"lava rivers",
+	"mesas",
+	"mountain",
+	"mountain ranges",
+	"mountains",
+	"ocean",
+	"oceans",
+	"plains",
+	"plateaus",
+	"rainforests",
+	"reefs",
+	"rivers",
+	"rock",
+	"rock arches",
+	"rocky",
+	"rocky canyons",
+	"rocky deserts",
+	"rocky islands",
+	"savanna",
+	"savannahs",
+	"savannas",
+	"scrublands",
+	"seas",
+	"sinkholes",
+	"swamp",
+	"swamps",
+	"toxic cloudsea",
+	"tundra",
+	"unknown",
+	"urban",
+	"valleys",
+	"verdant",
+	"vines",
+	"volcanoes",
+}
+
+var vocabularies = map[string][]string{
+	"climate": Climates,
+	"terrain": Terrains,
+}
+
+// Known checks the value against the vocabulary
+func Known(vocabulary string, value string) bool {
+	for _, v := range vocabularies[vocabulary] {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
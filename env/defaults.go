@@ -0,0 +1,123 @@
+package env
+
+import "time"
+
+// Budgets used when the config leaves a timeout unset
+const (
+	DefaultReadTimeout  = 2 * time.Second
+	DefaultWriteTimeout = 20 * time.Second
+	DefaultPurgeTimeout = 20 * time.Second
+	DefaultJobTimeout   = 2 * time.Minute
+
+	// an import gets this much per planet of the largest upload allowed: eight
+	// planets saved at a time, each up to 1.6s with the SWAPI retries
+	DefaultImportRowTimeout = 200 * time.Millisecond
+
+	// the server write timeout must outlast the slowest handler budget
+	DefaultServerReadTimeout  = 10 * time.Second
+	DefaultServerWriteTimeout = 30 * time.Second
+
+	// claim holding the roles of a JWT principal
+	DefaultRolesClaim = "roles"
+
+	// requests per client on routes without their own limit
+	DefaultRateLimit      = "100/1m"
+	DefaultRateLimitStore = "memory"
+
+	// background workers and how often they look for jobs queued elsewhere
+	DefaultJobWorkers = 4
+	DefaultJobPoll    = 5 * time.Second
+
+	// items of a batch request
+	DefaultBatchSize = 100
+
+	// planets of an upload to POST /imports
+	DefaultImportMaxRows = 10000
+
+	// how long the response to an Idempotency-Key is replayed
+	DefaultIdempotencyTTL   = 24 * time.Hour
+	DefaultIdempotencyStore = "mongodb"
+)
+
+// v1 is deprecated since v2 shipped and answers for six months more
+var (
+	DefaultV1Deprecation = time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)
+	DefaultV1Sunset      = time.Date(2027, time.May, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// CORS lists used when the config leaves them empty; no origin is allowed by default
+var (
+	DefaultCorsMethods = []string{"GET", "POST", "PUT", "DELETE"}
+	DefaultCorsHeaders = []string{
+		"Authorization", "Content-Type", "If-Match", "If-Modified-Since", "If-None-Match", "Idempotency-Key", "X-API-Key", "X-Request-ID",
+	}
+	DefaultCorsExpose = []string{
+		"ETag", "Idempotent-Replayed", "Last-Modified", "Retry-After", "X-Request-ID",
+		"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy",
+	}
+)
+
+func applyDefaults(cfg *Config) {
+	orDefault(&cfg.Api.ReadTimeout, DefaultServerReadTimeout)
+	orDefault(&cfg.Api.WriteTimeout, DefaultServerWriteTimeout)
+	orDefault(&cfg.Timeouts.Read, DefaultReadTimeout)
+	orDefault(&cfg.Timeouts.Write, DefaultWriteTimeout)
+	orDefault(&cfg.Timeouts.Purge, DefaultPurgeTimeout)
+	orDefault(&cfg.Timeouts.Job, DefaultJobTimeout)
+	orDefault(&cfg.Jobs.Poll, DefaultJobPoll)
+	orDefault(&cfg.Idempotency.TTL, DefaultIdempotencyTTL)
+
+	if cfg.Auth.RolesClaim == "" {
+		cfg.Auth.RolesClaim = DefaultRolesClaim
+	}
+
+	if cfg.Imports.MaxRows <= 0 {
+		cfg.Imports.MaxRows = DefaultImportMaxRows
+	}
+
+	orDefault(&cfg.Timeouts.Import, time.Duration(cfg.Imports.MaxRows)*DefaultImportRowTimeout)
+
+	if cfg.Jobs.Workers <= 0 {
+		cfg.Jobs.Workers = DefaultJobWorkers
+	}
+
+	if cfg.Planets.BatchSize <= 0 {
+		cfg.Planets.BatchSize = DefaultBatchSize
+	}
+
+	if cfg.Idempotency.Store == "" {
+		cfg.Idempotency.Store = DefaultIdempotencyStore
+	}
+
+	if cfg.RateLimit.Default == "" {
+		cfg.RateLimit.Default = DefaultRateLimit
+	}
+
+	if cfg.RateLimit.Store == "" {
+		cfg.RateLimit.Store = DefaultRateLimitStore
+	}
+
+	if cfg.Versions.V1.Deprecation.IsZero() {
+		cfg.Versions.V1.Deprecation = DefaultV1Deprecation
+	}
+
+	if cfg.Versions.V1.Sunset.IsZero() {
+		cfg.Versions.V1.Sunset = DefaultV1Sunset
+	}
+
+	orDefaultList(&cfg.Cors.Methods, DefaultCorsMethods)
+	orDefaultList(&cfg.Cors.Headers, DefaultCorsHeaders)
+	orDefaultList(&cfg.Cors.Expose, DefaultCorsExpose)
+}
+
+func orDefault(d *time.Duration, fallback time.Duration) {
+	if *d <= 0 {
+		*d = fallback
+	}
+}
+
+func orDefaultList(list *[]string, fallback []string) {
+	if len(*list) == 0 {
+		*list = append([]string(nil), fallback...)
+	}
+}
@@ -0,0 +1,39 @@
+package env
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyDefaults(t *testing.T) {
+	var cfg Config
+	cfg.Timeouts.Read = 5 * time.Second
+	cfg.Cors.Methods = []string{"GET"}
+
+	applyDefaults(&cfg)
+
+	assert.Equal(t, 5*time.Second, cfg.Timeouts.Read)
+	assert.Equal(t, DefaultWriteTimeout, cfg.Timeouts.Write)
+	assert.Equal(t, DefaultPurgeTimeout, cfg.Timeouts.Purge)
+	assert.Equal(t, DefaultImportMaxRows*DefaultImportRowTimeout, cfg.Timeouts.Import)
+	assert.Equal(t, DefaultJobTimeout, cfg.Timeouts.Job)
+	assert.Equal(t, DefaultJobWorkers, cfg.Jobs.Workers)
+	assert.Equal(t, DefaultJobPoll, cfg.Jobs.Poll)
+	assert.Equal(t, DefaultServerReadTimeout, cfg.Api.ReadTimeout)
+	assert.Equal(t, DefaultServerWriteTimeout, cfg.Api.WriteTimeout)
+	assert.Equal(t, DefaultRolesClaim, cfg.Auth.RolesClaim)
+	assert.Equal(t, DefaultBatchSize, cfg.Planets.BatchSize)
+	assert.Equal(t, DefaultImportMaxRows, cfg.Imports.MaxRows)
+	assert.Equal(t, DefaultIdempotencyTTL, cfg.Idempotency.TTL)
+	assert.Equal(t, DefaultIdempotencyStore, cfg.Idempotency.Store)
+	assert.Equal(t, DefaultRateLimit, cfg.RateLimit.Default)
+	assert.Equal(t, DefaultRateLimitStore, cfg.RateLimit.Store)
+	assert.Equal(t, DefaultV1Deprecation, cfg.Versions.V1.Deprecation)
+	assert.Equal(t, DefaultV1Sunset, cfg.Versions.V1.Sunset)
+	assert.Equal(t, []string{"GET"}, cfg.Cors.Methods)
+	assert.Equal(t, DefaultCorsHeaders, cfg.Cors.Headers)
+	assert.Equal(t, DefaultCorsExpose, cfg.Cors.Expose)
+	assert.Empty(t, cfg.Cors.Origins)
+}
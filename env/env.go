@@ -1,11 +1,12 @@
 package env
 
 import (
-	"fmt"
-	"log"
 	"os"
+	"star-wars/logger"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
+	"go.uber.org/zap"
 	"gopkg.in/yaml.v2"
 )
 
@@ -14,32 +15,138 @@ var Vars Config
 func init() {
 	readFile(&Vars)
 	readEnv(&Vars)
-	fmt.Printf("%+v", Vars)
+	applyDefaults(&Vars)
+
+	if err := logger.SetLevel(Vars.Log.Level); err != nil {
+		processError(err)
+	}
+
+	logger.L().Debug("config loaded", zap.Any("config", Vars.Redacted()))
 }
 
 type Config struct {
 	Api struct {
-		Env  string `yaml:"env", envconfig:"API_ENV"`
-		Port string `yaml:"port", envconfig:"API_PORT"`
+		Env  string `yaml:"env" envconfig:"API_ENV"`
+		Port string `yaml:"port" envconfig:"API_PORT"`
+
+		ReadTimeout     time.Duration `yaml:"read-timeout" envconfig:"API_READ_TIMEOUT"`
+		WriteTimeout    time.Duration `yaml:"write-timeout" envconfig:"API_WRITE_TIMEOUT"`
+		ShutdownTimeout time.Duration `yaml:"shutdown-timeout" envconfig:"API_SHUTDOWN_TIMEOUT"`
+
+		// TrustedProxies addresses or CIDR ranges whose X-Forwarded-For is believed
+		TrustedProxies []string `yaml:"trusted-proxies" envconfig:"API_TRUSTED_PROXIES"`
 	} `yaml:"api"`
 
+	Auth struct {
+		Enabled       bool              `yaml:"enabled" envconfig:"AUTH_ENABLED"`
+		AnonymousRole string            `yaml:"anonymous-role" envconfig:"AUTH_ANONYMOUS_ROLE"`
+		Jwks          string            `yaml:"jwks" envconfig:"AUTH_JWKS"`
+		Issuer        string            `yaml:"issuer" envconfig:"AUTH_ISSUER"`
+		Audience      string            `yaml:"audience" envconfig:"AUTH_AUDIENCE"`
+		RolesClaim    string            `yaml:"roles-claim" envconfig:"AUTH_ROLES_CLAIM"`
+		ApiKeys       map[string]string `yaml:"api-keys" envconfig:"AUTH_API_KEYS" redact:"true"`
+	} `yaml:"auth"`
+
+	Cache struct {
+		Planet  string `yaml:"planet" envconfig:"CACHE_PLANET"`
+		Planets string `yaml:"planets" envconfig:"CACHE_PLANETS"`
+	} `yaml:"cache"`
+
+	Cors struct {
+		Origins     []string      `yaml:"origins" envconfig:"CORS_ORIGINS"`
+		Methods     []string      `yaml:"methods" envconfig:"CORS_METHODS"`
+		Headers     []string      `yaml:"headers" envconfig:"CORS_HEADERS"`
+		Expose      []string      `yaml:"expose" envconfig:"CORS_EXPOSE"`
+		Credentials bool          `yaml:"credentials" envconfig:"CORS_CREDENTIALS"`
+		MaxAge      time.Duration `yaml:"max-age" envconfig:"CORS_MAX_AGE"`
+	} `yaml:"cors"`
+
+	GRPC struct {
+		// Port of the gRPC server, empty leaves it off
+		Port string `yaml:"port" envconfig:"GRPC_PORT"`
+	} `yaml:"grpc"`
+
+	Health struct {
+		SwapiTTL time.Duration `yaml:"swapi-ttl" envconfig:"HEALTH_SWAPI_TTL"`
+	} `yaml:"health"`
+
+	Idempotency struct {
+		Store string        `yaml:"store" envconfig:"IDEMPOTENCY_STORE"`
+		TTL   time.Duration `yaml:"ttl" envconfig:"IDEMPOTENCY_TTL"`
+	} `yaml:"idempotency"`
+
+	Imports struct {
+		MaxRows int `yaml:"max-rows" envconfig:"IMPORTS_MAX_ROWS"`
+	} `yaml:"imports"`
+
 	Importer struct {
-		Env     string `yaml:"env", envconfig:"IMPORTER_ENV"`
-		PathCsv string `yaml:"path-csv", envconfig:"IMPORTER_PATH_CSV"`
+		Env     string `yaml:"env" envconfig:"IMPORTER_ENV"`
+		PathCsv string `yaml:"path-csv" envconfig:"IMPORTER_PATH_CSV"`
 	} `yaml:"importer"`
 
 	Database struct {
-		Name string `yaml:"name", envconfig:"DB_NAME"`
-		Host string `yaml:"host", envconfig:"DB_HOST"`
+		Name string `yaml:"name" envconfig:"DB_NAME"`
+		Host string `yaml:"host" envconfig:"DB_HOST" redact:"url"`
 	} `yaml:"database"`
 
+	Jobs struct {
+		Workers int           `yaml:"workers" envconfig:"JOBS_WORKERS"`
+		Poll    time.Duration `yaml:"poll" envconfig:"JOBS_POLL"`
+	} `yaml:"jobs"`
+
+	Log struct {
+		Level string `yaml:"level" envconfig:"LOG_LEVEL"`
+	} `yaml:"log"`
+
+	OpenAPI struct {
+		Validate bool `yaml:"validate" envconfig:"OPENAPI_VALIDATE"`
+	} `yaml:"openapi"`
+
+	Planets struct {
+		Retention time.Duration `yaml:"retention" envconfig:"PLANETS_RETENTION"`
+		BatchSize int           `yaml:"batch-size" envconfig:"PLANETS_BATCH_SIZE"`
+	} `yaml:"planets"`
+
+	RateLimit struct {
+		Enabled bool              `yaml:"enabled" envconfig:"RATELIMIT_ENABLED"`
+		Store   string            `yaml:"store" envconfig:"RATELIMIT_STORE"`
+		Redis   string            `yaml:"redis" envconfig:"RATELIMIT_REDIS" redact:"url"`
+		Default string            `yaml:"default" envconfig:"RATELIMIT_DEFAULT"`
+		Routes  map[string]string `yaml:"routes" envconfig:"RATELIMIT_ROUTES"`
+	} `yaml:"ratelimit"`
+
+	Tracing struct {
+		Exporter string `yaml:"exporter" envconfig:"TRACING_EXPORTER"`
+		Endpoint string `yaml:"endpoint" envconfig:"TRACING_ENDPOINT"`
+		Insecure bool   `yaml:"insecure" envconfig:"TRACING_INSECURE"`
+		File     string `yaml:"file" envconfig:"TRACING_FILE"`
+		Service  string `yaml:"service" envconfig:"TRACING_SERVICE"`
+	} `yaml:"tracing"`
+
+	Timeouts struct {
+		Read   time.Duration `yaml:"read" envconfig:"TIMEOUT_READ"`
+		Write  time.Duration `yaml:"write" envconfig:"TIMEOUT_WRITE"`
+		Purge  time.Duration `yaml:"purge" envconfig:"TIMEOUT_PURGE"`
+		Import time.Duration `yaml:"import" envconfig:"TIMEOUT_IMPORT"`
+		Job    time.Duration `yaml:"job" envconfig:"TIMEOUT_JOB"`
+	} `yaml:"timeouts"`
+
+	Versions struct {
+		V1 struct {
+			Deprecation time.Time `yaml:"deprecation" envconfig:"V1_DEPRECATION"`
+			Sunset      time.Time `yaml:"sunset" envconfig:"V1_SUNSET"`
+		} `yaml:"v1"`
+	} `yaml:"versions"`
+
 	Swapi struct {
-		Url string `yaml:"url", envconfig:"SWAPI_URL"`
+		Url     string        `yaml:"url" envconfig:"SWAPI_URL"`
+		Retries int           `yaml:"retries" envconfig:"SWAPI_RETRIES"`
+		Backoff time.Duration `yaml:"backoff" envconfig:"SWAPI_BACKOFF"`
 	} `yaml:"swapi"`
 }
 
 func processError(err error) {
-	log.Print(err)
+	logger.L().Warn("config", zap.Error(err))
 }
 
 func readFile(cfg *Config) {
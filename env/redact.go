@@ -0,0 +1,62 @@
+package env
+
+import (
+	"net/url"
+	"reflect"
+)
+
+// Mask replaces secrets
+const Mask = "REDACTED"
+
+// Redacted copy of the config safe to log: fields tagged redact:"true" are
+// replaced (map values included), redact:"url" keeps the URL but hides its password
+func (c Config) Redacted() Config {
+	redact(reflect.ValueOf(&c).Elem())
+	return c
+}
+
+func redact(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+
+		switch field.Kind() {
+		case reflect.Struct:
+			redact(field)
+		case reflect.Map:
+			if field.Len() == 0 || v.Type().Field(i).Tag.Get("redact") != "true" {
+				continue
+			}
+
+			// the copy shares maps with the original config, so build a new one
+			masked := reflect.MakeMapWithSize(field.Type(), field.Len())
+			for _, key := range field.MapKeys() {
+				masked.SetMapIndex(key, reflect.ValueOf(Mask))
+			}
+			field.Set(masked)
+		case reflect.String:
+			if field.String() == "" {
+				continue
+			}
+
+			switch v.Type().Field(i).Tag.Get("redact") {
+			case "true":
+				field.SetString(Mask)
+			case "url":
+				field.SetString(redactURL(field.String()))
+			}
+		}
+	}
+}
+
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return Mask
+	}
+
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), Mask)
+	}
+
+	return u.String()
+}
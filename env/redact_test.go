@@ -0,0 +1,28 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedacted(t *testing.T) {
+	var cfg Config
+	cfg.Database.Name = "star-wars"
+	cfg.Database.Host = "mongodb://luke:skywalker@db:27017/?authSource=admin"
+	cfg.Auth.ApiKeys = map[string]string{"ci": "editor@secret"}
+
+	redacted := cfg.Redacted()
+
+	assert.Equal(t, "mongodb://luke:REDACTED@db:27017/?authSource=admin", redacted.Database.Host)
+	assert.Equal(t, "star-wars", redacted.Database.Name)
+	assert.Equal(t, "mongodb://luke:skywalker@db:27017/?authSource=admin", cfg.Database.Host)
+	assert.Equal(t, map[string]string{"ci": Mask}, redacted.Auth.ApiKeys)
+	assert.Equal(t, "editor@secret", cfg.Auth.ApiKeys["ci"])
+}
+
+func TestRedactURL(t *testing.T) {
+	assert.Equal(t, "mongodb://db:27017", redactURL("mongodb://db:27017"))
+	assert.Equal(t, "mongodb://luke@db:27017", redactURL("mongodb://luke@db:27017"))
+	assert.Equal(t, Mask, redactURL("mongodb://luke:sky walker@db:%zz"))
+}
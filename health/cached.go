@@ -0,0 +1,41 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type cached struct {
+	checker Checker
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu      sync.Mutex
+	checked time.Time
+	err     error
+}
+
+// Cached reuses the last result of c for ttl, for dependencies too slow or
+// rate limited to probe on every request
+func Cached(c Checker, ttl time.Duration) Checker {
+	return &cached{
+		checker: c,
+		ttl:     ttl,
+		now:     time.Now,
+	}
+}
+
+func (c *cached) Check(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.checked.IsZero() && c.now().Sub(c.checked) < c.ttl {
+		return c.err
+	}
+
+	c.err = c.checker.Check(ctx)
+	c.checked = c.now()
+
+	return c.err
+}
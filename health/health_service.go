@@ -0,0 +1,83 @@
+package health
+
+import (
+	"context"
+	"star-wars/entity"
+	"sync"
+	"time"
+)
+
+// Checker probes a dependency
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts a function to Checker
+type CheckerFunc func(ctx context.Context) error
+
+// Check calls f
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Dependency checked by the readiness probe
+type Dependency struct {
+	Name     string
+	Critical bool
+	Checker  Checker
+}
+
+// Service contract
+type Service interface {
+	Ready(ctx context.Context) entity.HealthCheck
+}
+
+type srv struct {
+	deps []Dependency
+}
+
+// NewService returns a readiness service checking deps
+func NewService(deps ...Dependency) Service {
+	return &srv{
+		deps: deps,
+	}
+}
+
+// Ready runs every checker concurrently, results keep the registration order
+func (s srv) Ready(ctx context.Context) entity.HealthCheck {
+	checks := make([]entity.Check, len(s.deps))
+
+	var wg sync.WaitGroup
+
+	for i, dep := range s.deps {
+		wg.Add(1)
+
+		go func(i int, dep Dependency) {
+			defer wg.Done()
+			checks[i] = run(ctx, dep)
+		}(i, dep)
+	}
+
+	wg.Wait()
+
+	return entity.HealthCheck{Checks: checks}
+}
+
+func run(ctx context.Context, dep Dependency) entity.Check {
+	check := entity.Check{
+		Name:     dep.Name,
+		Status:   entity.StatusOK,
+		Critical: dep.Critical,
+	}
+
+	start := time.Now()
+	err := dep.Checker.Check(ctx)
+	check.Latency = time.Since(start).String()
+
+	if err != nil {
+		check.Status = entity.StatusError
+		check.Error = err.Error()
+	}
+
+	return check
+}
@@ -0,0 +1,58 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"star-wars/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReady(t *testing.T) {
+	ok := CheckerFunc(func(ctx context.Context) error { return nil })
+	fail := CheckerFunc(func(ctx context.Context) error { return errors.New("connection refused") })
+
+	s := NewService(
+		Dependency{Name: "mongodb", Critical: true, Checker: ok},
+		Dependency{Name: "swapi", Checker: fail},
+	)
+
+	hc := s.Ready(context.Background())
+
+	assert.Len(t, hc.Checks, 2)
+	assert.Equal(t, "mongodb", hc.Checks[0].Name)
+	assert.Equal(t, entity.StatusOK, hc.Checks[0].Status)
+	assert.True(t, hc.Checks[0].Critical)
+	assert.NotEmpty(t, hc.Checks[0].Latency)
+	assert.Empty(t, hc.Checks[0].Error)
+	assert.Equal(t, "swapi", hc.Checks[1].Name)
+	assert.Equal(t, entity.StatusError, hc.Checks[1].Status)
+	assert.Equal(t, "connection refused", hc.Checks[1].Error)
+}
+
+func TestCached(t *testing.T) {
+	calls := 0
+	checker := CheckerFunc(func(ctx context.Context) error {
+		calls++
+		if calls == 1 {
+			return errors.New("timeout")
+		}
+		return nil
+	})
+
+	now := time.Date(2020, 8, 7, 10, 0, 0, 0, time.UTC)
+	c := Cached(checker, time.Minute).(*cached)
+	c.now = func() time.Time { return now }
+
+	assert.EqualError(t, c.Check(context.Background()), "timeout")
+
+	now = now.Add(30 * time.Second)
+	assert.EqualError(t, c.Check(context.Background()), "timeout")
+	assert.Equal(t, 1, calls)
+
+	now = now.Add(30 * time.Second)
+	assert.Nil(t, c.Check(context.Background()))
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,175 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"star-wars/api/handler"
+	"star-wars/auth"
+	"star-wars/clientip"
+	"star-wars/logger"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	// Header carrying the key a client picks for a request and its retries
+	Header = "Idempotency-Key"
+	// Replayed header set on responses answered from the store
+	Replayed = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+
+	// storeTimeout budget to keep a response, detached from the request: the
+	// client that timed out is the one that will retry
+	storeTimeout = 5 * time.Second
+)
+
+// replayedHeaders of a response kept along with its status and body
+var replayedHeaders = []string{"Content-Type", "ETag", "Last-Modified", "Location"}
+
+// Middleware answers the retries of a request sent with an Idempotency-Key
+// with the response of the first one, for ttl. The key is held for lock while
+// the first request runs, retries meanwhile get 409; a key reused for another
+// request gets 422. Keys are scoped to the client, the authenticated
+// principal or the IP. Responses of 5xx are not kept, the request can be
+// retried, and a failing store lets requests through.
+func Middleware(store Store, ttl, lock time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(Header)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxKeyLength {
+			handler.ResponseError(handler.BadRequest{Message: "Idempotency-Key is too long"}, c)
+			c.Abort()
+			return
+		}
+
+		ctx := c.Request.Context()
+
+		body, err := ioutil.ReadAll(c.Request.Body)
+		if err != nil {
+			handler.ResponseError(handler.BadRequest{Message: err.Error()}, c)
+			c.Abort()
+			return
+		}
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		key = "idempotency:" + client(c) + ":" + key
+		fp := fingerprint(c.Request, body)
+
+		held, err := store.Claim(ctx, key, fp, time.Now().Add(lock))
+		if err != nil {
+			logger.FromContext(ctx).Warn("idempotency store failed", zap.Error(err))
+			c.Next()
+			return
+		}
+
+		if held != nil {
+			replay(c, held, fp)
+			c.Abort()
+			return
+		}
+
+		w := &recorder{ResponseWriter: c.Writer}
+		c.Writer = w
+
+		done := false
+		defer func() {
+			if !done {
+				release(ctx, store, key)
+			}
+		}()
+
+		c.Next()
+		done = true
+
+		if w.Status() >= http.StatusInternalServerError {
+			release(ctx, store, key)
+			return
+		}
+
+		header := map[string]string{}
+		for _, name := range replayedHeaders {
+			if v := w.Header().Get(name); v != "" {
+				header[name] = v
+			}
+		}
+
+		detached, cancel := context.WithTimeout(context.Background(), storeTimeout)
+		defer cancel()
+
+		r := Response{Status: w.Status(), Header: header, Body: w.body.Bytes()}
+		if err := store.Complete(detached, key, r, time.Now().Add(ttl)); err != nil {
+			logger.FromContext(ctx).Warn("idempotency store failed", zap.Error(err))
+		}
+	}
+}
+
+// replay answers with the response kept for the request holding the key
+func replay(c *gin.Context, held *Record, fp string) {
+	switch {
+	case held.Fingerprint != fp:
+		handler.ResponseError(handler.UnprocessableEntity{Message: "Idempotency-Key was used for another request"}, c)
+	case held.Response == nil:
+		handler.ResponseError(handler.Conflict{Message: "a request with this Idempotency-Key is in progress"}, c)
+	default:
+		for name, v := range held.Response.Header {
+			c.Header(name, v)
+		}
+		c.Header(Replayed, "true")
+		c.Status(held.Response.Status)
+		_, _ = c.Writer.Write(held.Response.Body)
+	}
+}
+
+// release frees the key of a request that failed, logging when it cannot
+func release(ctx context.Context, store Store, key string) {
+	detached, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+
+	if err := store.Release(detached, key); err != nil {
+		logger.FromContext(ctx).Warn("idempotency store failed", zap.Error(err))
+	}
+}
+
+// fingerprint tells requests apart: the same key must come with the same
+// method, target and body; the target keeps the version prefix, whose
+// responses differ in shape
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func client(c *gin.Context) string {
+	if p, ok := auth.From(c.Request.Context()); ok && p.Authenticated() {
+		return p.Method + ":" + p.Subject
+	}
+	return "ip:" + clientip.Of(c.Request)
+}
+
+// recorder keeps a copy of the body written
+type recorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *recorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
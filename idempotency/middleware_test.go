@@ -0,0 +1,145 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type failing struct{}
+
+func (failing) Claim(context.Context, string, string, time.Time) (*Record, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (failing) Complete(context.Context, string, Response, time.Time) error { return nil }
+
+func (failing) Release(context.Context, string) error { return nil }
+
+// router counts the planets created, answering 500 to the ones named "Crash"
+func router(store Store, created *int) *gin.Engine {
+	r := gin.New()
+	r.Use(Middleware(store, time.Hour, time.Minute))
+
+	r.POST("/planets", func(c *gin.Context) {
+		var body struct{ Name string }
+		_ = c.BindJSON(&body)
+
+		if body.Name == "Crash" {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+
+		*created++
+		c.Header("ETag", `"1"`)
+		c.JSON(http.StatusCreated, gin.H{"id": *created, "name": body.Name})
+	})
+
+	return r
+}
+
+func post(r *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/planets", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(Header, key)
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("replays the first response to the retries", func(t *testing.T) {
+		created := 0
+		r := router(NewMemoryStore(), &created)
+
+		first := post(r, "k1", `{"name":"Tatooine"}`)
+		assert.Equal(t, 201, first.Code)
+		assert.Empty(t, first.Header().Get(Replayed))
+
+		retry := post(r, "k1", `{"name":"Tatooine"}`)
+		assert.Equal(t, 201, retry.Code)
+		assert.Equal(t, first.Body.String(), retry.Body.String())
+		assert.Equal(t, `"1"`, retry.Header().Get("ETag"))
+		assert.Equal(t, "application/json; charset=utf-8", retry.Header().Get("Content-Type"))
+		assert.Equal(t, "true", retry.Header().Get(Replayed))
+		assert.Equal(t, 1, created)
+
+		assert.Equal(t, 201, post(r, "k2", `{"name":"Tatooine"}`).Code, "another key is another request")
+		assert.Equal(t, 2, created)
+	})
+
+	t.Run("when the key comes with another body", func(t *testing.T) {
+		created := 0
+		r := router(NewMemoryStore(), &created)
+
+		post(r, "k1", `{"name":"Tatooine"}`)
+		w := post(r, "k1", `{"name":"Hoth"}`)
+
+		assert.Equal(t, 422, w.Code)
+		assert.Equal(t, `{"error":"Idempotency-Key was used for another request"}`, w.Body.String())
+		assert.Equal(t, 1, created)
+	})
+
+	t.Run("when the first request is in progress", func(t *testing.T) {
+		created := 0
+		store := NewMemoryStore()
+		r := router(store, &created)
+
+		_, _ = store.Claim(context.Background(), "idempotency:ip:192.0.2.1:k1", fingerprintOf(`{"name":"Tatooine"}`), time.Now().Add(time.Minute))
+		w := post(r, "k1", `{"name":"Tatooine"}`)
+
+		assert.Equal(t, 409, w.Code)
+		assert.Equal(t, 0, created)
+	})
+
+	t.Run("server errors are not replayed", func(t *testing.T) {
+		created := 0
+		r := router(NewMemoryStore(), &created)
+
+		assert.Equal(t, 500, post(r, "k1", `{"name":"Crash"}`).Code)
+		w := post(r, "k1", `{"name":"Crash"}`)
+
+		assert.Equal(t, 500, w.Code)
+		assert.Empty(t, w.Header().Get(Replayed), "the key was released")
+	})
+
+	t.Run("without a key", func(t *testing.T) {
+		created := 0
+		r := router(NewMemoryStore(), &created)
+
+		post(r, "", `{"name":"Tatooine"}`)
+		post(r, "", `{"name":"Tatooine"}`)
+
+		assert.Equal(t, 2, created)
+	})
+
+	t.Run("when the key is too long", func(t *testing.T) {
+		created := 0
+		r := router(NewMemoryStore(), &created)
+
+		assert.Equal(t, 400, post(r, strings.Repeat("k", maxKeyLength+1), `{"name":"Tatooine"}`).Code)
+		assert.Equal(t, 0, created)
+	})
+
+	t.Run("when the store fails", func(t *testing.T) {
+		created := 0
+		r := router(failing{}, &created)
+
+		assert.Equal(t, 201, post(r, "k1", `{"name":"Tatooine"}`).Code)
+		assert.Equal(t, 201, post(r, "k1", `{"name":"Tatooine"}`).Code)
+		assert.Equal(t, 2, created)
+	})
+}
+
+func fingerprintOf(body string) string {
+	return fingerprint(httptest.NewRequest(http.MethodPost, "/planets", nil), []byte(body))
+}
@@ -0,0 +1,106 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"star-wars/database"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// duplicateKey code of the write that hit a key already stored
+const duplicateKey = 11000
+
+type mongoStore struct{}
+
+// NewMongoStore keeps records in MongoDB, shared by every instance; expired
+// records are dropped by the TTL index EnsureIndexes creates
+func NewMongoStore() Store {
+	return &mongoStore{}
+}
+
+// cnx is patched in tests, keep it out of line
+//
+//go:noinline
+func cnx(ctx context.Context) (*mongo.Collection, error) {
+	return database.Collection(ctx, "idempotency")
+}
+
+func (m mongoStore) Claim(ctx context.Context, key, fingerprint string, expires time.Time) (*Record, error) {
+	coll, err := cnx(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// a record that expired but was not dropped yet is taken over; a live
+	// one makes the upsert collide with its _id
+	_, err = coll.UpdateOne(ctx,
+		bson.M{"_id": key, "expiresAt": bson.M{"$lte": time.Now()}},
+		bson.M{
+			"$set":   bson.M{"fingerprint": fingerprint, "expiresAt": expires},
+			"$unset": bson.M{"response": ""},
+		},
+		options.Update().SetUpsert(true),
+	)
+
+	if err == nil {
+		return nil, nil
+	}
+
+	if !duplicate(err) {
+		return nil, err
+	}
+
+	held := &Record{}
+
+	if err := coll.FindOne(ctx, bson.M{"_id": key}).Decode(held); err != nil {
+		return nil, err
+	}
+
+	return held, nil
+}
+
+func (m mongoStore) Complete(ctx context.Context, key string, r Response, expires time.Time) error {
+	coll, err := cnx(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = coll.UpdateOne(ctx,
+		bson.M{"_id": key},
+		bson.M{"$set": bson.M{"response": r, "expiresAt": expires}},
+	)
+
+	return err
+}
+
+func (m mongoStore) Release(ctx context.Context, key string) error {
+	coll, err := cnx(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = coll.DeleteOne(ctx, bson.M{"_id": key})
+
+	return err
+}
+
+func duplicate(err error) bool {
+	var we mongo.WriteException
+	if errors.As(err, &we) {
+		for _, e := range we.WriteErrors {
+			if e.Code == duplicateKey {
+				return true
+			}
+		}
+	}
+
+	var ce mongo.CommandError
+	return errors.As(err, &ce) && ce.Code == duplicateKey
+}
@@ -0,0 +1,95 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Response kept to be replayed to the retries of a request
+type Response struct {
+	Status int               `bson:"status"`
+	Header map[string]string `bson:"header"`
+	Body   []byte            `bson:"body"`
+}
+
+// Record of the request that claimed a key
+type Record struct {
+	Fingerprint string    `bson:"fingerprint"`
+	Response    *Response `bson:"response,omitempty"`
+	ExpiresAt   time.Time `bson:"expiresAt"`
+}
+
+// Store keeps the requests sent with an idempotency key
+type Store interface {
+	// Claim takes key for the request with fingerprint until expires; when the
+	// key is held it takes nothing and returns the record holding it
+	Claim(ctx context.Context, key, fingerprint string, expires time.Time) (*Record, error)
+	// Complete keeps the response of the request holding key until expires
+	Complete(ctx context.Context, key string, r Response, expires time.Time) error
+	// Release frees key so the request can be sent again
+	Release(ctx context.Context, key string) error
+}
+
+type memory struct {
+	mu      sync.Mutex
+	records map[string]*Record
+	swept   time.Time
+}
+
+// NewMemoryStore keeps records in this process, for a single instance
+func NewMemoryStore() Store {
+	return &memory{records: map[string]*Record{}}
+}
+
+// sweepInterval how often expired records are dropped from memory
+const sweepInterval = time.Minute
+
+func (m *memory) Claim(_ context.Context, key, fingerprint string, expires time.Time) (*Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.sweep(now)
+
+	if r, ok := m.records[key]; ok && now.Before(r.ExpiresAt) {
+		held := *r
+		return &held, nil
+	}
+
+	m.records[key] = &Record{Fingerprint: fingerprint, ExpiresAt: expires}
+	return nil, nil
+}
+
+func (m *memory) Complete(_ context.Context, key string, r Response, expires time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if rec, ok := m.records[key]; ok {
+		rec.Response = &r
+		rec.ExpiresAt = expires
+	}
+	return nil
+}
+
+func (m *memory) Release(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.records, key)
+	return nil
+}
+
+func (m *memory) sweep(now time.Time) {
+	if now.Sub(m.swept) < sweepInterval {
+		return
+	}
+
+	for key, r := range m.records {
+		if !now.Before(r.ExpiresAt) {
+			delete(m.records, key)
+		}
+	}
+
+	m.swept = now
+}
@@ -0,0 +1,60 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	later := time.Now().Add(time.Minute)
+
+	held, err := s.Claim(ctx, "a", "fp", later)
+	assert.NoError(t, err)
+	assert.Nil(t, held, "the key was free")
+
+	held, _ = s.Claim(ctx, "a", "other", later)
+	assert.Equal(t, &Record{Fingerprint: "fp", ExpiresAt: later}, held, "in progress")
+
+	r := Response{Status: 201, Header: map[string]string{"ETag": `"1"`}, Body: []byte("{}")}
+	assert.NoError(t, s.Complete(ctx, "a", r, later.Add(time.Hour)))
+
+	held, _ = s.Claim(ctx, "a", "fp", later)
+	assert.Equal(t, &Record{Fingerprint: "fp", Response: &r, ExpiresAt: later.Add(time.Hour)}, held)
+
+	held, _ = s.Claim(ctx, "b", "fp", time.Now().Add(-time.Second))
+	assert.Nil(t, held)
+	held, _ = s.Claim(ctx, "b", "other", later)
+	assert.Nil(t, held, "an expired record is taken over")
+
+	assert.NoError(t, s.Release(ctx, "a"))
+	held, _ = s.Claim(ctx, "a", "other", later)
+	assert.Nil(t, held, "a released key is free")
+}
+
+func TestDuplicate(t *testing.T) {
+	type test struct {
+		name string
+		err  error
+		want bool
+	}
+
+	tests := []test{
+		{name: "write error", err: mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: duplicateKey}}}, want: true},
+		{name: "command error", err: mongo.CommandError{Code: duplicateKey}, want: true},
+		{name: "other write error", err: mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 2}}}, want: false},
+		{name: "other error", err: errors.New("connection refused"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, duplicate(tt.err))
+		})
+	}
+}
@@ -2,86 +2,92 @@ package main
 
 import (
 	"context"
-	"encoding/csv"
-	"fmt"
-	"io"
-	"log"
 	"os"
+	"star-wars/api/handler"
+	"star-wars/audit"
+	"star-wars/database"
 	"star-wars/entity"
 	"star-wars/env"
 	"star-wars/importer"
+	"star-wars/logger"
 	"star-wars/planet"
 	"star-wars/swapi"
+	"star-wars/tracing"
 	"time"
+
+	"go.uber.org/zap"
 )
 
 func openCsv() *os.File {
 	csvfile, err := os.Open(env.Vars.Importer.PathCsv)
 	if err != nil {
-		log.Fatalln("couldn't open the csv file", err)
+		logger.L().Fatal("couldn't open the csv file", zap.Error(err))
 	}
 
 	return csvfile
 }
 
 func readCsv(file *os.File) []entity.Planet {
-	r := csv.NewReader(file)
-	r.Comma = ';'
-
-	if _, err := r.Read(); err != nil {
-		panic(err)
+	rows, invalid, err := importer.Read(file, importer.FormatCSV)
+	if err != nil {
+		logger.L().Fatal("couldn't read the csv file", zap.Error(err))
 	}
 
-	var planets []entity.Planet
-
-	for {
-		rec, err := r.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		newPlanet := entity.Planet{
-			Name:    rec[0],
-			Climate: rec[1],
-			Terrain: rec[2],
-		}
-
-		empty := newPlanet.IsEmpty([]string{"Name", "Climate", "Terrain"})
-
-		if empty {
-			log.Printf("row error: %s; %s; %s", rec[0], rec[1], rec[2])
-			continue
-		}
+	for _, row := range invalid {
+		logger.L().Warn("row error", zap.Int("row", row.Row), zap.String("name", row.Name), zap.String("error", row.Error))
+	}
 
-		planets = append(planets, newPlanet)
+	if err := file.Close(); err != nil {
+		logger.L().Fatal("couldn't close the csv file", zap.Error(err))
 	}
 
-	err := file.Close()
-	if err != nil {
-		log.Fatal(err)
+	planets := make([]entity.Planet, len(rows))
+	for i, row := range rows {
+		planets[i] = row.Planet
 	}
 
 	return planets
 }
 
+// closeTimeout to disconnect from MongoDB once done
+const closeTimeout = 5 * time.Second
+
 func main() {
+	defer logger.Sync()
+
+	shutdownTracing, err := tracing.Setup(context.Background())
+	if err != nil {
+		logger.L().Fatal("error at tracing setup", zap.Error(err))
+	}
+	defer shutdownTracing(context.Background())
+
 	csvfile := openCsv()
 	planets := readCsv(csvfile)
 
-	s := swapi.New()
-	p := planet.NewService(planet.NewRepository(), s)
-	srv := importer.NewImporter(p, s)
+	s := swapi.NewRetry(swapi.NewInstrumented(swapi.New()), env.Vars.Swapi.Retries, env.Vars.Swapi.Backoff)
+	p := planet.NewTracedService(
+		planet.NewService(planet.NewInstrumentedRepository(planet.NewRepository()), s, audit.NewService(audit.NewRepository())),
+	)
+	srv := importer.NewInstrumented(importer.NewImporter(p, s))
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := context.WithTimeout(audit.WithActor(context.Background(), "importer"), env.Vars.Timeouts.Import)
 	defer cancel()
-	errors := srv.Import(ctx, planets)
+
+	// the work context may be over by now, the disconnect gets one of its own
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+		defer cancel()
+
+		if err := database.Close(ctx); err != nil {
+			logger.L().Warn("couldn't close mongodb", zap.Error(err))
+		}
+	}()
+
+	errors := srv.Import(ctx, planets, nil)
 
 	for _, err := range errors {
-		log.Print(err)
+		logger.L().Warn("planet not imported", zap.Error(handler.Cause(err)))
 	}
 
-	fmt.Println("> completed - errors:", len(errors))
+	logger.L().Info("import completed", zap.Int("planets", len(planets)), zap.Int("errors", len(errors)))
 }
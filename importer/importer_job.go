@@ -0,0 +1,105 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"star-wars/api/handler"
+	"star-wars/entity"
+	"star-wars/logger"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// saveEvery how often the progress of an import is stored while it runs
+const saveEvery = time.Second
+
+// Job runs the entity.JobImportPlanets jobs with s, counting the outcome of
+// each row as it is known and keeping the error report sorted by row; the job
+// fails when no planet was imported or ctx ended before every row was tried
+func Job(s Service) func(ctx context.Context, j *entity.Job, save func(context.Context) error) error {
+	return func(ctx context.Context, j *entity.Job, save func(context.Context) error) error {
+		imp := j.Import
+		if imp == nil {
+			return handler.BadRequest{Message: "job has no import"}
+		}
+
+		rows := imp.Rows
+		planets := make([]entity.Planet, len(rows))
+		for i := range rows {
+			planets[i] = rows[i].Planet
+		}
+
+		var mutex sync.Mutex
+		done := make([]bool, len(rows))
+		saved := time.Now()
+
+		s.Import(ctx, planets, func(i int, err error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			// a row cut short by the timeout was not imported nor refused
+			if err != nil && ctx.Err() != nil {
+				return
+			}
+
+			done[i] = true
+
+			if err == nil {
+				imp.Imported++
+			} else {
+				imp.Failed++
+				imp.Errors = append(imp.Errors, rowError(ctx, rows[i], err))
+			}
+
+			if time.Since(saved) < saveEvery {
+				return
+			}
+
+			saved = time.Now()
+			imp.Rows = pending(rows, done)
+
+			if err := save(ctx); err != nil {
+				logger.FromContext(ctx).Warn("import progress not stored", zap.String("job", j.ID), zap.Error(err))
+			}
+		})
+
+		left := len(pending(rows, done))
+		imp.Rows = nil
+		sort.SliceStable(imp.Errors, func(a, b int) bool { return imp.Errors[a].Row < imp.Errors[b].Row })
+
+		if left > 0 {
+			return handler.InternalServer{Message: fmt.Sprintf("import ran out of time with %d of %d planets left", left, imp.Total)}
+		}
+
+		if imp.Imported == 0 {
+			return handler.UnprocessableEntity{Message: fmt.Sprintf("none of the %d planets was imported", imp.Total)}
+		}
+
+		return nil
+	}
+}
+
+func rowError(ctx context.Context, row entity.ImportRow, err error) entity.ImportError {
+	e := entity.ImportError{Row: row.Row, Name: row.Planet.Name, Status: handler.Status(err), Error: err.Error()}
+
+	if e.Status == http.StatusInternalServerError {
+		logger.FromContext(ctx).Error("planet not imported", zap.Int("row", row.Row), zap.Error(handler.Cause(err)))
+	}
+
+	return e
+}
+
+// pending rows, the ones a worker taking the job over still has to import
+func pending(rows []entity.ImportRow, done []bool) []entity.ImportRow {
+	var left []entity.ImportRow
+	for i, row := range rows {
+		if !done[i] {
+			left = append(left, row)
+		}
+	}
+	return left
+}
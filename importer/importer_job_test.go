@@ -0,0 +1,110 @@
+package importer
+
+import (
+	"context"
+	"star-wars/api/handler"
+	"star-wars/entity"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJob(t *testing.T) {
+	t.Run("counts the outcome of each row", func(t *testing.T) {
+		c, ps, s := configDep(t)
+		defer c.Finish()
+
+		hoth := pe
+		hoth.Name = "Hoth"
+
+		ps.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, p *entity.Planet) error {
+			if p.Name == "Hoth" {
+				return handler.BadRequest{Message: "non-existent planet"}
+			}
+			return nil
+		}).Times(2)
+
+		j := &entity.Job{Kind: entity.JobImportPlanets, Import: &entity.Import{
+			Format: FormatCSV,
+			Total:  3,
+			Failed: 1,
+			Errors: []entity.ImportError{{Row: 3, Name: "Naboo", Status: 400, Error: "name, climate and terrain is required"}},
+			Rows:   []entity.ImportRow{{Row: 1, Planet: pe}, {Row: 4, Planet: hoth}},
+		}}
+
+		err := Job(NewImporter(ps, s))(context.Background(), j, nil)
+
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.Import{
+			Format:   FormatCSV,
+			Total:    3,
+			Imported: 1,
+			Failed:   2,
+			Errors: []entity.ImportError{
+				{Row: 3, Name: "Naboo", Status: 400, Error: "name, climate and terrain is required"},
+				{Row: 4, Name: "Hoth", Status: 400, Error: "non-existent planet"},
+			},
+		}, j.Import)
+	})
+
+	t.Run("when no planet is imported", func(t *testing.T) {
+		c, ps, s := configDep(t)
+		defer c.Finish()
+
+		ps.EXPECT().Save(gomock.Any(), gomock.Any()).Return(handler.Conflict{Message: "planet already registered"})
+
+		j := &entity.Job{Kind: entity.JobImportPlanets, Import: &entity.Import{
+			Total:  2,
+			Failed: 1,
+			Errors: []entity.ImportError{{Row: 2, Name: "Naboo", Status: 400, Error: "name, climate and terrain is required"}},
+			Rows:   []entity.ImportRow{{Row: 1, Planet: pe}},
+		}}
+
+		err := Job(NewImporter(ps, s))(context.Background(), j, nil)
+
+		assert.Equal(t, handler.UnprocessableEntity{Message: "none of the 2 planets was imported"}, err)
+		assert.Equal(t, 2, j.Import.Failed)
+		assert.Nil(t, j.Import.Rows)
+	})
+
+	t.Run("when the time runs out", func(t *testing.T) {
+		c, ps, s := configDep(t)
+		defer c.Finish()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		ps.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, p *entity.Planet) error {
+			cancel()
+			return ctx.Err()
+		})
+
+		j := &entity.Job{Kind: entity.JobImportPlanets, Import: &entity.Import{
+			Total: 1,
+			Rows:  []entity.ImportRow{{Row: 1, Planet: pe}},
+		}}
+
+		err := Job(NewImporter(ps, s))(ctx, j, nil)
+
+		assert.Equal(t, handler.InternalServer{Message: "import ran out of time with 1 of 1 planets left"}, err)
+		assert.Equal(t, 0, j.Import.Failed)
+		assert.Empty(t, j.Import.Errors)
+	})
+
+	t.Run("when the job has no import", func(t *testing.T) {
+		c, ps, s := configDep(t)
+		defer c.Finish()
+
+		err := Job(NewImporter(ps, s))(context.Background(), &entity.Job{Kind: entity.JobImportPlanets}, nil)
+
+		assert.Equal(t, handler.BadRequest{Message: "job has no import"}, err)
+	})
+}
+
+func TestPending(t *testing.T) {
+	rows := []entity.ImportRow{{Row: 1}, {Row: 2}, {Row: 3}}
+
+	assert.Equal(t, []entity.ImportRow{{Row: 2}}, pending(rows, []bool{true, false, true}))
+	assert.Nil(t, pending(rows, []bool{true, true, true}))
+}
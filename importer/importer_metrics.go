@@ -0,0 +1,41 @@
+package importer
+
+import (
+	"context"
+	"star-wars/entity"
+	"star-wars/metrics"
+	"sync/atomic"
+	"time"
+)
+
+type instrumented struct {
+	next Service
+}
+
+// NewInstrumented records how many planets each import run processed
+func NewInstrumented(s Service) Service {
+	return &instrumented{
+		next: s,
+	}
+}
+
+func (i instrumented) Import(ctx context.Context, planets []entity.Planet, progress func(i int, err error)) []error {
+	start := time.Now()
+	var imported, failed int64
+
+	// planets left untried when ctx ends are neither
+	errs := i.next.Import(ctx, planets, func(n int, err error) {
+		if err == nil {
+			atomic.AddInt64(&imported, 1)
+		} else {
+			atomic.AddInt64(&failed, 1)
+		}
+
+		if progress != nil {
+			progress(n, err)
+		}
+	})
+
+	metrics.Import(int(imported), int(failed), start)
+	return errs
+}
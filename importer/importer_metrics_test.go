@@ -0,0 +1,43 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"star-wars/entity"
+	"star-wars/importer/mock_importer"
+	"star-wars/metrics"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstrumented(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	s := mock_importer.NewMockService(c)
+
+	planets := []entity.Planet{pe, pe, pe}
+	failure := errors.New("planet already registered")
+	ctx := context.Background()
+	s.EXPECT().Import(ctx, planets, gomock.Any()).DoAndReturn(func(_ context.Context, planets []entity.Planet, progress func(int, error)) []error {
+		progress(0, nil)
+		progress(1, failure)
+		progress(2, nil)
+		return []error{failure}
+	})
+
+	errs := NewInstrumented(s).Import(ctx, planets, nil)
+
+	assert.Len(t, errs, 1)
+
+	expected := `
+# HELP importer_planets_total Planets processed by the importer by result.
+# TYPE importer_planets_total counter
+importer_planets_total{result="failed"} 1
+importer_planets_total{result="imported"} 2
+`
+	assert.Nil(t, testutil.GatherAndCompare(metrics.Registry, strings.NewReader(expected), "importer_planets_total"))
+}
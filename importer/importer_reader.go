@@ -0,0 +1,132 @@
+package importer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"star-wars/entity"
+	"strings"
+)
+
+// Formats of the uploads
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// header of the CSV layout, the one GET /planets answers in text/csv
+var header = []string{"name", "climate", "terrain"}
+
+// Read the planets of an upload in format, numbered from 1 in the order sent.
+// Rows that are not a valid planet, or repeat the name of an earlier one, are
+// returned apart with their error; an upload that cannot be read fails whole.
+func Read(r io.Reader, format string) ([]entity.ImportRow, []entity.ImportError, error) {
+	var planets []entity.Planet
+	var err error
+
+	switch format {
+	case FormatCSV:
+		planets, err = readCSV(r)
+	case FormatJSON:
+		planets, err = readJSON(r)
+	default:
+		err = fmt.Errorf("format %s is unknown", format)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rows []entity.ImportRow
+	var invalid []entity.ImportError
+	seen := map[string]bool{}
+
+	for i, p := range planets {
+		err := p.Validate()
+		if err == nil && seen[p.Name] {
+			err = errors.New("planet is repeated in the upload")
+		}
+
+		if err != nil {
+			invalid = append(invalid, entity.ImportError{Row: i + 1, Name: p.Name, Status: http.StatusBadRequest, Error: err.Error()})
+			continue
+		}
+
+		seen[p.Name] = true
+		rows = append(rows, entity.ImportRow{Row: i + 1, Planet: p})
+	}
+
+	return rows, invalid, nil
+}
+
+func readCSV(r io.Reader) ([]entity.Planet, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = ';'
+	cr.FieldsPerRecord = -1
+
+	head, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("file is not valid CSV: %v", err)
+	}
+
+	if !sameHeader(head) {
+		return nil, fmt.Errorf("header must be %s", strings.Join(header, ";"))
+	}
+
+	var planets []entity.Planet
+
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("file is not valid CSV: %v", err)
+		}
+
+		// a row missing fields fails validation on its own
+		for len(rec) < len(header) {
+			rec = append(rec, "")
+		}
+
+		planets = append(planets, entity.Planet{
+			Name:    rec[0],
+			Climate: entity.ParseTerms(rec[1]),
+			Terrain: entity.ParseTerms(rec[2]),
+		})
+	}
+
+	return planets, nil
+}
+
+// sameHeader ignores case, spaces and a byte order mark
+func sameHeader(head []string) bool {
+	if len(head) != len(header) {
+		return false
+	}
+
+	for i, name := range head {
+		name = strings.TrimPrefix(name, "\ufeff")
+		if !strings.EqualFold(strings.TrimSpace(name), header[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func readJSON(r io.Reader) ([]entity.Planet, error) {
+	var planets []entity.Planet
+
+	if err := json.NewDecoder(r).Decode(&planets); err != nil {
+		return nil, errors.New("file is not a JSON array of planets")
+	}
+
+	return planets, nil
+}
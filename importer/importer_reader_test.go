@@ -0,0 +1,79 @@
+package importer
+
+import (
+	"star-wars/entity"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRead(t *testing.T) {
+	type test struct {
+		name        string
+		format      string
+		body        string
+		wantRows    []entity.ImportRow
+		wantInvalid []entity.ImportError
+		wantErr     string
+	}
+
+	tatooine := entity.Planet{Name: "Tatooine", Climate: entity.Terms{"arid"}, Terrain: entity.Terms{"desert"}}
+
+	tests := []test{
+		{
+			name:     "csv",
+			format:   FormatCSV,
+			body:     "\ufeffName; Climate ;TERRAIN\nTatooine;arid;desert\n",
+			wantRows: []entity.ImportRow{{Row: 1, Planet: tatooine}},
+		},
+		{
+			name:     "csv with invalid and repeated rows",
+			format:   FormatCSV,
+			body:     "name;climate;terrain\nHoth;frozen\nTatooine;arid;desert\nTatooine;arid;desert\n",
+			wantRows: []entity.ImportRow{{Row: 2, Planet: tatooine}},
+			wantInvalid: []entity.ImportError{
+				{Row: 1, Name: "Hoth", Status: 400, Error: "name, climate and terrain is required"},
+				{Row: 3, Name: "Tatooine", Status: 400, Error: "planet is repeated in the upload"},
+			},
+		},
+		{
+			name:    "csv with another header",
+			format:  FormatCSV,
+			body:    "name,climate,terrain\n",
+			wantErr: "header must be name;climate;terrain",
+		},
+		{
+			name:    "empty csv",
+			format:  FormatCSV,
+			wantErr: "file is empty",
+		},
+		{
+			name:     "json",
+			format:   FormatJSON,
+			body:     `[{"name":"Tatooine","climate":["arid"],"terrain":["desert"]}]`,
+			wantRows: []entity.ImportRow{{Row: 1, Planet: tatooine}},
+		},
+		{
+			name:    "json that is not an array",
+			format:  FormatJSON,
+			body:    `{"name":"Tatooine"}`,
+			wantErr: "file is not a JSON array of planets",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, invalid, err := Read(strings.NewReader(tt.body), tt.format)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.wantRows, rows)
+			assert.Equal(t, tt.wantInvalid, invalid)
+		})
+	}
+}
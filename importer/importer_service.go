@@ -8,12 +8,16 @@ import (
 	"sync"
 )
 
-var wg sync.WaitGroup
-var mutex = &sync.Mutex{}
+// concurrency planets saved at a time, which bounds the SWAPI lookups in flight
+const concurrency = 8
 
 // Service contract
 type Service interface {
-	Import(ctx context.Context, planets []entity.Planet) []error
+	// Import saves planets, returning the errors of the ones that failed;
+	// progress, when not nil, is told the outcome of planets[i] as soon as it
+	// is known, from the goroutine that saved it. Once ctx is done the planets
+	// left are not tried, and the error of ctx is returned once for them
+	Import(ctx context.Context, planets []entity.Planet, progress func(i int, err error)) []error
 }
 
 type service struct {
@@ -29,23 +33,47 @@ func NewImporter(s planet.Service, swapi swapi.Service) Service {
 	}
 }
 
-func saveTask(ctx context.Context, planet entity.Planet, srv planet.Service, errs *[]error) {
-	defer wg.Done()
-	err := srv.Save(ctx, &planet)
-	if err != nil {
-		mutex.Lock()
-		*errs = append(*errs, err)
-		mutex.Unlock()
-	}
-}
-
 // Import data import
-func (i service) Import(ctx context.Context, planets []entity.Planet) []error {
-	var errs []error
+func (i service) Import(ctx context.Context, planets []entity.Planet, progress func(i int, err error)) []error {
+	var (
+		errs  []error
+		mutex sync.Mutex
+		wg    sync.WaitGroup
+	)
+
+	sem := make(chan struct{}, concurrency)
+
+	for n := range planets {
+		sem <- struct{}{}
+
+		if err := ctx.Err(); err != nil {
+			<-sem
+			mutex.Lock()
+			errs = append(errs, err)
+			mutex.Unlock()
+			break
+		}
 
-	for _, planet := range planets {
 		wg.Add(1)
-		go saveTask(ctx, planet, i.planetSrv, &errs)
+
+		go func(n int, planet entity.Planet) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			err := i.planetSrv.Save(ctx, &planet)
+
+			if err != nil {
+				mutex.Lock()
+				errs = append(errs, err)
+				mutex.Unlock()
+			}
+
+			if progress != nil {
+				progress(n, err)
+			}
+		}(n, planets[n])
 	}
 	wg.Wait()
 
@@ -6,6 +6,7 @@ import (
 	"star-wars/entity"
 	"star-wars/planet/mock_planet"
 	"star-wars/swapi/mock_swapi"
+	"sync"
 	"testing"
 	"time"
 
@@ -14,11 +15,10 @@ import (
 )
 
 var (
-	ctx, cancel               = context.WithTimeout(context.Background(), 2*time.Second)
-	pe          entity.Planet = entity.Planet{
+	pe entity.Planet = entity.Planet{
 		Name:       "Tatooine",
-		Climate:    "arid",
-		Terrain:    "desert",
+		Climate:    entity.Terms{"arid"},
+		Terrain:    entity.Terms{"desert"},
 		TotalFilms: 5,
 	}
 )
@@ -34,11 +34,12 @@ func TestProcess(t *testing.T) {
 	t.Run("happy path", func(t *testing.T) {
 		c, ps, s := configDep(t)
 		defer c.Finish()
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 		defer cancel()
 		ps.EXPECT().Save(ctx, &pe).Return(nil)
 
 		srv := NewImporter(ps, s)
-		errors := srv.Import(ctx, []entity.Planet{pe})
+		errors := srv.Import(ctx, []entity.Planet{pe}, nil)
 
 		assert.Equal(t, 0, len(errors))
 	})
@@ -46,12 +47,53 @@ func TestProcess(t *testing.T) {
 	t.Run("when save returns error", func(t *testing.T) {
 		c, ps, s := configDep(t)
 		defer c.Finish()
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 		defer cancel()
 		ps.EXPECT().Save(ctx, &pe).Return(errors.New("error"))
 
 		srv := NewImporter(ps, s)
-		errors := srv.Import(ctx, []entity.Planet{pe})
+		errors := srv.Import(ctx, []entity.Planet{pe}, nil)
 
 		assert.Equal(t, 1, len(errors))
 	})
+	t.Run("tells progress the outcome of each planet", func(t *testing.T) {
+		c, ps, s := configDep(t)
+		defer c.Finish()
+		failure := errors.New("error")
+		ps.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, p *entity.Planet) error {
+			if p.Name == "Hoth" {
+				return failure
+			}
+			return nil
+		}).Times(2)
+
+		hoth := pe
+		hoth.Name = "Hoth"
+
+		var mutex sync.Mutex
+		outcomes := map[int]error{}
+
+		srv := NewImporter(ps, s)
+		errors := srv.Import(context.Background(), []entity.Planet{pe, hoth}, func(i int, err error) {
+			mutex.Lock()
+			outcomes[i] = err
+			mutex.Unlock()
+		})
+
+		assert.Equal(t, []error{failure}, errors)
+		assert.Equal(t, map[int]error{0: nil, 1: failure}, outcomes)
+	})
+	t.Run("stops once ctx is done", func(t *testing.T) {
+		c, ps, s := configDep(t)
+		defer c.Finish()
+
+		done, stop := context.WithCancel(context.Background())
+		stop()
+
+		var told int
+		errors := NewImporter(ps, s).Import(done, []entity.Planet{pe, pe}, func(int, error) { told++ })
+
+		assert.Equal(t, []error{context.Canceled}, errors)
+		assert.Equal(t, 0, told)
+	})
 }
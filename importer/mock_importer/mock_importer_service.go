@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: importer/importer_service.go
+
+// Package mock_importer is a generated GoMock package.
+package mock_importer
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	entity "star-wars/entity"
+)
+
+// MockService is a mock of Service interface
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// Import mocks base method
+func (m *MockService) Import(ctx context.Context, planets []entity.Planet, progress func(int, error)) []error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, planets, progress)
+	ret0, _ := ret[0].([]error)
+	return ret0
+}
+
+// Import indicates an expected call of Import
+func (mr *MockServiceMockRecorder) Import(ctx, planets, progress interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockService)(nil).Import), ctx, planets, progress)
+}
@@ -0,0 +1,33 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// retention of finished jobs, their outcome is only polled for a while
+const retention = 7 * 24 * time.Hour
+
+// EnsureIndexes creates the index workers claim jobs by and the TTL index
+// dropping finished jobs
+func EnsureIndexes(ctx context.Context) error {
+	coll, err := cnx(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "finishedAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(retention.Seconds())),
+		},
+	})
+
+	return err
+}
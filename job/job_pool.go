@@ -0,0 +1,207 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"star-wars/api/handler"
+	"star-wars/audit"
+	"star-wars/entity"
+	"star-wars/logger"
+	"star-wars/requestid"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Handler runs a job of a kind, filling in its outcome; save stores the
+// progress made so far in j and extends the lease of the worker
+type Handler func(ctx context.Context, j *entity.Job, save func(ctx context.Context) error) error
+
+type kind struct {
+	handler Handler
+	timeout time.Duration
+}
+
+// maxAttempts runs of a job whose worker died before it is given up
+const maxAttempts = 3
+
+// leaseMargin kept beyond the timeout of a job before another worker takes it
+// over, covering the writes around the run
+const leaseMargin = 30 * time.Second
+
+// finishTimeout budget to store the outcome of a job
+const finishTimeout = 5 * time.Second
+
+// Pool runs the queued jobs with a fixed number of workers. Workers poll the
+// repository, so jobs queued by any instance, or left by one that stopped,
+// are run as well; Wake spares the wait for jobs queued by this one.
+type Pool struct {
+	repo    Repository
+	kinds   map[string]kind
+	workers int
+	poll    time.Duration
+	lease   time.Duration
+	now     func() time.Time
+
+	wake chan struct{}
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewPool of workers looking for jobs every poll
+func NewPool(r Repository, workers int, poll time.Duration) *Pool {
+	return &Pool{
+		repo:    r,
+		kinds:   map[string]kind{},
+		workers: workers,
+		poll:    poll,
+		lease:   leaseMargin,
+		now:     func() time.Time { return time.Now().UTC() },
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+	}
+}
+
+// Handle registers the handler of the jobs of name, each run for at most
+// timeout, before Start
+func (p *Pool) Handle(name string, timeout time.Duration, h Handler) {
+	p.kinds[name] = kind{handler: h, timeout: timeout}
+
+	// jobs are claimed before their kind is known, the lease fits any
+	if timeout+leaseMargin > p.lease {
+		p.lease = timeout + leaseMargin
+	}
+}
+
+// Wake tells an idle worker a job was queued
+func (p *Pool) Wake() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start the workers
+func (p *Pool) Start() {
+	for i := 0; i < p.workers; i++ {
+		p.wg.Add(1)
+		go p.work()
+	}
+}
+
+// Shutdown stops claiming jobs and waits for the running ones, until ctx is
+// done; the jobs cut short are taken over once their lease ends
+func (p *Pool) Shutdown(ctx context.Context) error {
+	close(p.stop)
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		default:
+		}
+
+		if p.next() {
+			continue
+		}
+
+		select {
+		case <-p.stop:
+			return
+		case <-p.wake:
+		case <-time.After(p.poll):
+		}
+	}
+}
+
+// next runs a job, telling whether there was one
+func (p *Pool) next() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), finishTimeout)
+	defer cancel()
+
+	j, err := p.repo.Claim(ctx, p.now(), p.lease)
+	if err != nil {
+		logger.L().Warn("job claim failed", zap.Error(err))
+		return false
+	}
+
+	if j == nil {
+		return false
+	}
+
+	p.run(j)
+	return true
+}
+
+// run j in the context of the request that queued it and store the outcome
+func (p *Pool) run(j *entity.Job) {
+	ctx := audit.WithActor(requestid.With(context.Background(), j.RequestID), j.Actor)
+	log := logger.FromContext(ctx).With(zap.String("job", j.ID), zap.String("kind", j.Kind))
+
+	err := p.execute(ctx, j)
+
+	finished := p.now()
+	j.FinishedAt = &finished
+	j.Status = entity.JobSucceeded
+
+	if err != nil {
+		j.Status = entity.JobFailed
+		j.Error = &entity.JobError{Status: handler.Status(err), Message: err.Error()}
+
+		if j.Error.Status == http.StatusInternalServerError {
+			log.Error("job failed", zap.Error(handler.Cause(err)))
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, finishTimeout)
+	defer cancel()
+
+	if err := p.repo.Finish(ctx, j); err != nil {
+		log.Warn("job outcome not stored", zap.Error(err))
+	}
+}
+
+func (p *Pool) execute(ctx context.Context, j *entity.Job) (err error) {
+	if j.Attempts > maxAttempts {
+		return fmt.Errorf("job given up after %d attempts", maxAttempts)
+	}
+
+	k, ok := p.kinds[j.Kind]
+	if !ok {
+		return handler.InternalServer{Message: "unknown job kind " + j.Kind}
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = handler.InternalServer{Message: fmt.Sprint("job panicked: ", r)}
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, k.timeout)
+	defer cancel()
+
+	save := func(ctx context.Context) error {
+		return p.repo.Progress(ctx, j, p.now().Add(p.lease))
+	}
+
+	return k.handler(ctx, j, save)
+}
@@ -0,0 +1,175 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"star-wars/api/handler"
+	"star-wars/audit"
+	"star-wars/entity"
+	"star-wars/requestid"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func pool(r Repository) *Pool {
+	p := NewPool(r, 1, time.Hour)
+	p.now = func() time.Time { return now }
+	return p
+}
+
+func TestRun(t *testing.T) {
+	saved := &entity.Planet{ID: "5f2c891e9a9e070b1ef2e28c", Name: "Tatooine"}
+
+	type test struct {
+		name    string
+		job     entity.Job
+		handler Handler
+		wantJob entity.Job
+	}
+
+	tests := []test{
+		{
+			name: "when it succeeds",
+			job:  entity.Job{ID: "1", Kind: "test", Actor: "luke", RequestID: "req-1", Attempts: 1},
+			handler: func(ctx context.Context, j *entity.Job, _ func(context.Context) error) error {
+				assert.Equal(t, "luke", audit.ActorFrom(ctx))
+				assert.Equal(t, "req-1", requestid.From(ctx))
+				j.Planet = saved
+				return nil
+			},
+			wantJob: entity.Job{ID: "1", Kind: "test", Status: entity.JobSucceeded, Actor: "luke", RequestID: "req-1", Attempts: 1, Planet: saved, FinishedAt: &now},
+		},
+		{
+			name: "when it fails",
+			job:  entity.Job{ID: "1", Kind: "test", Attempts: 1},
+			handler: func(context.Context, *entity.Job, func(context.Context) error) error {
+				return handler.BadRequest{Message: "planet already registered"}
+			},
+			wantJob: entity.Job{ID: "1", Kind: "test", Status: entity.JobFailed, Attempts: 1, FinishedAt: &now,
+				Error: &entity.JobError{Status: 400, Message: "planet already registered"}},
+		},
+		{
+			name:    "when it panics",
+			job:     entity.Job{ID: "1", Kind: "test", Attempts: 1},
+			handler: func(context.Context, *entity.Job, func(context.Context) error) error { panic("boom") },
+			wantJob: entity.Job{ID: "1", Kind: "test", Status: entity.JobFailed, Attempts: 1, FinishedAt: &now,
+				Error: &entity.JobError{Status: 500, Message: "internal server error"}},
+		},
+		{
+			name: "when its workers died too often",
+			job:  entity.Job{ID: "1", Kind: "test", Attempts: maxAttempts + 1},
+			handler: func(context.Context, *entity.Job, func(context.Context) error) error {
+				t.Error("the job must not run again")
+				return nil
+			},
+			wantJob: entity.Job{ID: "1", Kind: "test", Status: entity.JobFailed, Attempts: maxAttempts + 1, FinishedAt: &now,
+				Error: &entity.JobError{Status: 500, Message: "job given up after 3 attempts"}},
+		},
+		{
+			name:    "when the kind is unknown",
+			job:     entity.Job{ID: "1", Kind: "other", Attempts: 1},
+			handler: func(context.Context, *entity.Job, func(context.Context) error) error { return nil },
+			wantJob: entity.Job{ID: "1", Kind: "other", Status: entity.JobFailed, Attempts: 1, FinishedAt: &now,
+				Error: &entity.JobError{Status: 500, Message: "internal server error"}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			c, r, _, _ := configDep(t)
+			defer c.Finish()
+
+			r.EXPECT().Finish(gomock.Any(), &tt.wantJob).Return(nil)
+
+			p := pool(r)
+			p.Handle("test", time.Second, tt.handler)
+			p.run(&tt.job)
+		})
+	}
+}
+
+// queue hands out its jobs once, recording their outcome
+type queue struct {
+	mu       sync.Mutex
+	pending  []*entity.Job
+	finished chan *entity.Job
+}
+
+func (q *queue) Insert(context.Context, *entity.Job) error { return nil }
+
+func (q *queue) FindByID(context.Context, string) (*entity.Job, error) { return nil, ErrNotFound }
+
+func (q *queue) Claim(context.Context, time.Time, time.Duration) (*entity.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.pending) == 0 {
+		return nil, nil
+	}
+
+	j := q.pending[0]
+	q.pending = q.pending[1:]
+	return j, nil
+}
+
+func (q *queue) Progress(context.Context, *entity.Job, time.Time) error { return nil }
+
+func (q *queue) Finish(_ context.Context, j *entity.Job) error {
+	q.finished <- j
+	return nil
+}
+
+func (q *queue) push(j *entity.Job) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending = append(q.pending, j)
+}
+
+func TestPool(t *testing.T) {
+	q := &queue{finished: make(chan *entity.Job, 1)}
+	p := pool(q)
+	p.Handle("test", time.Second, func(context.Context, *entity.Job, func(context.Context) error) error { return nil })
+	p.Start()
+
+	q.push(&entity.Job{ID: "1", Kind: "test"})
+	p.Wake()
+
+	select {
+	case j := <-q.finished:
+		assert.Equal(t, entity.JobSucceeded, j.Status, "woken before the poll interval")
+	case <-time.After(time.Second):
+		t.Fatal("the job did not run")
+	}
+
+	assert.NoError(t, p.Shutdown(ctx))
+}
+
+func TestShutdown(t *testing.T) {
+	q := &queue{finished: make(chan *entity.Job, 1)}
+	p := pool(q)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	p.Handle("test", time.Second, func(context.Context, *entity.Job, func(context.Context) error) error {
+		close(started)
+		<-release
+		return errors.New("cut short")
+	})
+
+	q.push(&entity.Job{ID: "1", Kind: "test"})
+	p.Start()
+	<-started
+
+	short, stop := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer stop()
+
+	assert.Equal(t, context.DeadlineExceeded, p.Shutdown(short), "the running job is not waited past ctx")
+
+	close(release)
+	<-q.finished
+}
@@ -0,0 +1,181 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"star-wars/database"
+	"star-wars/entity"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Repository contract
+type Repository interface {
+	Insert(ctx context.Context, j *entity.Job) error
+	FindByID(ctx context.Context, id string) (*entity.Job, error)
+	// Claim takes the oldest pending job, or a running one whose lease ended,
+	// for lease; nil when there is none
+	Claim(ctx context.Context, now time.Time, lease time.Duration) (*entity.Job, error)
+	// Progress stores the progress of j and extends its lease until, unless
+	// another worker took it over
+	Progress(ctx context.Context, j *entity.Job, until time.Time) error
+	// Finish stores the outcome of j, unless another worker took it over
+	Finish(ctx context.Context, j *entity.Job) error
+}
+
+// ErrNotFound the job does not exist
+var ErrNotFound = errors.New("job not found")
+
+// ErrLost the lease of the job ended and another worker took it over
+var ErrLost = errors.New("job taken over by another worker")
+
+type repo struct{}
+
+// NewRepository jobs
+func NewRepository() Repository {
+	return &repo{}
+}
+
+// cnx is patched in tests, keep it out of line
+//
+//go:noinline
+func cnx(ctx context.Context) (*mongo.Collection, error) {
+	return database.Collection(ctx, "jobs")
+}
+
+func (r repo) Insert(ctx context.Context, j *entity.Job) error {
+	coll, err := cnx(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	result, err := coll.InsertOne(ctx, j)
+
+	if err != nil {
+		return err
+	}
+
+	oid, _ := result.InsertedID.(primitive.ObjectID)
+	j.ID = oid.Hex()
+
+	return nil
+}
+
+func (r repo) FindByID(ctx context.Context, id string) (*entity.Job, error) {
+	_id, err := primitive.ObjectIDFromHex(id)
+
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	coll, err := cnx(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var j entity.Job
+
+	err = coll.FindOne(ctx, bson.M{"_id": _id}).Decode(&j)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &j, nil
+}
+
+func (r repo) Claim(ctx context.Context, now time.Time, lease time.Duration) (*entity.Job, error) {
+	coll, err := cnx(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	opt := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var j entity.Job
+
+	err = coll.FindOneAndUpdate(
+		ctx,
+		bson.M{"$or": bson.A{
+			bson.M{"status": entity.JobPending},
+			bson.M{"status": entity.JobRunning, "lockedUntil": bson.M{"$lte": now}},
+		}},
+		bson.M{
+			"$set": bson.M{"status": entity.JobRunning, "startedAt": now, "lockedUntil": now.Add(lease)},
+			"$inc": bson.M{"attempts": 1},
+		},
+		opt,
+	).Decode(&j)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &j, nil
+}
+
+func (r repo) Progress(ctx context.Context, j *entity.Job, until time.Time) error {
+	return r.update(ctx, j, bson.M{"$set": bson.M{"import": j.Import, "lockedUntil": until}})
+}
+
+func (r repo) Finish(ctx context.Context, j *entity.Job) error {
+	return r.update(ctx, j, bson.M{
+		"$set": bson.M{
+			"status":     j.Status,
+			"planet":     j.Planet,
+			"import":     j.Import,
+			"error":      j.Error,
+			"finishedAt": j.FinishedAt,
+		},
+		"$unset": bson.M{"lockedUntil": ""},
+	})
+}
+
+// update the run of j, the attempt tells it from the one of a worker that
+// took the job over
+func (r repo) update(ctx context.Context, j *entity.Job, update bson.M) error {
+	_id, err := primitive.ObjectIDFromHex(j.ID)
+
+	if err != nil {
+		return ErrNotFound
+	}
+
+	coll, err := cnx(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	result, err := coll.UpdateOne(
+		ctx,
+		bson.M{"_id": _id, "status": entity.JobRunning, "attempts": j.Attempts},
+		update,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrLost
+	}
+
+	return nil
+}
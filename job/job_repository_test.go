@@ -0,0 +1,107 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"star-wars/entity"
+	"star-wars/planet/monkey_patch/mongo_db"
+	"testing"
+
+	"bou.ke/monkey"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func monkeyCnx(guard *monkey.PatchGuard, err bool) *monkey.PatchGuard {
+	guard = monkey.Patch(cnx, func(ctx context.Context) (*mongo.Collection, error) {
+		if err {
+			return nil, errors.New("connection error")
+		}
+
+		c, _ := mongo.NewClient()
+		coll := c.Database("").Collection("")
+
+		return coll, nil
+	})
+	return guard
+}
+
+func TestInsert_Repository(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, false)
+
+		var guardInsertOne monkey.PatchGuard
+		mongo_db.InsertOne(&guardInsertOne, "5f3a1c2b9a9e070b1ef2e29a", false)
+
+		j := &entity.Job{Kind: entity.JobCreatePlanet}
+		err := NewRepository().Insert(ctx, j)
+
+		assert.Equal(t, nil, err)
+		assert.Equal(t, "5f3a1c2b9a9e070b1ef2e29a", j.ID)
+	})
+
+	t.Run("when connection error", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, true)
+
+		err := NewRepository().Insert(ctx, &entity.Job{})
+
+		assert.Equal(t, "connection error", err.Error())
+	})
+}
+
+func TestFindByID_Repository(t *testing.T) {
+	t.Run("when the id is invalid", func(t *testing.T) {
+		_, err := NewRepository().FindByID(ctx, "job")
+
+		assert.Equal(t, ErrNotFound, err)
+	})
+
+	t.Run("when connection error", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, true)
+
+		_, err := NewRepository().FindByID(ctx, "5f3a1c2b9a9e070b1ef2e29a")
+
+		assert.Equal(t, "connection error", err.Error())
+	})
+}
+
+func TestFinish_Repository(t *testing.T) {
+	type test struct {
+		name    string
+		matched int64
+		dbErr   bool
+		want    error
+	}
+
+	tests := []test{
+		{name: "happy path", matched: 1},
+		{name: "when another worker took the job over", matched: 0, want: ErrLost},
+		{name: "when db returns error", dbErr: true, want: errors.New("update one error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var guardCnx monkey.PatchGuard
+			monkeyCnx(&guardCnx, false)
+
+			var guardUpdateOne monkey.PatchGuard
+			mongo_db.UpdateOne(&guardUpdateOne, tt.matched, tt.dbErr)
+
+			err := NewRepository().Finish(ctx, &entity.Job{ID: "5f3a1c2b9a9e070b1ef2e29a", Status: entity.JobSucceeded, Attempts: 1})
+
+			assert.Equal(t, tt.want, err)
+		})
+	}
+}
+
+func TestClaim_Repository(t *testing.T) {
+	var guardCnx monkey.PatchGuard
+	monkeyCnx(&guardCnx, true)
+
+	_, err := NewRepository().Claim(ctx, now, leaseMargin)
+
+	assert.Equal(t, "connection error", err.Error())
+}
@@ -0,0 +1,61 @@
+package job
+
+import (
+	"context"
+	"star-wars/api/handler"
+	"star-wars/audit"
+	"star-wars/entity"
+	"star-wars/requestid"
+	"time"
+)
+
+// Service contract
+type Service interface {
+	// Enqueue stores j as pending, for the pool to run
+	Enqueue(ctx context.Context, j *entity.Job) error
+	Find(ctx context.Context, id string) (*entity.Job, error)
+}
+
+type srv struct {
+	repo Repository
+	wake func()
+	now  func() time.Time
+}
+
+// NewService returns a job service instance; wake tells the pool a job was queued
+func NewService(r Repository, wake func()) Service {
+	return &srv{
+		repo: r,
+		wake: wake,
+		now:  func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func (s srv) Enqueue(ctx context.Context, j *entity.Job) error {
+	j.Status = entity.JobPending
+	j.Actor = audit.ActorFrom(ctx)
+	j.RequestID = requestid.From(ctx)
+	j.CreatedAt = s.now()
+
+	if err := s.repo.Insert(ctx, j); err != nil {
+		return handler.InternalServer{Message: err.Error()}
+	}
+
+	s.wake()
+
+	return nil
+}
+
+func (s srv) Find(ctx context.Context, id string) (*entity.Job, error) {
+	j, err := s.repo.FindByID(ctx, id)
+
+	if err == ErrNotFound {
+		return nil, handler.NotFound{Message: err.Error()}
+	}
+
+	if err != nil {
+		return nil, handler.InternalServer{Message: err.Error()}
+	}
+
+	return j, nil
+}
@@ -0,0 +1,96 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"star-wars/api/handler"
+	"star-wars/audit"
+	"star-wars/entity"
+	"star-wars/job/mock_job"
+	"star-wars/requestid"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	ctx, cancel = context.WithTimeout(context.Background(), 2*time.Second)
+	now         = time.Date(2020, 8, 7, 10, 0, 0, 0, time.UTC)
+)
+
+func configDep(t *testing.T) (*gomock.Controller, *mock_job.MockRepository, *int, Service) {
+	c := gomock.NewController(t)
+	r := mock_job.NewMockRepository(c)
+	woken := 0
+	return c, r, &woken, &srv{repo: r, wake: func() { woken++ }, now: func() time.Time { return now }}
+}
+
+func TestEnqueue(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		c, r, woken, s := configDep(t)
+		defer c.Finish()
+
+		r.EXPECT().Insert(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, j *entity.Job) error {
+			j.ID = "5f3a1c2b9a9e070b1ef2e29a"
+			return nil
+		})
+
+		j := &entity.Job{Kind: entity.JobCreatePlanet, Planet: &entity.Planet{Name: "Tatooine"}}
+		err := s.Enqueue(audit.WithActor(requestid.With(ctx, "req-1"), "luke"), j)
+
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.Job{
+			ID:        "5f3a1c2b9a9e070b1ef2e29a",
+			Kind:      entity.JobCreatePlanet,
+			Status:    entity.JobPending,
+			Actor:     "luke",
+			RequestID: "req-1",
+			Planet:    &entity.Planet{Name: "Tatooine"},
+			CreatedAt: now,
+		}, j)
+		assert.Equal(t, 1, *woken)
+	})
+
+	t.Run("when db returns error", func(t *testing.T) {
+		c, r, woken, s := configDep(t)
+		defer c.Finish()
+
+		r.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+
+		err := s.Enqueue(ctx, &entity.Job{Kind: entity.JobCreatePlanet})
+
+		assert.Equal(t, handler.InternalServer{Message: "connection refused"}, err)
+		assert.Equal(t, 0, *woken)
+	})
+}
+
+func TestFind(t *testing.T) {
+	type test struct {
+		name    string
+		repoJob *entity.Job
+		repoErr error
+		wantErr error
+	}
+
+	tests := []test{
+		{name: "happy path", repoJob: &entity.Job{ID: "5f3a1c2b9a9e070b1ef2e29a"}},
+		{name: "when the job does not exist", repoErr: ErrNotFound, wantErr: handler.NotFound{Message: "job not found"}},
+		{name: "when db returns error", repoErr: errors.New("connection refused"), wantErr: handler.InternalServer{Message: "connection refused"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, r, _, s := configDep(t)
+			defer c.Finish()
+
+			r.EXPECT().FindByID(gomock.Any(), "5f3a1c2b9a9e070b1ef2e29a").Return(tt.repoJob, tt.repoErr)
+
+			j, err := s.Find(ctx, "5f3a1c2b9a9e070b1ef2e29a")
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.repoJob, j)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"net/http"
+	"star-wars/clientip"
+	"star-wars/requestid"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Middleware writes one access log line per request
+func Middleware(c *gin.Context) {
+	start := time.Now()
+
+	c.Next()
+
+	status := c.Writer.Status()
+	fields := []zap.Field{
+		zap.String("method", c.Request.Method),
+		zap.String("route", c.FullPath()),
+		zap.String("path", c.Request.URL.Path),
+		zap.Int("status", status),
+		zap.Duration("latency", time.Since(start)),
+		zap.String("clientIp", clientip.Of(c.Request)),
+	}
+
+	if len(c.Errors) > 0 {
+		fields = append(fields, zap.String("errors", c.Errors.String()))
+	}
+
+	l := FromContext(c.Request.Context())
+
+	switch {
+	case status >= http.StatusInternalServerError:
+		l.Error("request", fields...)
+	case status >= http.StatusBadRequest:
+		l.Warn("request", fields...)
+	default:
+		l.Info("request", fields...)
+	}
+}
+
+// Recovery logs a panic with its stack and answers 500
+func Recovery(c *gin.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			FromContext(c.Request.Context()).Error("panic", zap.Any("panic", r), zap.Stack("stack"))
+
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error":     "internal server error",
+				"requestId": requestid.From(c.Request.Context()),
+			})
+		}
+	}()
+
+	c.Next()
+}
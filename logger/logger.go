@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"context"
+	"star-wars/requestid"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	level = zap.NewAtomicLevel()
+	root  = newLogger()
+)
+
+func newLogger() *zap.Logger {
+	cfg := zap.NewProductionConfig()
+	cfg.Level = level
+	cfg.DisableStacktrace = true
+	cfg.EncoderConfig.TimeKey = "time"
+	cfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	l, err := cfg.Build()
+	if err != nil {
+		return zap.NewNop()
+	}
+	return l
+}
+
+// SetLevel changes the minimum level: debug, info, warn or error
+func SetLevel(name string) error {
+	if name == "" {
+		return nil
+	}
+	return level.UnmarshalText([]byte(name))
+}
+
+// L application logger
+func L() *zap.Logger {
+	return root
+}
+
+// FromContext logger carrying the request ID and the trace found in ctx
+func FromContext(ctx context.Context) *zap.Logger {
+	l := root
+
+	if id := requestid.From(ctx); id != "" {
+		l = l.With(zap.String("requestId", id))
+	}
+
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		l = l.With(zap.String("traceId", sc.TraceID().String()), zap.String("spanId", sc.SpanID().String()))
+	}
+
+	return l
+}
+
+// Sync flushes buffered entries
+func Sync() {
+	_ = root.Sync()
+}
@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"star-wars/requestid"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func observe(t *testing.T) *observer.ObservedLogs {
+	core, logs := observer.New(zapcore.DebugLevel)
+	previous := root
+	root = zap.New(core)
+	t.Cleanup(func() { root = previous })
+	return logs
+}
+
+func TestFromContext(t *testing.T) {
+	logs := observe(t)
+
+	FromContext(requestid.With(context.Background(), "req-1")).Info("saved")
+	FromContext(context.Background()).Info("saved")
+
+	entries := logs.All()
+	assert.Equal(t, "req-1", entries[0].ContextMap()["requestId"])
+	assert.NotContains(t, entries[1].ContextMap(), "requestId")
+}
+
+func TestMiddleware(t *testing.T) {
+	logs := observe(t)
+
+	router := gin.New()
+	router.Use(requestid.Middleware, Middleware, Recovery)
+	router.GET("/planets/:id", func(c *gin.Context) {
+		c.Status(http.StatusNotFound)
+	})
+	router.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	req, _ := http.NewRequest("GET", "/planets/1", nil)
+	req.Header.Set(requestid.Header, "req-1")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	w := httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/panic", nil)
+	req.Header.Set(requestid.Header, "req-2")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, 500, w.Code)
+	assert.Equal(t, `{"error":"internal server error","requestId":"req-2"}`, w.Body.String())
+
+	entries := logs.All()
+	assert.Len(t, entries, 3)
+
+	assert.Equal(t, zapcore.WarnLevel, entries[0].Level)
+	assert.Equal(t, "/planets/:id", entries[0].ContextMap()["route"])
+	assert.Equal(t, int64(404), entries[0].ContextMap()["status"])
+	assert.Equal(t, "req-1", entries[0].ContextMap()["requestId"])
+
+	assert.Equal(t, "panic", entries[1].Message)
+	assert.Equal(t, "req-2", entries[1].ContextMap()["requestId"])
+	assert.Equal(t, zapcore.ErrorLevel, entries[2].Level)
+}
+
+func TestSetLevel(t *testing.T) {
+	defer SetLevel("info")
+
+	assert.Nil(t, SetLevel("warn"))
+	assert.False(t, L().Core().Enabled(zapcore.InfoLevel))
+	assert.NotNil(t, SetLevel("loud"))
+}
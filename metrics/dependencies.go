@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	repositoryDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "repository_operation_duration_seconds",
+			Help:    "Repository operation latency.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"repository", "operation"},
+	)
+
+	repositoryErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "repository_errors_total",
+			Help: "Repository operations that failed, not found excluded.",
+		},
+		[]string{"repository", "operation"},
+	)
+
+	swapiDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "swapi_request_duration_seconds",
+			Help:    "SWAPI call latency by status, error when no response arrived.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"operation", "status"},
+	)
+
+	swapiRetries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "swapi_retries_total",
+			Help: "SWAPI calls repeated after a failure.",
+		},
+		[]string{"operation"},
+	)
+
+	importerPlanets = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "importer_planets_total",
+			Help: "Planets processed by the importer by result.",
+		},
+		[]string{"result"},
+	)
+
+	rateLimited = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "http_rate_limited_total",
+			Help: "Requests rejected by the rate limiter by limit group.",
+		},
+		[]string{"group"},
+	)
+
+	importerDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "importer_run_duration_seconds",
+			Help:    "Importer run latency.",
+			Buckets: prometheus.ExponentialBuckets(0.1, 2, 12),
+		},
+	)
+)
+
+func init() {
+	Registry.MustRegister(
+		repositoryDuration,
+		repositoryErrors,
+		swapiDuration,
+		swapiRetries,
+		importerPlanets,
+		importerDuration,
+		rateLimited,
+	)
+}
+
+// Repository observes an operation started at start; failed tells whether it counts as an error
+func Repository(repository string, operation string, start time.Time, failed bool) {
+	repositoryDuration.WithLabelValues(repository, operation).Observe(time.Since(start).Seconds())
+
+	if failed {
+		repositoryErrors.WithLabelValues(repository, operation).Inc()
+	}
+}
+
+// Swapi observes a call started at start
+func Swapi(operation string, status string, start time.Time) {
+	swapiDuration.WithLabelValues(operation, status).Observe(time.Since(start).Seconds())
+}
+
+// SwapiRetry counts a repeated call
+func SwapiRetry(operation string) {
+	swapiRetries.WithLabelValues(operation).Inc()
+}
+
+// Import observes an importer run started at start
+func Import(imported int, failed int, start time.Time) {
+	importerPlanets.WithLabelValues("imported").Add(float64(imported))
+	importerPlanets.WithLabelValues("failed").Add(float64(failed))
+	importerDuration.Observe(time.Since(start).Seconds())
+}
+
+// RateLimited counts a request rejected with 429
+func RateLimited(group string) {
+	rateLimited.WithLabelValues(group).Inc()
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"star-wars/apikey"
+	"star-wars/database"
+	"star-wars/idempotency"
+	"star-wars/job"
+	"star-wars/logger"
+	"star-wars/planet"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// closeTimeout to disconnect from MongoDB once done
+const closeTimeout = 5 * time.Second
+
+func main() {
+	defer logger.Sync()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	// the work context may be over by now, the disconnect gets one of its own
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+		defer cancel()
+
+		if err := database.Close(ctx); err != nil {
+			logger.L().Warn("couldn't close mongodb", zap.Error(err))
+		}
+	}()
+
+	total, err := planet.NormalizeTerms(ctx)
+
+	if err != nil {
+		logger.L().Fatal("migration failed", zap.Error(err))
+	}
+
+	if err := apikey.EnsureIndexes(ctx); err != nil {
+		logger.L().Fatal("migration failed", zap.Error(err))
+	}
+
+	if err := idempotency.EnsureIndexes(ctx); err != nil {
+		logger.L().Fatal("migration failed", zap.Error(err))
+	}
+
+	if err := job.EnsureIndexes(ctx); err != nil {
+		logger.L().Fatal("migration failed", zap.Error(err))
+	}
+
+	logger.L().Info("migration completed", zap.Int64("planets", total))
+}
@@ -125,3 +125,46 @@ func Ping(guard *monkey.PatchGuard, err bool) *monkey.PatchGuard {
 	guard = monkey.PatchInstanceMethod(reflect.TypeOf(obj), "Ping", mockFn)
 	return guard
 }
+
+func Distinct(guard *monkey.PatchGuard, values []interface{}, err bool) *monkey.PatchGuard {
+	var coll *mongo.Collection
+	mockFn := func(coll *mongo.Collection, ctx context.Context, fieldName string, filter interface{}, opts ...*options.DistinctOptions) ([]interface{}, error) {
+		guard.Unpatch()
+		defer guard.Restore()
+		if err {
+			return nil, errors.New("distinct error")
+		}
+		return values, nil
+	}
+
+	guard = monkey.PatchInstanceMethod(reflect.TypeOf(coll), "Distinct", mockFn)
+	return guard
+}
+
+func UpdateOne(guard *monkey.PatchGuard, matched int64, err bool) *monkey.PatchGuard {
+	var coll *mongo.Collection
+	mockFn := func(coll *mongo.Collection, ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+		guard.Unpatch()
+		defer guard.Restore()
+		if err {
+			return nil, errors.New("update one error")
+		}
+		return &mongo.UpdateResult{MatchedCount: matched, ModifiedCount: matched}, nil
+	}
+
+	guard = monkey.PatchInstanceMethod(reflect.TypeOf(coll), "UpdateOne", mockFn)
+	return guard
+}
+
+// UseSessionWithOptions answers err instead of running the session
+func UseSessionWithOptions(guard *monkey.PatchGuard, err error) *monkey.PatchGuard {
+	var client *mongo.Client
+	mockFn := func(client *mongo.Client, ctx context.Context, opts *options.SessionOptions, fn func(mongo.SessionContext) error) error {
+		guard.Unpatch()
+		defer guard.Restore()
+		return err
+	}
+
+	guard = monkey.PatchInstanceMethod(reflect.TypeOf(client), "UseSessionWithOptions", mockFn)
+	return guard
+}
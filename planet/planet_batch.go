@@ -0,0 +1,197 @@
+package planet
+
+import (
+	"context"
+	"fmt"
+	"star-wars/api/handler"
+	"star-wars/entity"
+	"sync"
+)
+
+// batchConcurrency items of a batch processed at a time, which bounds the
+// SWAPI lookups in flight as well
+const batchConcurrency = 4
+
+// SaveMany saves planets, errs[i] is the outcome of planets[i]. Each planet is
+// saved on its own, unless atomic: then they are saved in a transaction and
+// none is when any fails
+func (s srv) SaveMany(ctx context.Context, planets []entity.Planet, atomic bool) ([]error, error) {
+	errs := make([]error, len(planets))
+	repeated(errs, func(i int) string { return planets[i].Name })
+
+	save := s.Save
+	if atomic {
+		save = s.prepare
+	}
+
+	each(len(planets), func(i int) {
+		if errs[i] == nil {
+			errs[i] = save(ctx, &planets[i])
+		}
+	})
+
+	if !atomic || abort(errs) {
+		return errs, nil
+	}
+
+	err := s.atomically(ctx, errs, func(ctx context.Context, i int) error {
+		return s.repo.Save(ctx, &planets[i])
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if failed(errs) >= 0 {
+		return errs, nil
+	}
+
+	for i := range planets {
+		s.record(ctx, entity.ActionCreate, planets[i].ID, nil, &planets[i])
+	}
+
+	return errs, nil
+}
+
+// DeleteMany deletes the planets of refs, errs[i] is the outcome of refs[i];
+// atomic works as in SaveMany
+func (s srv) DeleteMany(ctx context.Context, refs []entity.PlanetRef, atomic bool) ([]error, error) {
+	errs := make([]error, len(refs))
+	repeated(errs, func(i int) string { return refs[i].ID })
+
+	if !atomic {
+		each(len(refs), func(i int) {
+			if errs[i] == nil {
+				errs[i] = s.Delete(ctx, refs[i].ID, refs[i].Version)
+			}
+		})
+		return errs, nil
+	}
+
+	before := make([]*entity.Planet, len(refs))
+
+	each(len(refs), func(i int) {
+		if errs[i] == nil {
+			before[i], errs[i] = s.deletable(ctx, refs[i].ID, refs[i].Version)
+		}
+	})
+
+	if abort(errs) {
+		return errs, nil
+	}
+
+	err := s.atomically(ctx, errs, func(ctx context.Context, i int) error {
+		if before[i] == nil {
+			return nil
+		}
+		return s.repo.Delete(ctx, refs[i].ID, before[i].Version)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if failed(errs) >= 0 {
+		return errs, nil
+	}
+
+	for i, p := range before {
+		if p != nil {
+			s.record(ctx, entity.ActionDelete, refs[i].ID, p, nil)
+		}
+	}
+
+	return errs, nil
+}
+
+// atomically writes every item of a prepared batch in a transaction; the item
+// whose write fails fails the batch
+func (s srv) atomically(ctx context.Context, errs []error, write func(ctx context.Context, i int) error) error {
+	at := -1
+
+	err := s.repo.Transaction(ctx, func(ctx context.Context) error {
+		at = -1
+		for i := range errs {
+			if err := write(ctx, i); err != nil {
+				at = i
+				return err
+			}
+		}
+		return nil
+	})
+
+	switch {
+	case err == nil:
+		return nil
+	case err == ErrNoTransactions:
+		return handler.BadRequest{Message: "atomic batches are not supported by the database"}
+	case at < 0:
+		return handler.InternalServer{Message: err.Error()}
+	}
+
+	errs[at] = writeError(err)
+	abort(errs)
+	return nil
+}
+
+// each runs fn for every index below n, batchConcurrency at a time
+func each(n int, fn func(i int)) {
+	sem := make(chan struct{}, batchConcurrency)
+	var wg sync.WaitGroup
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(i)
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+// repeated fails the items whose key an earlier item of the batch has
+func repeated(errs []error, key func(i int) string) {
+	seen := map[string]bool{}
+
+	for i := range errs {
+		if seen[key(i)] {
+			errs[i] = handler.BadRequest{Message: "planet is repeated in the batch"}
+		}
+		seen[key(i)] = true
+	}
+}
+
+// failed index of the first failed item, -1 when none failed
+func failed(errs []error) int {
+	for i, err := range errs {
+		if err != nil {
+			return i
+		}
+	}
+	return -1
+}
+
+// abort fails the items left undone because another one failed, telling
+// whether any did
+func abort(errs []error) bool {
+	at := failed(errs)
+	if at < 0 {
+		return false
+	}
+
+	undone := handler.FailedDependency{Message: fmt.Sprintf("item %d of the batch failed", at)}
+
+	for i := range errs {
+		if errs[i] == nil {
+			errs[i] = undone
+		}
+	}
+
+	return true
+}
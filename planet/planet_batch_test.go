@@ -0,0 +1,226 @@
+package planet
+
+import (
+	"context"
+	"errors"
+	"star-wars/api/handler"
+	"star-wars/entity"
+	"star-wars/swapi/adapter"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func batchOf(names ...string) []entity.Planet {
+	planets := make([]entity.Planet, len(names))
+	for i, name := range names {
+		planets[i] = entity.Planet{Name: name, Climate: entity.Terms{"arid"}, Terrain: entity.Terms{"desert"}}
+	}
+	return planets
+}
+
+var appearances = adapter.Planets{Count: 1, Results: []adapter.Planet{{Films: []string{"film"}}}}
+
+// inTransaction runs the writes of the batch as a database supporting transactions would
+func inTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestSaveMany(t *testing.T) {
+	t.Run("saves each planet on its own", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+
+		r.EXPECT().FindByName(gomock.Any(), gomock.Any()).Return(nil, errors.New("mongo: no documents in result")).Times(2)
+		s.EXPECT().GetPlanet(gomock.Any(), "Tatooine").Return(appearances, nil)
+		s.EXPECT().GetPlanet(gomock.Any(), "Hoth").Return(adapter.Planets{}, nil)
+		r.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, p *entity.Planet) error {
+			p.ID = "5f2c891e9a9e070b1ef2e28c"
+			return nil
+		})
+		a.EXPECT().Record(gomock.Any(), entity.ActionCreate, "5f2c891e9a9e070b1ef2e28c", nil, gomock.Any()).Return(nil)
+
+		planets := batchOf("Tatooine", "Hoth", "Tatooine")
+		errs, err := NewService(r, s, a).SaveMany(ctx, planets, false)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []error{
+			nil,
+			handler.BadRequest{Message: "non-existent planet"},
+			handler.BadRequest{Message: "planet is repeated in the batch"},
+		}, errs)
+		assert.Equal(t, "5f2c891e9a9e070b1ef2e28c", planets[0].ID)
+		assert.Equal(t, 1, planets[0].TotalFilms)
+	})
+
+	t.Run("atomic saves every planet in a transaction", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+
+		r.EXPECT().FindByName(gomock.Any(), gomock.Any()).Return(nil, errors.New("mongo: no documents in result")).Times(2)
+		s.EXPECT().GetPlanet(gomock.Any(), gomock.Any()).Return(appearances, nil).Times(2)
+		r.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(inTransaction)
+		r.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+		a.EXPECT().Record(gomock.Any(), entity.ActionCreate, gomock.Any(), nil, gomock.Any()).Return(nil).Times(2)
+
+		errs, err := NewService(r, s, a).SaveMany(ctx, batchOf("Tatooine", "Hoth"), true)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []error{nil, nil}, errs)
+	})
+
+	t.Run("atomic saves nothing when a planet is invalid", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+
+		r.EXPECT().FindByName(gomock.Any(), "Tatooine").Return(nil, errors.New("mongo: no documents in result"))
+		s.EXPECT().GetPlanet(gomock.Any(), "Tatooine").Return(appearances, nil)
+
+		planets := batchOf("Tatooine", "Hoth")
+		planets[1].Terrain = entity.Terms{"sand"}
+
+		errs, err := NewService(r, s, a).SaveMany(ctx, planets, true)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []error{
+			handler.FailedDependency{Message: "item 1 of the batch failed"},
+			handler.BadRequest{Message: `terrain has unknown value: "sand"`},
+		}, errs)
+	})
+
+	t.Run("atomic rolls back when a write fails", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+
+		r.EXPECT().FindByName(gomock.Any(), gomock.Any()).Return(nil, errors.New("mongo: no documents in result")).Times(3)
+		s.EXPECT().GetPlanet(gomock.Any(), gomock.Any()).Return(appearances, nil).Times(3)
+		r.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(inTransaction)
+		gomock.InOrder(
+			r.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil),
+			r.EXPECT().Save(gomock.Any(), gomock.Any()).Return(errors.New("write conflict")),
+		)
+
+		errs, err := NewService(r, s, a).SaveMany(ctx, batchOf("Tatooine", "Hoth", "Naboo"), true)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []error{
+			handler.FailedDependency{Message: "item 1 of the batch failed"},
+			handler.InternalServer{Message: "write conflict"},
+			handler.FailedDependency{Message: "item 1 of the batch failed"},
+		}, errs)
+	})
+
+	t.Run("atomic without transactions", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+
+		r.EXPECT().FindByName(gomock.Any(), "Tatooine").Return(nil, errors.New("mongo: no documents in result"))
+		s.EXPECT().GetPlanet(gomock.Any(), "Tatooine").Return(appearances, nil)
+		r.EXPECT().Transaction(gomock.Any(), gomock.Any()).Return(ErrNoTransactions)
+
+		errs, err := NewService(r, s, a).SaveMany(ctx, batchOf("Tatooine"), true)
+
+		assert.Nil(t, errs)
+		assert.Equal(t, handler.BadRequest{Message: "atomic batches are not supported by the database"}, err)
+	})
+}
+
+func TestDeleteMany(t *testing.T) {
+	found := &entity.Planet{ID: "5f2c891e9a9e070b1ef2e28c", Name: "Tatooine", Version: 2}
+
+	t.Run("deletes each planet on its own", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+
+		r.EXPECT().FindByID(gomock.Any(), "5f2c891e9a9e070b1ef2e28c").Return(found, nil)
+		r.EXPECT().FindByID(gomock.Any(), "5f2c891e9a9e070b1ef2e28d").Return(nil, errors.New("mongo: no documents in result"))
+		r.EXPECT().Delete(gomock.Any(), "5f2c891e9a9e070b1ef2e28c", int64(2)).Return(nil)
+		a.EXPECT().Record(gomock.Any(), entity.ActionDelete, "5f2c891e9a9e070b1ef2e28c", found, nil).Return(nil)
+
+		errs, err := NewService(r, s, a).DeleteMany(ctx, []entity.PlanetRef{
+			{ID: "5f2c891e9a9e070b1ef2e28c", Version: 2},
+			{ID: "5f2c891e9a9e070b1ef2e28d", Version: 1},
+			{ID: "5f2c891e9a9e070b1ef2e28c", Version: handler.AnyVersion},
+		}, false)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []error{
+			nil,
+			handler.PreconditionFailed{Message: "planet not found"},
+			handler.BadRequest{Message: "planet is repeated in the batch"},
+		}, errs)
+	})
+
+	t.Run("atomic deletes every planet in a transaction", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+
+		r.EXPECT().FindByID(gomock.Any(), "5f2c891e9a9e070b1ef2e28c").Return(found, nil)
+		r.EXPECT().FindByID(gomock.Any(), "5f2c891e9a9e070b1ef2e28d").Return(nil, errors.New("mongo: no documents in result"))
+		r.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(inTransaction)
+		r.EXPECT().Delete(gomock.Any(), "5f2c891e9a9e070b1ef2e28c", int64(2)).Return(nil)
+		a.EXPECT().Record(gomock.Any(), entity.ActionDelete, "5f2c891e9a9e070b1ef2e28c", found, nil).Return(nil)
+
+		errs, err := NewService(r, s, a).DeleteMany(ctx, []entity.PlanetRef{
+			{ID: "5f2c891e9a9e070b1ef2e28c", Version: handler.AnyVersion},
+			{ID: "5f2c891e9a9e070b1ef2e28d", Version: handler.AnyVersion},
+		}, true)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []error{nil, nil}, errs, "a planet already gone is deleted")
+	})
+
+	t.Run("atomic rolls back when a planet changed meanwhile", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+
+		r.EXPECT().FindByID(gomock.Any(), "5f2c891e9a9e070b1ef2e28c").Return(found, nil)
+		r.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(inTransaction)
+		r.EXPECT().Delete(gomock.Any(), "5f2c891e9a9e070b1ef2e28c", int64(2)).Return(ErrConflict)
+
+		errs, err := NewService(r, s, a).DeleteMany(ctx, []entity.PlanetRef{{ID: "5f2c891e9a9e070b1ef2e28c", Version: 2}}, true)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []error{handler.PreconditionFailed{Message: ErrConflict.Error()}}, errs)
+	})
+
+	t.Run("atomic fails when the commit does", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+
+		r.EXPECT().FindByID(gomock.Any(), "5f2c891e9a9e070b1ef2e28c").Return(found, nil)
+		r.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			assert.NoError(t, fn(ctx))
+			return errors.New("commit failed")
+		})
+		r.EXPECT().Delete(gomock.Any(), "5f2c891e9a9e070b1ef2e28c", int64(2)).Return(nil)
+
+		errs, err := NewService(r, s, a).DeleteMany(ctx, []entity.PlanetRef{{ID: "5f2c891e9a9e070b1ef2e28c", Version: 2}}, true)
+
+		assert.Nil(t, errs)
+		assert.Equal(t, handler.InternalServer{Message: "commit failed"}, err)
+	})
+}
+
+func TestEach(t *testing.T) {
+	var running, peak, done int32
+
+	each(20, func(int) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		atomic.AddInt32(&done, 1)
+	})
+
+	assert.Equal(t, int32(20), done)
+	assert.LessOrEqual(t, peak, int32(batchConcurrency))
+}
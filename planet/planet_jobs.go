@@ -0,0 +1,17 @@
+package planet
+
+import (
+	"context"
+	"star-wars/api/handler"
+	"star-wars/entity"
+)
+
+// CreateJob runs the entity.JobCreatePlanet jobs, saving their planet with s
+func CreateJob(s Service) func(ctx context.Context, j *entity.Job, save func(context.Context) error) error {
+	return func(ctx context.Context, j *entity.Job, _ func(context.Context) error) error {
+		if j.Planet == nil {
+			return handler.BadRequest{Message: "job has no planet"}
+		}
+		return s.Save(ctx, j.Planet)
+	}
+}
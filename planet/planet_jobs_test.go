@@ -0,0 +1,38 @@
+package planet
+
+import (
+	"errors"
+	"star-wars/api/handler"
+	"star-wars/entity"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateJob(t *testing.T) {
+	t.Run("saves the planet of the job", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+
+		r.EXPECT().FindByName(gomock.Any(), "Tatooine").Return(nil, errors.New("mongo: no documents in result"))
+		s.EXPECT().GetPlanet(gomock.Any(), "Tatooine").Return(appearances, nil)
+		r.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+		a.EXPECT().Record(gomock.Any(), entity.ActionCreate, gomock.Any(), nil, gomock.Any()).Return(nil)
+
+		j := &entity.Job{Kind: entity.JobCreatePlanet, Planet: &batchOf("Tatooine")[0]}
+		err := CreateJob(NewService(r, s, a))(ctx, j, nil)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, j.Planet.TotalFilms)
+	})
+
+	t.Run("when the job has no planet", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+
+		err := CreateJob(NewService(r, s, a))(ctx, &entity.Job{Kind: entity.JobCreatePlanet}, nil)
+
+		assert.Equal(t, handler.BadRequest{Message: "job has no planet"}, err)
+	})
+}
@@ -0,0 +1,65 @@
+package planet
+
+import (
+	"context"
+	"star-wars/entity"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+type legacyPlanet struct {
+	ID      interface{}   `bson:"_id"`
+	Climate bson.RawValue `bson:"climate"`
+	Terrain bson.RawValue `bson:"terrain"`
+}
+
+// NormalizeTerms converts comma-joined climate and terrain strings into arrays
+func NormalizeTerms(ctx context.Context) (int64, error) {
+	coll, err := cnx(ctx)
+
+	if err != nil {
+		return 0, err
+	}
+
+	filter := bson.M{"$or": bson.A{
+		bson.M{"climate": bson.M{"$type": "string"}},
+		bson.M{"terrain": bson.M{"$type": "string"}},
+	}}
+
+	cr, err := coll.Find(ctx, filter)
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer cr.Close(ctx)
+
+	var total int64
+
+	for cr.Next(ctx) {
+		var planet legacyPlanet
+
+		if err := cr.Decode(&planet); err != nil {
+			return total, err
+		}
+
+		set := bson.M{}
+
+		if planet.Climate.Type == bsontype.String {
+			set["climate"] = entity.ParseTerms(planet.Climate.StringValue())
+		}
+
+		if planet.Terrain.Type == bsontype.String {
+			set["terrain"] = entity.ParseTerms(planet.Terrain.StringValue())
+		}
+
+		if _, err := coll.UpdateOne(ctx, bson.M{"_id": planet.ID}, bson.M{"$set": set}); err != nil {
+			return total, err
+		}
+
+		total++
+	}
+
+	return total, cr.Err()
+}
@@ -2,15 +2,18 @@ package planet
 
 import (
 	"context"
-	"log"
+	"errors"
+	"star-wars/database"
 	"star-wars/entity"
-	"star-wars/env"
+	"star-wars/logger"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.uber.org/zap"
 )
 
 // Repository contract
@@ -18,24 +21,50 @@ type Repository interface {
 	FindAll(ctx context.Context, limit int64, skip int64) (*[]entity.Planet, error)
 	FindByName(ctx context.Context, name string) (*entity.Planet, error)
 	FindByID(ctx context.Context, id string) (*entity.Planet, error)
+	FindByIDs(ctx context.Context, ids []string) (*[]entity.Planet, error)
 	Save(ctx context.Context, planet *entity.Planet) error
-	Delete(ctx context.Context, id string) error
+	Update(ctx context.Context, planet *entity.Planet) error
+	Delete(ctx context.Context, id string, version int64) error
+	FindDeleted(ctx context.Context, id string) (*entity.Planet, error)
+	Restore(ctx context.Context, planet *entity.Planet) error
+	Purge(ctx context.Context, before time.Time) ([]entity.Planet, error)
+	Distinct(ctx context.Context, field string) ([]string, error)
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
 	Ping(ctx context.Context) string
 }
 
 type repo struct{}
 
+// ErrConflict the planet version changed since it was read
+var ErrConflict = errors.New("planet was modified by another request")
+
+// ErrNoTransactions the server is standalone, transactions need a replica set or a mongos
+var ErrNoTransactions = errors.New("transactions are not supported")
+
+// illegalOperation code of the error a standalone server answers transactions with
+const illegalOperation = 20
+
+// notDeleted filter hides soft-deleted planets
+var notDeleted = bson.M{"deletedAt": nil}
+
+// versionFilter matches documents written before versioning as version 0
+func versionFilter(version int64) interface{} {
+	if version == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+	return version
+}
+
 // NewRepository planet
 func NewRepository() Repository {
 	return &repo{}
 }
 
+// cnx is patched in tests, keep it out of line
+//
+//go:noinline
 func cnx(ctx context.Context) (*mongo.Collection, error) {
-	c, _ := mongo.NewClient(options.Client().ApplyURI(env.Vars.Database.Host))
-	err := c.Connect(ctx)
-	coll := c.Database(env.Vars.Database.Name).Collection("planets")
-
-	return coll, err
+	return database.Collection(ctx, "planets")
 }
 
 func (r repo) FindAll(ctx context.Context, limit int64, skip int64) (*[]entity.Planet, error) {
@@ -45,16 +74,14 @@ func (r repo) FindAll(ctx context.Context, limit int64, skip int64) (*[]entity.P
 		return nil, err
 	}
 
-	defer coll.Database().Client().Disconnect(ctx)
-
 	opt := options.Find()
 	opt.SetLimit(limit)
 	opt.SetSkip(skip)
 
-	cr, err := coll.Find(ctx, bson.D{}, opt)
+	cr, err := coll.Find(ctx, notDeleted, opt)
 
 	if err != nil {
-		log.Print(err)
+		logger.FromContext(ctx).Error("find planets", zap.Error(err))
 		return nil, err
 	}
 
@@ -76,13 +103,11 @@ func (r repo) FindByName(ctx context.Context, name string) (*entity.Planet, erro
 		return nil, err
 	}
 
-	defer coll.Database().Client().Disconnect(ctx)
-
 	var planet entity.Planet
 
 	err = coll.FindOne(
 		ctx,
-		bson.M{"name": name},
+		bson.M{"name": name, "deletedAt": nil},
 	).Decode(&planet)
 
 	if err != nil {
@@ -105,13 +130,11 @@ func (r repo) FindByID(ctx context.Context, id string) (*entity.Planet, error) {
 		return nil, err
 	}
 
-	defer coll.Database().Client().Disconnect(ctx)
-
 	var planet entity.Planet
 
 	err = coll.FindOne(
 		ctx,
-		bson.M{"_id": _id},
+		bson.M{"_id": _id, "deletedAt": nil},
 	).Decode(&planet)
 
 	if err != nil {
@@ -121,6 +144,40 @@ func (r repo) FindByID(ctx context.Context, id string) (*entity.Planet, error) {
 	return &planet, nil
 }
 
+// FindByIDs ignores ids that are invalid or not found
+func (r repo) FindByIDs(ctx context.Context, ids []string) (*[]entity.Planet, error) {
+	oids := bson.A{}
+
+	for _, id := range ids {
+		if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+			oids = append(oids, oid)
+		}
+	}
+
+	coll, err := cnx(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cr, err := coll.Find(ctx, bson.M{"_id": bson.M{"$in": oids}, "deletedAt": nil})
+
+	if err != nil {
+		logger.FromContext(ctx).Error("find planets by ids", zap.Error(err))
+		return nil, err
+	}
+
+	planets := &[]entity.Planet{}
+
+	err = cr.All(ctx, planets)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return planets, nil
+}
+
 func (r repo) Save(ctx context.Context, planet *entity.Planet) error {
 	coll, err := cnx(ctx)
 
@@ -128,7 +185,10 @@ func (r repo) Save(ctx context.Context, planet *entity.Planet) error {
 		return err
 	}
 
-	defer coll.Database().Client().Disconnect(ctx)
+	now := time.Now().UTC()
+	planet.CreatedAt = &now
+	planet.UpdatedAt = &now
+	planet.Version = 1
 
 	result, err := coll.InsertOne(ctx, &planet)
 
@@ -142,14 +202,56 @@ func (r repo) Save(ctx context.Context, planet *entity.Planet) error {
 	return nil
 }
 
-func (r repo) Delete(ctx context.Context, id string) error {
+func (r repo) Update(ctx context.Context, planet *entity.Planet) error {
+	_id, err := primitive.ObjectIDFromHex(planet.ID)
+
+	if err != nil {
+		return err
+	}
+
 	coll, err := cnx(ctx)
 
 	if err != nil {
 		return err
 	}
 
-	defer coll.Database().Client().Disconnect(ctx)
+	now := time.Now().UTC()
+
+	result, err := coll.UpdateOne(
+		ctx,
+		bson.M{"_id": _id, "deletedAt": nil, "version": versionFilter(planet.Version)},
+		bson.M{
+			"$set": bson.M{
+				"name":       planet.Name,
+				"climate":    planet.Climate,
+				"terrain":    planet.Terrain,
+				"totalFilms": planet.TotalFilms,
+				"updatedAt":  now,
+			},
+			"$inc": bson.M{"version": 1},
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrConflict
+	}
+
+	planet.UpdatedAt = &now
+	planet.Version++
+
+	return nil
+}
+
+func (r repo) Delete(ctx context.Context, id string, version int64) error {
+	coll, err := cnx(ctx)
+
+	if err != nil {
+		return err
+	}
 
 	_id, err := primitive.ObjectIDFromHex(id)
 
@@ -157,15 +259,189 @@ func (r repo) Delete(ctx context.Context, id string) error {
 		return err
 	}
 
-	_, err = coll.DeleteOne(ctx, bson.M{"_id": _id})
+	now := time.Now().UTC()
+
+	result, err := coll.UpdateOne(
+		ctx,
+		bson.M{"_id": _id, "deletedAt": nil, "version": versionFilter(version)},
+		bson.M{
+			"$set": bson.M{"deletedAt": now, "updatedAt": now},
+			"$inc": bson.M{"version": 1},
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrConflict
+	}
+
+	return nil
+}
+
+func (r repo) FindDeleted(ctx context.Context, id string) (*entity.Planet, error) {
+	_id, err := primitive.ObjectIDFromHex(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	coll, err := cnx(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var planet entity.Planet
+
+	err = coll.FindOne(
+		ctx,
+		bson.M{"_id": _id, "deletedAt": bson.M{"$ne": nil}},
+	).Decode(&planet)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &planet, nil
+}
+
+func (r repo) Restore(ctx context.Context, planet *entity.Planet) error {
+	coll, err := cnx(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	_id, err := primitive.ObjectIDFromHex(planet.ID)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+
+	result, err := coll.UpdateOne(
+		ctx,
+		bson.M{"_id": _id, "deletedAt": bson.M{"$ne": nil}, "version": versionFilter(planet.Version)},
+		bson.M{
+			"$set":   bson.M{"updatedAt": now},
+			"$unset": bson.M{"deletedAt": ""},
+			"$inc":   bson.M{"version": 1},
+		},
+	)
 
 	if err != nil {
 		return err
 	}
 
+	if result.MatchedCount == 0 {
+		return ErrConflict
+	}
+
+	planet.DeletedAt = nil
+	planet.UpdatedAt = &now
+	planet.Version++
+
 	return nil
 }
 
+// Purge deletes for good the planets removed before the cutoff, returning them;
+// a planet restored meanwhile is left alone
+func (r repo) Purge(ctx context.Context, before time.Time) ([]entity.Planet, error) {
+	coll, err := cnx(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	expired := bson.M{"$lt": before}
+
+	cr, err := coll.Find(ctx, bson.M{"deletedAt": expired})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var found []entity.Planet
+
+	if err := cr.All(ctx, &found); err != nil {
+		return nil, err
+	}
+
+	purged := []entity.Planet{}
+
+	for _, planet := range found {
+		_id, err := primitive.ObjectIDFromHex(planet.ID)
+
+		if err != nil {
+			return purged, err
+		}
+
+		result, err := coll.DeleteOne(ctx, bson.M{"_id": _id, "deletedAt": expired})
+
+		if err != nil {
+			return purged, err
+		}
+
+		if result.DeletedCount > 0 {
+			purged = append(purged, planet)
+		}
+	}
+
+	return purged, nil
+}
+
+func (r repo) Distinct(ctx context.Context, field string) ([]string, error) {
+	coll, err := cnx(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := coll.Distinct(ctx, field, notDeleted)
+
+	if err != nil {
+		return nil, err
+	}
+
+	values := []string{}
+
+	for _, v := range result {
+		if value, ok := v.(string); ok {
+			values = append(values, value)
+		}
+	}
+
+	return values, nil
+}
+
+// Transaction runs fn in a transaction, committed when fn returns nil; fn may
+// run again when the transaction is retried, and must write through its ctx
+func (r repo) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	coll, err := cnx(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	err = coll.Database().Client().UseSession(ctx, func(sc mongo.SessionContext) error {
+		_, err := sc.WithTransaction(sc, func(sc mongo.SessionContext) (interface{}, error) {
+			return nil, fn(sc)
+		})
+		return err
+	})
+
+	var cmd mongo.CommandError
+	if errors.As(err, &cmd) && cmd.Code == illegalOperation {
+		return ErrNoTransactions
+	}
+
+	return err
+}
+
 func (r repo) Ping(ctx context.Context) string {
 	coll, err := cnx(ctx)
 
@@ -173,8 +449,6 @@ func (r repo) Ping(ctx context.Context) string {
 		return "error"
 	}
 
-	defer coll.Database().Client().Disconnect(ctx)
-
 	err = coll.Database().Client().Ping(ctx, readpref.Primary())
 	if err != nil {
 		return "error"
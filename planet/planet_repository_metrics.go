@@ -0,0 +1,116 @@
+package planet
+
+import (
+	"context"
+	"star-wars/entity"
+	"star-wars/metrics"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type instrumented struct {
+	next Repository
+}
+
+// NewInstrumentedRepository records latency and errors of every operation of r
+func NewInstrumentedRepository(r Repository) Repository {
+	return &instrumented{
+		next: r,
+	}
+}
+
+func observe(operation string, start time.Time, err error) {
+	metrics.Repository("planets", operation, start, err != nil && err != mongo.ErrNoDocuments)
+}
+
+func (i instrumented) FindAll(ctx context.Context, limit int64, skip int64) (*[]entity.Planet, error) {
+	start := time.Now()
+	planets, err := i.next.FindAll(ctx, limit, skip)
+	observe("find_all", start, err)
+	return planets, err
+}
+
+func (i instrumented) FindByName(ctx context.Context, name string) (*entity.Planet, error) {
+	start := time.Now()
+	planet, err := i.next.FindByName(ctx, name)
+	observe("find_by_name", start, err)
+	return planet, err
+}
+
+func (i instrumented) FindByID(ctx context.Context, id string) (*entity.Planet, error) {
+	start := time.Now()
+	planet, err := i.next.FindByID(ctx, id)
+	observe("find_by_id", start, err)
+	return planet, err
+}
+
+func (i instrumented) FindByIDs(ctx context.Context, ids []string) (*[]entity.Planet, error) {
+	start := time.Now()
+	planets, err := i.next.FindByIDs(ctx, ids)
+	observe("find_by_ids", start, err)
+	return planets, err
+}
+
+func (i instrumented) Save(ctx context.Context, planet *entity.Planet) error {
+	start := time.Now()
+	err := i.next.Save(ctx, planet)
+	observe("save", start, err)
+	return err
+}
+
+func (i instrumented) Update(ctx context.Context, planet *entity.Planet) error {
+	start := time.Now()
+	err := i.next.Update(ctx, planet)
+	observe("update", start, err)
+	return err
+}
+
+func (i instrumented) Delete(ctx context.Context, id string, version int64) error {
+	start := time.Now()
+	err := i.next.Delete(ctx, id, version)
+	observe("delete", start, err)
+	return err
+}
+
+func (i instrumented) FindDeleted(ctx context.Context, id string) (*entity.Planet, error) {
+	start := time.Now()
+	planet, err := i.next.FindDeleted(ctx, id)
+	observe("find_deleted", start, err)
+	return planet, err
+}
+
+func (i instrumented) Restore(ctx context.Context, planet *entity.Planet) error {
+	start := time.Now()
+	err := i.next.Restore(ctx, planet)
+	observe("restore", start, err)
+	return err
+}
+
+func (i instrumented) Purge(ctx context.Context, before time.Time) ([]entity.Planet, error) {
+	start := time.Now()
+	purged, err := i.next.Purge(ctx, before)
+	observe("purge", start, err)
+	return purged, err
+}
+
+func (i instrumented) Distinct(ctx context.Context, field string) ([]string, error) {
+	start := time.Now()
+	values, err := i.next.Distinct(ctx, field)
+	observe("distinct", start, err)
+	return values, err
+}
+
+func (i instrumented) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	start := time.Now()
+	err := i.next.Transaction(ctx, fn)
+	observe("transaction", start, err)
+	return err
+}
+
+func (i instrumented) Ping(ctx context.Context) string {
+	start := time.Now()
+	status := i.next.Ping(ctx)
+	metrics.Repository("planets", "ping", start, status != "ok")
+	return status
+}
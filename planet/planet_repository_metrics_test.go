@@ -0,0 +1,45 @@
+package planet
+
+import (
+	"errors"
+	"star-wars/entity"
+	"star-wars/metrics"
+	"star-wars/planet/mock_planet"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestInstrumentedRepository(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	r := mock_planet.NewMockRepository(c)
+
+	planet := &entity.Planet{ID: "5f2c88567563c4bae600d7e0"}
+	r.EXPECT().FindByID(ctx, "5f2c88567563c4bae600d7e0").Return(planet, nil)
+	r.EXPECT().FindByID(ctx, "5f2c88567563c4bae600d7e1").Return(nil, mongo.ErrNoDocuments)
+	r.EXPECT().Save(ctx, planet).Return(errors.New("insert error"))
+
+	repo := NewInstrumentedRepository(r)
+
+	found, err := repo.FindByID(ctx, "5f2c88567563c4bae600d7e0")
+	assert.Nil(t, err)
+	assert.Equal(t, planet, found)
+
+	_, err = repo.FindByID(ctx, "5f2c88567563c4bae600d7e1")
+	assert.Equal(t, mongo.ErrNoDocuments, err)
+
+	err = repo.Save(ctx, planet)
+	assert.Equal(t, "insert error", err.Error())
+
+	expected := `
+# HELP repository_errors_total Repository operations that failed, not found excluded.
+# TYPE repository_errors_total counter
+repository_errors_total{operation="save",repository="planets"} 1
+`
+	assert.Nil(t, testutil.GatherAndCompare(metrics.Registry, strings.NewReader(expected), "repository_errors_total"))
+}
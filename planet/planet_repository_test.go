@@ -6,9 +6,11 @@ import (
 	"star-wars/entity"
 	"star-wars/planet/monkey_patch/mongo_db"
 	"testing"
+	"time"
 
 	"bou.ke/monkey"
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -209,6 +211,72 @@ func TestFindByID_Repository(t *testing.T) {
 	})
 }
 
+func TestFindByIDs_Repository(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, false)
+
+		var guardFind monkey.PatchGuard
+		mongo_db.Find(&guardFind, false)
+
+		var guardAll monkey.PatchGuard
+		mongo_db.All(&guardAll, false)
+
+		defer cancel()
+
+		repo := NewRepository()
+		_, err := repo.FindByIDs(ctx, []string{"5f2c88567563c4bae600d7e0", "invalid"})
+
+		assert.Equal(t, nil, err)
+	})
+
+	t.Run("when connection error", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, true)
+
+		defer cancel()
+
+		repo := NewRepository()
+		_, err := repo.FindByIDs(ctx, []string{"5f2c88567563c4bae600d7e0"})
+
+		assert.Equal(t, "connection error", err.Error())
+	})
+
+	t.Run("when nothing expired", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, false)
+
+		var guardFind monkey.PatchGuard
+		mongo_db.Find(&guardFind, false)
+
+		var guardAll monkey.PatchGuard
+		mongo_db.All(&guardAll, false)
+
+		defer cancel()
+
+		repo := NewRepository()
+		purged, err := repo.Purge(ctx, time.Now())
+
+		assert.Nil(t, err)
+		assert.Empty(t, purged)
+	})
+
+	t.Run("when find returns error", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, false)
+
+		var guardFind monkey.PatchGuard
+		mongo_db.Find(&guardFind, true)
+
+		defer cancel()
+
+		repo := NewRepository()
+		_, err := repo.FindByIDs(ctx, []string{"5f2c88567563c4bae600d7e0"})
+
+		assert.Equal(t, "find error", err.Error())
+	})
+}
+
 func TestSave_Repository(t *testing.T) {
 	t.Run("happy path", func(t *testing.T) {
 		var guardCnx monkey.PatchGuard
@@ -220,8 +288,8 @@ func TestSave_Repository(t *testing.T) {
 		planet := entity.Planet{
 			ID:         "",
 			Name:       "Bespin",
-			Climate:    "temperate",
-			Terrain:    "gas giant",
+			Climate:    entity.Terms{"temperate"},
+			Terrain:    entity.Terms{"gas giant"},
 			TotalFilms: 1,
 		}
 
@@ -274,7 +342,7 @@ func TestDelete_Repository(t *testing.T) {
 		defer cancel()
 
 		repo := NewRepository()
-		err := repo.Delete(ctx, "5f3080961f4799f091e3c515")
+		err := repo.Delete(ctx, "5f3080961f4799f091e3c515", 1)
 
 		assert.Equal(t, "connection error", err.Error())
 	})
@@ -289,7 +357,7 @@ func TestDelete_Repository(t *testing.T) {
 		defer cancel()
 
 		repo := NewRepository()
-		err := repo.Delete(ctx, "")
+		err := repo.Delete(ctx, "", 1)
 
 		assert.Equal(t, "the provided hex string is not a valid ObjectID", err.Error())
 	})
@@ -301,7 +369,7 @@ func TestDelete_Repository(t *testing.T) {
 		defer cancel()
 
 		repo := NewRepository()
-		err := repo.Delete(ctx, "5f3080961f4799f091e3c515")
+		err := repo.Delete(ctx, "5f3080961f4799f091e3c515", 1)
 
 		assert.Equal(t, "the Database field must be set on Operation", err.Error())
 	})
@@ -350,3 +418,322 @@ func TestPing_Repository(t *testing.T) {
 		assert.Equal(t, "error", status)
 	})
 }
+
+func TestTransaction_Repository(t *testing.T) {
+	type test struct {
+		name    string
+		connErr bool
+		err     error
+		want    error
+	}
+
+	failed := errors.New("write conflict")
+
+	tests := []test{
+		{name: "when connection error", connErr: true, want: errors.New("connection error")},
+		{name: "when committed"},
+		{name: "when the transaction fails", err: failed, want: failed},
+		{name: "when the server is standalone", err: mongo.CommandError{Code: 20, Name: "IllegalOperation"}, want: ErrNoTransactions},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var guardCnx monkey.PatchGuard
+			monkeyCnx(&guardCnx, tt.connErr)
+
+			var guardSession monkey.PatchGuard
+			mongo_db.UseSessionWithOptions(&guardSession, tt.err)
+
+			defer monkey.UnpatchAll()
+
+			err := NewRepository().Transaction(context.Background(), func(ctx context.Context) error { return nil })
+
+			assert.Equal(t, tt.want, err)
+		})
+	}
+}
+
+func TestDistinct_Repository(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, false)
+
+		var guardDistinct monkey.PatchGuard
+		mongo_db.Distinct(&guardDistinct, []interface{}{"desert", "ocean"}, false)
+
+		defer cancel()
+
+		repo := NewRepository()
+		values, err := repo.Distinct(ctx, "terrain")
+
+		assert.Equal(t, nil, err)
+		assert.Equal(t, []string{"desert", "ocean"}, values)
+	})
+
+	t.Run("when distinct returns error", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, false)
+
+		var guardDistinct monkey.PatchGuard
+		mongo_db.Distinct(&guardDistinct, nil, true)
+
+		defer cancel()
+
+		repo := NewRepository()
+		_, err := repo.Distinct(ctx, "terrain")
+
+		assert.Equal(t, "distinct error", err.Error())
+	})
+
+	t.Run("when connection error", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, true)
+
+		defer cancel()
+
+		repo := NewRepository()
+		_, err := repo.Distinct(ctx, "terrain")
+
+		assert.Equal(t, "connection error", err.Error())
+	})
+}
+
+func TestRestore_Repository(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, false)
+
+		var guardObjectIDFromHex monkey.PatchGuard
+		mongo_db.ObjectIDFromHex(&guardObjectIDFromHex, false)
+
+		var guardUpdateOne monkey.PatchGuard
+		mongo_db.UpdateOne(&guardUpdateOne, 1, false)
+
+		defer cancel()
+
+		repo := NewRepository()
+		err := repo.Restore(ctx, &entity.Planet{ID: "5f3080961f4799f091e3c515", Version: 2})
+
+		assert.Equal(t, nil, err)
+	})
+
+	t.Run("when planet is not deleted", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, false)
+
+		var guardObjectIDFromHex monkey.PatchGuard
+		mongo_db.ObjectIDFromHex(&guardObjectIDFromHex, false)
+
+		var guardUpdateOne monkey.PatchGuard
+		mongo_db.UpdateOne(&guardUpdateOne, 0, false)
+
+		defer cancel()
+
+		repo := NewRepository()
+		err := repo.Restore(ctx, &entity.Planet{ID: "5f3080961f4799f091e3c515", Version: 2})
+
+		assert.Equal(t, ErrConflict, err)
+	})
+
+	t.Run("when update one returns error", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, false)
+
+		var guardObjectIDFromHex monkey.PatchGuard
+		mongo_db.ObjectIDFromHex(&guardObjectIDFromHex, false)
+
+		var guardUpdateOne monkey.PatchGuard
+		mongo_db.UpdateOne(&guardUpdateOne, 0, true)
+
+		defer cancel()
+
+		repo := NewRepository()
+		err := repo.Restore(ctx, &entity.Planet{ID: "5f3080961f4799f091e3c515", Version: 2})
+
+		assert.Equal(t, "update one error", err.Error())
+	})
+
+	t.Run("when connection error", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, true)
+
+		defer cancel()
+
+		repo := NewRepository()
+		err := repo.Restore(ctx, &entity.Planet{ID: "5f3080961f4799f091e3c515", Version: 2})
+
+		assert.Equal(t, "connection error", err.Error())
+	})
+}
+
+func TestFindDeleted_Repository(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		var guardObjectIDFromHex monkey.PatchGuard
+		mongo_db.ObjectIDFromHex(&guardObjectIDFromHex, false)
+
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, false)
+
+		var guardFindOne monkey.PatchGuard
+		mongo_db.FindOne(&guardFindOne)
+
+		var guardDecode monkey.PatchGuard
+		mongo_db.Decode(&guardDecode, false)
+
+		defer cancel()
+
+		repo := NewRepository()
+		_, err := repo.FindDeleted(ctx, "5f3080961f4799f091e3c515")
+
+		assert.Equal(t, nil, err)
+	})
+
+	t.Run("when decode returns error", func(t *testing.T) {
+		var guardObjectIDFromHex monkey.PatchGuard
+		mongo_db.ObjectIDFromHex(&guardObjectIDFromHex, false)
+
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, false)
+
+		var guardFindOne monkey.PatchGuard
+		mongo_db.FindOne(&guardFindOne)
+
+		var guardDecode monkey.PatchGuard
+		mongo_db.Decode(&guardDecode, true)
+
+		defer cancel()
+
+		repo := NewRepository()
+		_, err := repo.FindDeleted(ctx, "5f3080961f4799f091e3c515")
+
+		assert.Equal(t, "Registry cannot be nil", err.Error())
+	})
+}
+
+func TestPurge_Repository(t *testing.T) {
+	t.Run("when connection error", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, true)
+
+		defer cancel()
+
+		repo := NewRepository()
+		_, err := repo.Purge(ctx, time.Now())
+
+		assert.Equal(t, "connection error", err.Error())
+	})
+
+	t.Run("when nothing expired", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, false)
+
+		var guardFind monkey.PatchGuard
+		mongo_db.Find(&guardFind, false)
+
+		var guardAll monkey.PatchGuard
+		mongo_db.All(&guardAll, false)
+
+		defer cancel()
+
+		repo := NewRepository()
+		purged, err := repo.Purge(ctx, time.Now())
+
+		assert.Nil(t, err)
+		assert.Empty(t, purged)
+	})
+
+	t.Run("when find returns error", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, false)
+
+		var guardFind monkey.PatchGuard
+		mongo_db.Find(&guardFind, true)
+
+		defer cancel()
+
+		repo := NewRepository()
+		_, err := repo.Purge(ctx, time.Now())
+
+		assert.Equal(t, "find error", err.Error())
+	})
+}
+
+func TestUpdate_Repository(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, false)
+
+		var guardObjectIDFromHex monkey.PatchGuard
+		mongo_db.ObjectIDFromHex(&guardObjectIDFromHex, false)
+
+		var guardUpdateOne monkey.PatchGuard
+		mongo_db.UpdateOne(&guardUpdateOne, 1, false)
+
+		defer cancel()
+
+		planet := &entity.Planet{ID: "5f3080961f4799f091e3c515", Version: 2}
+
+		repo := NewRepository()
+		err := repo.Update(ctx, planet)
+
+		assert.Equal(t, nil, err)
+		assert.Equal(t, int64(3), planet.Version)
+		assert.NotNil(t, planet.UpdatedAt)
+	})
+
+	t.Run("when planet version changed", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, false)
+
+		var guardObjectIDFromHex monkey.PatchGuard
+		mongo_db.ObjectIDFromHex(&guardObjectIDFromHex, false)
+
+		var guardUpdateOne monkey.PatchGuard
+		mongo_db.UpdateOne(&guardUpdateOne, 0, false)
+
+		defer cancel()
+
+		planet := &entity.Planet{ID: "5f3080961f4799f091e3c515", Version: 2}
+
+		repo := NewRepository()
+		err := repo.Update(ctx, planet)
+
+		assert.Equal(t, ErrConflict, err)
+		assert.Equal(t, int64(2), planet.Version)
+	})
+
+	t.Run("when update one returns error", func(t *testing.T) {
+		var guardCnx monkey.PatchGuard
+		monkeyCnx(&guardCnx, false)
+
+		var guardObjectIDFromHex monkey.PatchGuard
+		mongo_db.ObjectIDFromHex(&guardObjectIDFromHex, false)
+
+		var guardUpdateOne monkey.PatchGuard
+		mongo_db.UpdateOne(&guardUpdateOne, 0, true)
+
+		defer cancel()
+
+		repo := NewRepository()
+		err := repo.Update(ctx, &entity.Planet{ID: "5f3080961f4799f091e3c515"})
+
+		assert.Equal(t, "update one error", err.Error())
+	})
+
+	t.Run("when an error occurs when converting from string to ObjectID", func(t *testing.T) {
+		var guardObjectIDFromHex monkey.PatchGuard
+		mongo_db.ObjectIDFromHex(&guardObjectIDFromHex, true)
+
+		defer cancel()
+
+		repo := NewRepository()
+		err := repo.Update(ctx, &entity.Planet{ID: "Bespin"})
+
+		assert.Equal(t, "the provided hex string is not a valid ObjectID", err.Error())
+	})
+}
+
+func TestVersionFilter(t *testing.T) {
+	assert.Equal(t, int64(3), versionFilter(3))
+	assert.Equal(t, bson.M{"$in": bson.A{0, nil}}, versionFilter(0))
+}
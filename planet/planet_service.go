@@ -2,34 +2,79 @@ package planet
 
 import (
 	"context"
+	"sort"
 	"star-wars/api/handler"
+	"star-wars/audit"
 	"star-wars/entity"
+	"star-wars/logger"
 	"star-wars/swapi"
+	"time"
+
+	"go.uber.org/zap"
 )
 
 // Service contract
 type Service interface {
 	Exists(ctx context.Context, name string) (bool, error)
 	Save(ctx context.Context, planet *entity.Planet) error
+	Update(ctx context.Context, planet *entity.Planet, version int64) error
 	FindAll(ctx context.Context, limit int64, skip int64) (*[]entity.Planet, error)
 	FindByName(ctx context.Context, name string) (*entity.Planet, error)
 	FindByID(ctx context.Context, id string) (*entity.Planet, error)
-	Delete(ctx context.Context, id string) error
+	FindByIDs(ctx context.Context, ids []string) (*[]entity.Planet, error)
+	Delete(ctx context.Context, id string, version int64) error
+	Restore(ctx context.Context, id string, version int64) (*entity.Planet, error)
+	Purge(ctx context.Context, retention time.Duration) (int64, error)
+	Distinct(ctx context.Context, field string) ([]string, error)
+	SaveMany(ctx context.Context, planets []entity.Planet, atomic bool) ([]error, error)
+	DeleteMany(ctx context.Context, refs []entity.PlanetRef, atomic bool) ([]error, error)
 }
 
 type srv struct {
 	repo  Repository
 	swapi swapi.Service
+	audit audit.Service
 }
 
 // NewService returns a planet service instance
-func NewService(r Repository, s swapi.Service) Service {
+func NewService(r Repository, s swapi.Service, a audit.Service) Service {
 	return &srv{
 		repo:  r,
 		swapi: s,
+		audit: a,
 	}
 }
 
+// record appends to the audit log; a failure there must not undo the mutation
+func (s srv) record(ctx context.Context, action string, id string, before *entity.Planet, after *entity.Planet) {
+	if err := s.audit.Record(ctx, action, id, before, after); err != nil {
+		logger.FromContext(ctx).Warn("audit record failed",
+			zap.String("action", action),
+			zap.String("planetId", id),
+			zap.Error(err),
+		)
+	}
+}
+
+// checkVersion compares the version expected by the client with the stored one
+func checkVersion(planet *entity.Planet, version int64) error {
+	if version != handler.AnyVersion && version != planet.Version {
+		return handler.PreconditionFailed{Message: ErrConflict.Error()}
+	}
+	return nil
+}
+
+// writeError maps repository write errors
+func writeError(err error) error {
+	if err == ErrConflict {
+		return handler.PreconditionFailed{Message: err.Error()}
+	}
+	if err.Error() == "the provided hex string is not a valid ObjectID" {
+		return handler.BadRequest{Message: "id is invalid"}
+	}
+	return handler.InternalServer{Message: err.Error()}
+}
+
 // Exists search planet in database
 func (s srv) Exists(ctx context.Context, name string) (bool, error) {
 	planet := entity.Planet{Name: name}
@@ -93,19 +138,135 @@ func (s srv) FindByID(ctx context.Context, id string) (*entity.Planet, error) {
 	return planet, nil
 }
 
+// FindByIDs get the planets found among ids, in no particular order
+func (s srv) FindByIDs(ctx context.Context, ids []string) (*[]entity.Planet, error) {
+	planets, err := s.repo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, handler.InternalServer{Message: err.Error()}
+	}
+	return planets, nil
+}
+
 // Delete planet
-func (s srv) Delete(ctx context.Context, id string) error {
+func (s srv) Delete(ctx context.Context, id string, version int64) error {
+	before, err := s.deletable(ctx, id, version)
+
+	if err != nil || before == nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id, before.Version); err != nil {
+		return writeError(err)
+	}
+
+	s.record(ctx, entity.ActionDelete, id, before, nil)
+	return nil
+}
+
+// deletable finds the planet Delete removes, nil when it is already gone
+func (s srv) deletable(ctx context.Context, id string, version int64) (*entity.Planet, error) {
 	if id == "" {
-		return handler.BadRequest{Message: "id is invalid"}
+		return nil, handler.BadRequest{Message: "id is invalid"}
 	}
 
-	if err := s.repo.Delete(ctx, id); err != nil {
-		if err.Error() == "the provided hex string is not a valid ObjectID" {
-			return handler.BadRequest{Message: "id is invalid"}
+	before, err := s.repo.FindByID(ctx, id)
+
+	if err != nil {
+		switch err.Error() {
+		case "the provided hex string is not a valid ObjectID":
+			return nil, handler.BadRequest{Message: "id is invalid"}
+		case "mongo: no documents in result":
+			if version != handler.AnyVersion {
+				return nil, handler.PreconditionFailed{Message: "planet not found"}
+			}
+			return nil, nil
 		}
-		return handler.InternalServer{Message: err.Error()}
+		return nil, handler.InternalServer{Message: err.Error()}
 	}
-	return nil
+
+	if err := checkVersion(before, version); err != nil {
+		return nil, err
+	}
+
+	return before, nil
+}
+
+// Restore undoes a soft delete
+func (s srv) Restore(ctx context.Context, id string, version int64) (*entity.Planet, error) {
+	if id == "" {
+		return nil, handler.BadRequest{Message: "id is invalid"}
+	}
+
+	planet, err := s.repo.FindDeleted(ctx, id)
+
+	if err != nil {
+		switch err.Error() {
+		case "the provided hex string is not a valid ObjectID":
+			return nil, handler.BadRequest{Message: "id is invalid"}
+		case "mongo: no documents in result":
+			return nil, handler.NotFound{Message: "planet not found"}
+		}
+		return nil, handler.InternalServer{Message: err.Error()}
+	}
+
+	if err := checkVersion(planet, version); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.Exists(ctx, planet.Name)
+
+	if err != nil && err.Error() != "mongo: no documents in result" {
+		return nil, handler.InternalServer{Message: err.Error()}
+	}
+
+	if exists {
+		return nil, handler.BadRequest{Message: "planet already registered"}
+	}
+
+	before := *planet
+
+	if err := s.repo.Restore(ctx, planet); err != nil {
+		return nil, writeError(err)
+	}
+
+	s.record(ctx, entity.ActionRestore, id, &before, planet)
+
+	return planet, nil
+}
+
+// Purge permanently removes planets deleted before the retention window
+func (s srv) Purge(ctx context.Context, retention time.Duration) (int64, error) {
+	if retention < 0 {
+		return 0, handler.BadRequest{Message: "retention is invalid"}
+	}
+
+	purged, err := s.repo.Purge(ctx, time.Now().UTC().Add(-retention))
+
+	// the planets purged before a failure are gone all the same
+	for i := range purged {
+		s.record(ctx, entity.ActionPurge, purged[i].ID, &purged[i], nil)
+	}
+
+	if err != nil {
+		return 0, handler.InternalServer{Message: err.Error()}
+	}
+
+	return int64(len(purged)), nil
+}
+
+// Distinct lists the climate or terrain values in use
+func (s srv) Distinct(ctx context.Context, field string) ([]string, error) {
+	if field != "climate" && field != "terrain" {
+		return nil, handler.BadRequest{Message: "field is invalid"}
+	}
+
+	values, err := s.repo.Distinct(ctx, field)
+	if err != nil {
+		return nil, handler.InternalServer{Message: err.Error()}
+	}
+
+	sort.Strings(values)
+	return values, nil
 }
 
 // FindAll get planets
@@ -119,6 +280,29 @@ func (s srv) FindAll(ctx context.Context, limit int64, skip int64) (*[]entity.Pl
 
 // Save planet
 func (s srv) Save(ctx context.Context, planet *entity.Planet) error {
+	if err := s.prepare(ctx, planet); err != nil {
+		return err
+	}
+
+	err := s.repo.Save(ctx, planet)
+
+	if err != nil {
+		return err
+	}
+
+	s.record(ctx, entity.ActionCreate, planet.ID, nil, planet)
+	return nil
+}
+
+// prepare validates a new planet and counts its films, ahead of saving it
+func (s srv) prepare(ctx context.Context, planet *entity.Planet) error {
+	// a new planet is never removed, whatever the body said
+	planet.DeletedAt = nil
+
+	if err := planet.Validate(); err != nil {
+		return handler.BadRequest{Message: err.Error()}
+	}
+
 	name := planet.Name
 	exists, err := s.Exists(ctx, name)
 
@@ -131,28 +315,81 @@ func (s srv) Save(ctx context.Context, planet *entity.Planet) error {
 		return err
 	}
 
-	adapter, err := s.swapi.GetPlanet(planet.Name)
+	return s.countFilms(ctx, planet)
+}
 
-	if err != nil {
-		return handler.InternalServer{Message: err.Error()}
+// Update planet, version is the one expected by the client or handler.AnyVersion
+func (s srv) Update(ctx context.Context, planet *entity.Planet, version int64) error {
+	if planet.ID == "" {
+		return handler.BadRequest{Message: "id is invalid"}
 	}
 
-	if adapter.Count == 0 {
-		return handler.BadRequest{Message: "non-existent planet"}
+	if err := planet.Validate(); err != nil {
+		return handler.BadRequest{Message: err.Error()}
 	}
 
-	total, err := planet.TotalAppearances(adapter.Results)
+	current, err := s.repo.FindByID(ctx, planet.ID)
+
 	if err != nil {
+		switch err.Error() {
+		case "the provided hex string is not a valid ObjectID":
+			return handler.BadRequest{Message: "id is invalid"}
+		case "mongo: no documents in result":
+			return handler.NotFound{Message: "planet not found"}
+		}
+		return handler.InternalServer{Message: err.Error()}
+	}
+
+	if err := checkVersion(current, version); err != nil {
 		return err
 	}
 
-	planet.TotalFilms = total
+	planet.TotalFilms = current.TotalFilms
+
+	if planet.Name != current.Name {
+		exists, err := s.Exists(ctx, planet.Name)
+
+		if err != nil && err.Error() != "mongo: no documents in result" {
+			return err
+		}
+
+		if exists {
+			return handler.BadRequest{Message: "planet already registered"}
+		}
+
+		if err := s.countFilms(ctx, planet); err != nil {
+			return err
+		}
+	}
+
+	planet.CreatedAt = current.CreatedAt
+	planet.Version = current.Version
 
-	err = s.repo.Save(ctx, planet)
+	if err := s.repo.Update(ctx, planet); err != nil {
+		return writeError(err)
+	}
+
+	s.record(ctx, entity.ActionUpdate, planet.ID, current, planet)
+	return nil
+}
+
+// countFilms fills the film appearances from SWAPI
+func (s srv) countFilms(ctx context.Context, planet *entity.Planet) error {
+	adapter, err := s.swapi.GetPlanet(ctx, planet.Name)
 
+	if err != nil {
+		return handler.InternalServer{Message: err.Error()}
+	}
+
+	if adapter.Count == 0 {
+		return handler.BadRequest{Message: "non-existent planet"}
+	}
+
+	total, err := planet.TotalAppearances(adapter.Results)
 	if err != nil {
 		return err
 	}
 
+	planet.TotalFilms = total
 	return nil
 }
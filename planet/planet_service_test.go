@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"star-wars/api/handler"
+	"star-wars/audit/mock_audit"
 	"star-wars/entity"
 	"star-wars/planet/mock_planet"
 	"star-wars/swapi/adapter"
@@ -19,28 +20,29 @@ var (
 	ctx, cancel = context.WithTimeout(context.Background(), 2*time.Second)
 )
 
-func configDep(t *testing.T) (*gomock.Controller, *mock_planet.MockRepository, *mock_swapi.MockService) {
+func configDep(t *testing.T) (*gomock.Controller, *mock_planet.MockRepository, *mock_swapi.MockService, *mock_audit.MockService) {
 	c := gomock.NewController(t)
 	r := mock_planet.NewMockRepository(c)
 	s := mock_swapi.NewMockService(c)
-	return c, r, s
+	a := mock_audit.NewMockService(c)
+	return c, r, s, a
 }
 
 func TestFindByName(t *testing.T) {
 	t.Run("sucess", func(t *testing.T) {
-		c, r, s := configDep(t)
+		c, r, s, a := configDep(t)
 		defer cancel()
 		defer c.Finish()
 
 		expected := entity.Planet{
 			ID:         "5f29e53f2939a742014a04af",
 			Name:       "Tatooine",
-			Climate:    "arid",
-			Terrain:    "desert",
+			Climate:    entity.Terms{"arid"},
+			Terrain:    entity.Terms{"desert"},
 			TotalFilms: 5,
 		}
 		r.EXPECT().FindByName(ctx, "Tatooine").Return(&expected, nil)
-		srv := NewService(r, s)
+		srv := NewService(r, s, a)
 
 		result, _ := srv.FindByName(ctx, "Tatooine")
 
@@ -48,18 +50,18 @@ func TestFindByName(t *testing.T) {
 	})
 
 	t.Run("when parameter name is invalid", func(t *testing.T) {
-		c, r, s := configDep(t)
+		c, r, s, a := configDep(t)
 		defer cancel()
 		defer c.Finish()
 
-		srv := NewService(r, s)
+		srv := NewService(r, s, a)
 		_, err := srv.FindByName(ctx, "")
 
 		assert.Equal(t, "name is invalid", err.Error())
 	})
 
 	t.Run("when planet not found", func(t *testing.T) {
-		c, r, s := configDep(t)
+		c, r, s, a := configDep(t)
 		defer cancel()
 		defer c.Finish()
 		r.EXPECT().FindByName(ctx, "Tatooine").Return(
@@ -67,14 +69,14 @@ func TestFindByName(t *testing.T) {
 			errors.New("mongo: no documents in result"),
 		)
 
-		srv := NewService(r, s)
+		srv := NewService(r, s, a)
 		_, err := srv.FindByName(ctx, "Tatooine")
 
 		assert.Equal(t, "planet not found", err.Error())
 	})
 
 	t.Run("when repository returns error", func(t *testing.T) {
-		c, r, s := configDep(t)
+		c, r, s, a := configDep(t)
 		defer c.Finish()
 		defer cancel()
 		r.EXPECT().FindByName(ctx, "Tatooine").Return(
@@ -82,7 +84,7 @@ func TestFindByName(t *testing.T) {
 			errors.New("other errors"),
 		)
 
-		srv := NewService(r, s)
+		srv := NewService(r, s, a)
 		_, err := srv.FindByName(ctx, "Tatooine")
 
 		assert.Equal(t, "internal server error", err.Error())
@@ -91,18 +93,18 @@ func TestFindByName(t *testing.T) {
 
 func TestFindByID(t *testing.T) {
 	t.Run("happy path", func(t *testing.T) {
-		c, r, s := configDep(t)
+		c, r, s, a := configDep(t)
 		defer c.Finish()
 		expected := &entity.Planet{
 			ID:         "5f29e53f2939a742014a04af",
 			Name:       "Tatooine",
-			Climate:    "arid",
-			Terrain:    "desert",
+			Climate:    entity.Terms{"arid"},
+			Terrain:    entity.Terms{"desert"},
 			TotalFilms: 5,
 		}
 		defer cancel()
 		r.EXPECT().FindByID(ctx, "5f29e53f2939a742014a04af").Return(expected, nil)
-		srv := NewService(r, s)
+		srv := NewService(r, s, a)
 
 		result, _ := srv.FindByID(ctx, "5f29e53f2939a742014a04af")
 
@@ -110,25 +112,25 @@ func TestFindByID(t *testing.T) {
 	})
 
 	t.Run("when parameter id is invalid", func(t *testing.T) {
-		c, r, s := configDep(t)
+		c, r, s, a := configDep(t)
 		defer c.Finish()
 		defer cancel()
 
-		srv := NewService(r, s)
+		srv := NewService(r, s, a)
 		_, err := srv.FindByID(ctx, "")
 
 		assert.Equal(t, "id is invalid", err.Error())
 	})
 
 	t.Run("when planet not found", func(t *testing.T) {
-		c, r, s := configDep(t)
+		c, r, s, a := configDep(t)
 		defer c.Finish()
 		defer cancel()
 		r.EXPECT().FindByID(ctx, "Tatooine").Return(
 			nil,
 			errors.New("mongo: no documents in result"),
 		)
-		srv := NewService(r, s)
+		srv := NewService(r, s, a)
 
 		_, err := srv.FindByID(ctx, "Tatooine")
 
@@ -136,14 +138,14 @@ func TestFindByID(t *testing.T) {
 	})
 
 	t.Run("when repository returns error", func(t *testing.T) {
-		c, r, s := configDep(t)
+		c, r, s, a := configDep(t)
 		defer c.Finish()
 		defer cancel()
 		r.EXPECT().FindByID(ctx, "Tatooine").Return(
 			nil,
 			errors.New("other errors"),
 		)
-		srv := NewService(r, s)
+		srv := NewService(r, s, a)
 		_, err := srv.FindByID(ctx, "Tatooine")
 
 		assert.Equal(t, "internal server error", err.Error())
@@ -152,7 +154,7 @@ func TestFindByID(t *testing.T) {
 
 func TestFindAll(t *testing.T) {
 	t.Run("happy path", func(t *testing.T) {
-		c, r, s := configDep(t)
+		c, r, s, a := configDep(t)
 		defer c.Finish()
 		defer cancel()
 
@@ -164,34 +166,34 @@ func TestFindAll(t *testing.T) {
 			{
 				ID:         "5f2c891e9a9e070b1ef2e28c",
 				Name:       "Alderaan",
-				Climate:    "temperate",
-				Terrain:    "grasslands, mountains",
+				Climate:    entity.Terms{"temperate"},
+				Terrain:    entity.Terms{"grasslands", "mountains"},
 				TotalFilms: 2,
 			},
 			{
 				ID:         "5f2c891e9a9e070b1ef2e28d",
 				Name:       "Tatooine",
-				Climate:    "arid",
-				Terrain:    "desert",
+				Climate:    entity.Terms{"arid"},
+				Terrain:    entity.Terms{"desert"},
 				TotalFilms: 5,
 			},
 			{
 				ID:         "5f2c891e9a9e070b1ef2e28e",
 				Name:       "Yavin IV",
-				Climate:    "temperate, tropical",
-				Terrain:    "jungle, rainforests",
+				Climate:    entity.Terms{"temperate", "tropical"},
+				Terrain:    entity.Terms{"jungle", "rainforests"},
 				TotalFilms: 1,
 			},
 		}
 		r.EXPECT().FindAll(ctx, limit, skip).Return(&expected, nil)
-		srv := NewService(r, s)
+		srv := NewService(r, s, a)
 		result, _ := srv.FindAll(ctx, 3, 0)
 
 		assert.Equal(t, 3, len(*result))
 	})
 
 	t.Run("when find all returns error", func(t *testing.T) {
-		c, r, s := configDep(t)
+		c, r, s, a := configDep(t)
 		defer c.Finish()
 		defer cancel()
 
@@ -200,7 +202,7 @@ func TestFindAll(t *testing.T) {
 		skip = 0
 
 		r.EXPECT().FindAll(ctx, limit, skip).Return(nil, errors.New("error"))
-		srv := NewService(r, s)
+		srv := NewService(r, s, a)
 
 		_, err := srv.FindAll(ctx, 3, 0)
 
@@ -208,58 +210,88 @@ func TestFindAll(t *testing.T) {
 	})
 }
 
+func TestFindByIDs(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+
+		ids := []string{"5f2c891e9a9e070b1ef2e28c", "5f2c891e9a9e070b1ef2e28d"}
+		expected := []entity.Planet{{ID: "5f2c891e9a9e070b1ef2e28c", Name: "Alderaan"}}
+
+		r.EXPECT().FindByIDs(ctx, ids).Return(&expected, nil)
+		srv := NewService(r, s, a)
+
+		result, err := srv.FindByIDs(ctx, ids)
+
+		assert.Nil(t, err)
+		assert.Equal(t, &expected, result)
+	})
+
+	t.Run("when find returns error", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+
+		r.EXPECT().FindByIDs(ctx, []string{"5f2c891e9a9e070b1ef2e28c"}).Return(nil, errors.New("error"))
+		srv := NewService(r, s, a)
+
+		_, err := srv.FindByIDs(ctx, []string{"5f2c891e9a9e070b1ef2e28c"})
+
+		assert.Equal(t, handler.InternalServer{Message: "error"}, err)
+	})
+}
+
 func TestExists(t *testing.T) {
 	t.Run("happy path", func(t *testing.T) {
-		c, r, s := configDep(t)
+		c, r, s, a := configDep(t)
 		defer c.Finish()
 		defer cancel()
 
 		r.EXPECT().FindByName(ctx, "Tatooine").Return(&entity.Planet{
 			ID:         "5f25e9782b148406adb55727",
 			Name:       "Tatooine",
-			Climate:    "arid",
-			Terrain:    "desert",
+			Climate:    entity.Terms{"arid"},
+			Terrain:    entity.Terms{"desert"},
 			TotalFilms: 5,
 		}, nil)
 
-		srv := NewService(r, s)
+		srv := NewService(r, s, a)
 		result, _ := srv.Exists(ctx, "Tatooine")
 
 		assert.Equal(t, true, result)
 	})
 
 	t.Run("when name param is empty", func(t *testing.T) {
-		c, r, s := configDep(t)
+		c, r, s, a := configDep(t)
 		defer c.Finish()
 		defer cancel()
 
-		srv := NewService(r, s)
+		srv := NewService(r, s, a)
 		_, err := srv.Exists(ctx, "")
 
 		assert.Equal(t, "name is invalid", err.Error())
 	})
 
 	t.Run("when there is no planet", func(t *testing.T) {
-		c, r, s := configDep(t)
+		c, r, s, a := configDep(t)
 		defer c.Finish()
 		defer cancel()
 
 		r.EXPECT().FindByName(ctx, "Tatooine").Return(nil, errors.New("mongo: no documents in result"))
 
-		srv := NewService(r, s)
+		srv := NewService(r, s, a)
 		result, _ := srv.Exists(ctx, "Tatooine")
 
 		assert.Equal(t, false, result)
 	})
 
 	t.Run("when db returns error", func(t *testing.T) {
-		c, r, s := configDep(t)
+		c, r, s, a := configDep(t)
 		defer c.Finish()
 		defer cancel()
 
 		r.EXPECT().FindByName(ctx, "Tatooine").Return(nil, errors.New("others errors"))
 
-		srv := NewService(r, s)
+		srv := NewService(r, s, a)
 		result, _ := srv.Exists(ctx, "Tatooine")
 
 		assert.Equal(t, false, result)
@@ -267,63 +299,139 @@ func TestExists(t *testing.T) {
 }
 
 func TestDelete(t *testing.T) {
+	before := &entity.Planet{
+		ID:      "5f2c88567563c4bae600d7df",
+		Name:    "Tatooine",
+		Climate: entity.Terms{"arid"},
+		Terrain: entity.Terms{"desert"},
+		Version: 3,
+	}
+
+	t.Run("when version matches", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+		defer cancel()
+		r.EXPECT().FindByID(ctx, "5f2c88567563c4bae600d7df").Return(before, nil)
+		r.EXPECT().Delete(ctx, "5f2c88567563c4bae600d7df", int64(3)).Return(nil)
+		a.EXPECT().Record(ctx, entity.ActionDelete, "5f2c88567563c4bae600d7df", before, nil).Return(nil)
+
+		srv := NewService(r, s, a)
+		err := srv.Delete(ctx, "5f2c88567563c4bae600d7df", 3)
+
+		assert.Equal(t, nil, err)
+	})
+
+	t.Run("when version is stale", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+		defer cancel()
+		r.EXPECT().FindByID(ctx, "5f2c88567563c4bae600d7df").Return(before, nil)
+
+		srv := NewService(r, s, a)
+		err := srv.Delete(ctx, "5f2c88567563c4bae600d7df", 2)
+
+		assert.Equal(t, handler.PreconditionFailed{Message: "planet was modified by another request"}, err)
+	})
+
+	t.Run("when planet changes concurrently", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+		defer cancel()
+		r.EXPECT().FindByID(ctx, "5f2c88567563c4bae600d7df").Return(before, nil)
+		r.EXPECT().Delete(ctx, "5f2c88567563c4bae600d7df", int64(3)).Return(ErrConflict)
+
+		srv := NewService(r, s, a)
+		err := srv.Delete(ctx, "5f2c88567563c4bae600d7df", handler.AnyVersion)
+
+		assert.Equal(t, handler.PreconditionFailed{Message: "planet was modified by another request"}, err)
+	})
+
 	t.Run("happy path", func(t *testing.T) {
-		c, r, s := configDep(t)
+		c, r, s, a := configDep(t)
 		defer c.Finish()
 		defer cancel()
-		r.EXPECT().Delete(ctx, "5f2c88567563c4bae600d7df").Return(nil)
+		r.EXPECT().FindByID(ctx, "5f2c88567563c4bae600d7df").Return(before, nil)
+		r.EXPECT().Delete(ctx, "5f2c88567563c4bae600d7df", int64(3)).Return(nil)
+		a.EXPECT().Record(ctx, entity.ActionDelete, "5f2c88567563c4bae600d7df", before, nil).Return(nil)
 
-		srv := NewService(r, s)
-		err := srv.Delete(ctx, "5f2c88567563c4bae600d7df")
+		srv := NewService(r, s, a)
+		err := srv.Delete(ctx, "5f2c88567563c4bae600d7df", handler.AnyVersion)
 
 		assert.Equal(t, nil, err)
 	})
 
 	t.Run("when id parameter is empty", func(t *testing.T) {
-		c, r, s := configDep(t)
+		c, r, s, a := configDep(t)
 		defer c.Finish()
 		defer cancel()
-		srv := NewService(r, s)
-		err := srv.Delete(ctx, "")
+		srv := NewService(r, s, a)
+		err := srv.Delete(ctx, "", handler.AnyVersion)
 
 		assert.Equal(t, "id is invalid", err.Error())
 	})
 
 	t.Run("when id parameter is invalid", func(t *testing.T) {
-		c, r, s := configDep(t)
+		c, r, s, a := configDep(t)
 		defer c.Finish()
 		defer cancel()
-		r.EXPECT().Delete(ctx, "abc").Return(errors.New("the provided hex string is not a valid ObjectID"))
+		r.EXPECT().FindByID(ctx, "abc").Return(nil, errors.New("the provided hex string is not a valid ObjectID"))
 
-		srv := NewService(r, s)
-		err := srv.Delete(ctx, "abc")
+		srv := NewService(r, s, a)
+		err := srv.Delete(ctx, "abc", handler.AnyVersion)
 
 		assert.Equal(t, "id is invalid", err.Error())
 	})
 
+	t.Run("when planet was already deleted", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+		defer cancel()
+		r.EXPECT().FindByID(ctx, "5f2c88567563c4bae600d7df").Return(nil, errors.New("mongo: no documents in result"))
+
+		srv := NewService(r, s, a)
+		err := srv.Delete(ctx, "5f2c88567563c4bae600d7df", handler.AnyVersion)
+
+		assert.Equal(t, nil, err)
+	})
+
 	t.Run("when db returns error", func(t *testing.T) {
-		c, r, s := configDep(t)
+		c, r, s, a := configDep(t)
 		defer c.Finish()
 		defer cancel()
-		r.EXPECT().Delete(ctx, "abc").Return(errors.New("delete error"))
+		r.EXPECT().FindByID(ctx, "5f2c88567563c4bae600d7df").Return(before, nil)
+		r.EXPECT().Delete(ctx, "5f2c88567563c4bae600d7df", int64(3)).Return(errors.New("delete error"))
 
-		srv := NewService(r, s)
-		err := srv.Delete(ctx, "abc")
+		srv := NewService(r, s, a)
+		err := srv.Delete(ctx, "5f2c88567563c4bae600d7df", handler.AnyVersion)
 
 		assert.Equal(t, "internal server error", err.Error())
 	})
+
+	t.Run("when audit returns error", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+		defer cancel()
+		r.EXPECT().FindByID(ctx, "5f2c88567563c4bae600d7df").Return(before, nil)
+		r.EXPECT().Delete(ctx, "5f2c88567563c4bae600d7df", int64(3)).Return(nil)
+		a.EXPECT().Record(ctx, entity.ActionDelete, "5f2c88567563c4bae600d7df", before, nil).Return(errors.New("audit error"))
+
+		srv := NewService(r, s, a)
+		err := srv.Delete(ctx, "5f2c88567563c4bae600d7df", handler.AnyVersion)
+
+		assert.Equal(t, nil, err)
+	})
 }
 
 func TestSave(t *testing.T) {
 	t.Run("happy path", func(t *testing.T) {
-		c, r, s := configDep(t)
+		c, r, s, a := configDep(t)
 		defer c.Finish()
 		defer cancel()
 
 		planet := &entity.Planet{
 			Name:       "Tatooine",
-			Climate:    "arid",
-			Terrain:    "desert",
+			Climate:    entity.Terms{"arid"},
+			Terrain:    entity.Terms{"desert"},
 			TotalFilms: 5,
 		}
 
@@ -338,92 +446,128 @@ func TestSave(t *testing.T) {
 
 		r.EXPECT().Save(ctx, planet).Return(nil)
 		r.EXPECT().FindByName(ctx, "Tatooine").Return(nil, errors.New("mongo: no documents in result"))
-		s.EXPECT().GetPlanet("Tatooine").Return(adp, nil)
+		s.EXPECT().GetPlanet(ctx, "Tatooine").Return(adp, nil)
+		a.EXPECT().Record(ctx, entity.ActionCreate, planet.ID, nil, planet).Return(nil)
 
-		srv := NewService(r, s)
+		srv := NewService(r, s, a)
 		err := srv.Save(ctx, planet)
 
 		assert.Equal(t, nil, err)
 	})
 
+	t.Run("a new planet is not removed", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+
+		deleted := time.Now()
+		planet := &entity.Planet{Name: "Tatooine", Climate: entity.Terms{"arid"}, Terrain: entity.Terms{"desert"}, DeletedAt: &deleted}
+
+		r.EXPECT().FindByName(ctx, "Tatooine").Return(nil, errors.New("mongo: no documents in result"))
+		s.EXPECT().GetPlanet(ctx, "Tatooine").Return(adapter.Planets{Count: 1, Results: []adapter.Planet{{Films: []string{"film"}}}}, nil)
+		r.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, p *entity.Planet) error {
+			assert.Nil(t, p.DeletedAt)
+			return nil
+		})
+		a.EXPECT().Record(ctx, entity.ActionCreate, gomock.Any(), nil, gomock.Any()).Return(nil)
+
+		err := NewService(r, s, a).Save(ctx, planet)
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("when planet is invalid", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+		defer cancel()
+
+		srv := NewService(r, s, a)
+		err := srv.Save(ctx, &entity.Planet{
+			Name:    "Tatooine",
+			Climate: entity.Terms{"arid"},
+			Terrain: entity.Terms{"sand"},
+		})
+
+		assert.Equal(t, handler.BadRequest{Message: `terrain has unknown value: "sand"`}, err)
+	})
+
 	t.Run("when db returns error", func(t *testing.T) {
-		c, r, s := configDep(t)
+		c, r, s, a := configDep(t)
 		defer c.Finish()
 		defer cancel()
 
 		planet := &entity.Planet{
 			Name:       "Tatooine",
-			Climate:    "arid",
-			Terrain:    "desert",
+			Climate:    entity.Terms{"arid"},
+			Terrain:    entity.Terms{"desert"},
 			TotalFilms: 5,
 		}
 
 		r.EXPECT().FindByName(ctx, "Tatooine").Return(nil, errors.New("db error"))
 
-		srv := NewService(r, s)
+		srv := NewService(r, s, a)
 		err := srv.Save(ctx, planet)
 
 		assert.Equal(t, "db error", err.Error())
 	})
 
 	t.Run("when planet already registered, returns error", func(t *testing.T) {
-		c, r, s := configDep(t)
+		c, r, s, a := configDep(t)
 		defer c.Finish()
 		defer cancel()
 
 		planet := &entity.Planet{
 			Name:       "Tatooine",
-			Climate:    "arid",
-			Terrain:    "desert",
+			Climate:    entity.Terms{"arid"},
+			Terrain:    entity.Terms{"desert"},
 			TotalFilms: 5,
 		}
 
 		r.EXPECT().FindByName(ctx, "Tatooine").Return(planet, nil)
 
-		srv := NewService(r, s)
+		srv := NewService(r, s, a)
 		err := srv.Save(ctx, planet)
 
 		assert.Equal(t, "planet already registered", err.Error())
 	})
 
 	t.Run("when swapi api returns error", func(t *testing.T) {
-		c, r, s := configDep(t)
+		c, r, s, a := configDep(t)
 		defer c.Finish()
 		defer cancel()
 
 		r.EXPECT().FindByName(ctx, "Tatooine").Return(nil, errors.New("mongo: no documents in result"))
-		s.EXPECT().GetPlanet("Tatooine").Return(adapter.Planets{}, handler.InternalServer{Message: "swapi error"})
+		s.EXPECT().GetPlanet(ctx, "Tatooine").Return(adapter.Planets{}, handler.InternalServer{Message: "swapi error"})
 
-		srv := NewService(r, s)
+		srv := NewService(r, s, a)
 		err := srv.Save(ctx, &entity.Planet{
 			Name:    "Tatooine",
-			Climate: "arid",
-			Terrain: "desert",
+			Climate: entity.Terms{"arid"},
+			Terrain: entity.Terms{"desert"},
 		})
 
 		assert.Equal(t, "internal server error", err.Error())
 	})
 
 	t.Run("when swapi api not found planet", func(t *testing.T) {
-		c, r, s := configDep(t)
+		c, r, s, a := configDep(t)
 		defer c.Finish()
 		defer cancel()
 
 		r.EXPECT().FindByName(ctx, "Test").Return(nil, errors.New("mongo: no documents in result"))
-		s.EXPECT().GetPlanet("Test").Return(adapter.Planets{}, nil)
+		s.EXPECT().GetPlanet(ctx, "Test").Return(adapter.Planets{}, nil)
 
-		srv := NewService(r, s)
+		srv := NewService(r, s, a)
 		err := srv.Save(ctx, &entity.Planet{
 			Name:    "Test",
-			Climate: "arid",
-			Terrain: "desert",
+			Climate: entity.Terms{"arid"},
+			Terrain: entity.Terms{"desert"},
 		})
 
 		assert.Equal(t, "non-existent planet", err.Error())
 	})
 
 	t.Run("when total appearances returns error", func(t *testing.T) {
-		c, r, s := configDep(t)
+		c, r, s, a := configDep(t)
 		defer c.Finish()
 		defer cancel()
 
@@ -433,13 +577,13 @@ func TestSave(t *testing.T) {
 		}
 
 		r.EXPECT().FindByName(ctx, "Tatooine").Return(nil, errors.New("mongo: no documents in result"))
-		s.EXPECT().GetPlanet("Tatooine").Return(adp, nil)
+		s.EXPECT().GetPlanet(ctx, "Tatooine").Return(adp, nil)
 
-		srv := NewService(r, s)
+		srv := NewService(r, s, a)
 		err := srv.Save(ctx, &entity.Planet{
 			Name:       "Tatooine",
-			Climate:    "arid",
-			Terrain:    "desert",
+			Climate:    entity.Terms{"arid"},
+			Terrain:    entity.Terms{"desert"},
 			TotalFilms: 5,
 		})
 
@@ -447,14 +591,14 @@ func TestSave(t *testing.T) {
 	})
 
 	t.Run("when save returns error", func(t *testing.T) {
-		c, r, s := configDep(t)
+		c, r, s, a := configDep(t)
 		defer c.Finish()
 		defer cancel()
 
 		planet := &entity.Planet{
 			Name:       "Tatooine",
-			Climate:    "arid",
-			Terrain:    "desert",
+			Climate:    entity.Terms{"arid"},
+			Terrain:    entity.Terms{"desert"},
 			TotalFilms: 5,
 		}
 
@@ -469,11 +613,303 @@ func TestSave(t *testing.T) {
 
 		r.EXPECT().Save(ctx, planet).Return(errors.New("db error"))
 		r.EXPECT().FindByName(ctx, "Tatooine").Return(nil, errors.New("mongo: no documents in result"))
-		s.EXPECT().GetPlanet("Tatooine").Return(adp, nil)
+		s.EXPECT().GetPlanet(ctx, "Tatooine").Return(adp, nil)
 
-		srv := NewService(r, s)
+		srv := NewService(r, s, a)
 		err := srv.Save(ctx, planet)
 
 		assert.Equal(t, "db error", err.Error())
 	})
 }
+
+func TestDistinct(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+		defer cancel()
+		r.EXPECT().Distinct(ctx, "terrain").Return([]string{"ocean", "desert"}, nil)
+
+		srv := NewService(r, s, a)
+		values, err := srv.Distinct(ctx, "terrain")
+
+		assert.Equal(t, nil, err)
+		assert.Equal(t, []string{"desert", "ocean"}, values)
+	})
+
+	t.Run("when field is invalid", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+		defer cancel()
+
+		srv := NewService(r, s, a)
+		_, err := srv.Distinct(ctx, "name")
+
+		assert.Equal(t, "field is invalid", err.Error())
+	})
+
+	t.Run("when db returns error", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+		defer cancel()
+		r.EXPECT().Distinct(ctx, "climate").Return(nil, errors.New("distinct error"))
+
+		srv := NewService(r, s, a)
+		_, err := srv.Distinct(ctx, "climate")
+
+		assert.Equal(t, "internal server error", err.Error())
+	})
+}
+
+func TestRestore(t *testing.T) {
+	deleted := func() *entity.Planet {
+		deletedAt := time.Now()
+		return &entity.Planet{
+			ID:        "5f2c88567563c4bae600d7df",
+			Name:      "Tatooine",
+			Climate:   entity.Terms{"arid"},
+			Terrain:   entity.Terms{"desert"},
+			DeletedAt: &deletedAt,
+		}
+	}
+
+	t.Run("happy path", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+		defer cancel()
+		r.EXPECT().FindDeleted(ctx, "5f2c88567563c4bae600d7df").Return(deleted(), nil)
+		r.EXPECT().FindByName(ctx, "Tatooine").Return(nil, errors.New("mongo: no documents in result"))
+		r.EXPECT().Restore(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, planet *entity.Planet) error {
+			planet.DeletedAt = nil
+			planet.Version++
+			return nil
+		})
+		a.EXPECT().Record(ctx, entity.ActionRestore, "5f2c88567563c4bae600d7df", gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context, _ string, _ string, before *entity.Planet, after *entity.Planet) {
+				assert.NotNil(t, before.DeletedAt)
+				assert.Nil(t, after.DeletedAt)
+			}).
+			Return(nil)
+
+		srv := NewService(r, s, a)
+		planet, err := srv.Restore(ctx, "5f2c88567563c4bae600d7df", handler.AnyVersion)
+
+		assert.Equal(t, nil, err)
+		assert.Nil(t, planet.DeletedAt)
+		assert.Equal(t, int64(1), planet.Version)
+	})
+
+	t.Run("when id parameter is empty", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+		defer cancel()
+
+		srv := NewService(r, s, a)
+		_, err := srv.Restore(ctx, "", handler.AnyVersion)
+
+		assert.Equal(t, "id is invalid", err.Error())
+	})
+
+	t.Run("when planet is not deleted", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+		defer cancel()
+		r.EXPECT().FindDeleted(ctx, "5f2c88567563c4bae600d7df").Return(nil, errors.New("mongo: no documents in result"))
+
+		srv := NewService(r, s, a)
+		_, err := srv.Restore(ctx, "5f2c88567563c4bae600d7df", handler.AnyVersion)
+
+		assert.Equal(t, handler.NotFound{Message: "planet not found"}, err)
+	})
+
+	t.Run("when planet was re-created", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+		defer cancel()
+		r.EXPECT().FindDeleted(ctx, "5f2c88567563c4bae600d7df").Return(deleted(), nil)
+		r.EXPECT().FindByName(ctx, "Tatooine").Return(&entity.Planet{ID: "5f2c88567563c4bae600d7e0", Name: "Tatooine"}, nil)
+
+		srv := NewService(r, s, a)
+		_, err := srv.Restore(ctx, "5f2c88567563c4bae600d7df", handler.AnyVersion)
+
+		assert.Equal(t, handler.BadRequest{Message: "planet already registered"}, err)
+	})
+
+	t.Run("when db returns error", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+		defer cancel()
+		r.EXPECT().FindDeleted(ctx, "5f2c88567563c4bae600d7df").Return(deleted(), nil)
+		r.EXPECT().FindByName(ctx, "Tatooine").Return(nil, errors.New("mongo: no documents in result"))
+		r.EXPECT().Restore(ctx, gomock.Any()).Return(errors.New("update error"))
+
+		srv := NewService(r, s, a)
+		_, err := srv.Restore(ctx, "5f2c88567563c4bae600d7df", handler.AnyVersion)
+
+		assert.Equal(t, "internal server error", err.Error())
+	})
+}
+
+func TestPurge(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+		defer cancel()
+		purged := []entity.Planet{{ID: "5f2c88567563c4bae600d7df"}, {ID: "5f2c88567563c4bae600d7e0"}}
+		r.EXPECT().Purge(ctx, gomock.Any()).Return(purged, nil)
+		a.EXPECT().Record(ctx, entity.ActionPurge, "5f2c88567563c4bae600d7df", &purged[0], nil).Return(nil)
+		a.EXPECT().Record(ctx, entity.ActionPurge, "5f2c88567563c4bae600d7e0", &purged[1], nil).Return(nil)
+
+		srv := NewService(r, s, a)
+		total, err := srv.Purge(ctx, 24*time.Hour)
+
+		assert.Equal(t, nil, err)
+		assert.Equal(t, int64(2), total)
+	})
+
+	t.Run("when retention is negative", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+		defer cancel()
+
+		srv := NewService(r, s, a)
+		_, err := srv.Purge(ctx, -time.Hour)
+
+		assert.Equal(t, "retention is invalid", err.Error())
+	})
+
+	t.Run("when db returns error", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+		defer cancel()
+		purged := []entity.Planet{{ID: "5f2c88567563c4bae600d7df"}}
+		r.EXPECT().Purge(ctx, gomock.Any()).Return(purged, errors.New("delete error"))
+		a.EXPECT().Record(ctx, entity.ActionPurge, "5f2c88567563c4bae600d7df", &purged[0], nil).Return(nil)
+
+		srv := NewService(r, s, a)
+		_, err := srv.Purge(ctx, time.Hour)
+
+		assert.Equal(t, "internal server error", err.Error())
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	current := func() *entity.Planet {
+		return &entity.Planet{
+			ID:         "5f2c88567563c4bae600d7df",
+			Name:       "Tatooine",
+			Climate:    entity.Terms{"arid"},
+			Terrain:    entity.Terms{"desert"},
+			TotalFilms: 5,
+			Version:    2,
+		}
+	}
+
+	changes := func() *entity.Planet {
+		return &entity.Planet{
+			ID:      "5f2c88567563c4bae600d7df",
+			Name:    "Tatooine",
+			Climate: entity.Terms{"arid", "hot"},
+			Terrain: entity.Terms{"desert"},
+		}
+	}
+
+	t.Run("happy path", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+		defer cancel()
+		r.EXPECT().FindByID(ctx, "5f2c88567563c4bae600d7df").Return(current(), nil)
+		r.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, planet *entity.Planet) error {
+			assert.Equal(t, int64(2), planet.Version)
+			planet.Version++
+			return nil
+		})
+		a.EXPECT().Record(ctx, entity.ActionUpdate, "5f2c88567563c4bae600d7df", current(), gomock.Any()).Return(nil)
+
+		planet := changes()
+		srv := NewService(r, s, a)
+		err := srv.Update(ctx, planet, 2)
+
+		assert.Equal(t, nil, err)
+		assert.Equal(t, 5, planet.TotalFilms)
+		assert.Equal(t, int64(3), planet.Version)
+	})
+
+	t.Run("when name changes", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+		defer cancel()
+
+		adp := adapter.Planets{
+			Count: 1,
+			Results: []adapter.Planet{
+				{
+					Films: []string{"film"},
+				},
+			},
+		}
+
+		r.EXPECT().FindByID(ctx, "5f2c88567563c4bae600d7df").Return(current(), nil)
+		r.EXPECT().FindByName(ctx, "Hoth").Return(nil, errors.New("mongo: no documents in result"))
+		s.EXPECT().GetPlanet(ctx, "Hoth").Return(adp, nil)
+		r.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+		a.EXPECT().Record(ctx, entity.ActionUpdate, "5f2c88567563c4bae600d7df", gomock.Any(), gomock.Any()).Return(nil)
+
+		planet := changes()
+		planet.Name = "Hoth"
+		srv := NewService(r, s, a)
+		err := srv.Update(ctx, planet, handler.AnyVersion)
+
+		assert.Equal(t, nil, err)
+		assert.Equal(t, 1, planet.TotalFilms)
+	})
+
+	t.Run("when planet is invalid", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+		defer cancel()
+
+		planet := changes()
+		planet.Terrain = entity.Terms{"sand"}
+		srv := NewService(r, s, a)
+		err := srv.Update(ctx, planet, handler.AnyVersion)
+
+		assert.Equal(t, handler.BadRequest{Message: `terrain has unknown value: "sand"`}, err)
+	})
+
+	t.Run("when planet not found", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+		defer cancel()
+		r.EXPECT().FindByID(ctx, "5f2c88567563c4bae600d7df").Return(nil, errors.New("mongo: no documents in result"))
+
+		srv := NewService(r, s, a)
+		err := srv.Update(ctx, changes(), handler.AnyVersion)
+
+		assert.Equal(t, handler.NotFound{Message: "planet not found"}, err)
+	})
+
+	t.Run("when version is stale", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+		defer cancel()
+		r.EXPECT().FindByID(ctx, "5f2c88567563c4bae600d7df").Return(current(), nil)
+
+		srv := NewService(r, s, a)
+		err := srv.Update(ctx, changes(), 1)
+
+		assert.Equal(t, handler.PreconditionFailed{Message: "planet was modified by another request"}, err)
+	})
+
+	t.Run("when planet changes concurrently", func(t *testing.T) {
+		c, r, s, a := configDep(t)
+		defer c.Finish()
+		defer cancel()
+		r.EXPECT().FindByID(ctx, "5f2c88567563c4bae600d7df").Return(current(), nil)
+		r.EXPECT().Update(ctx, gomock.Any()).Return(ErrConflict)
+
+		srv := NewService(r, s, a)
+		err := srv.Update(ctx, changes(), 2)
+
+		assert.Equal(t, handler.PreconditionFailed{Message: "planet was modified by another request"}, err)
+	})
+}
@@ -0,0 +1,119 @@
+package planet
+
+import (
+	"context"
+	"star-wars/entity"
+	"star-wars/tracing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type traced struct {
+	next Service
+}
+
+// NewTracedService opens a span around every method of s
+func NewTracedService(s Service) Service {
+	return &traced{
+		next: s,
+	}
+}
+
+func start(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, "planet.Service/"+method, trace.WithAttributes(attrs...))
+}
+
+func (t traced) Exists(ctx context.Context, name string) (bool, error) {
+	ctx, span := start(ctx, "Exists", attribute.String("planet.name", name))
+	exists, err := t.next.Exists(ctx, name)
+	tracing.End(span, err)
+	return exists, err
+}
+
+func (t traced) Save(ctx context.Context, planet *entity.Planet) error {
+	ctx, span := start(ctx, "Save", attribute.String("planet.name", planet.Name))
+	err := t.next.Save(ctx, planet)
+	span.SetAttributes(attribute.String("planet.id", planet.ID))
+	tracing.End(span, err)
+	return err
+}
+
+func (t traced) Update(ctx context.Context, planet *entity.Planet, version int64) error {
+	ctx, span := start(ctx, "Update", attribute.String("planet.id", planet.ID), attribute.Int64("planet.version", version))
+	err := t.next.Update(ctx, planet, version)
+	tracing.End(span, err)
+	return err
+}
+
+func (t traced) FindAll(ctx context.Context, limit int64, skip int64) (*[]entity.Planet, error) {
+	ctx, span := start(ctx, "FindAll", attribute.Int64("limit", limit), attribute.Int64("skip", skip))
+	planets, err := t.next.FindAll(ctx, limit, skip)
+	tracing.End(span, err)
+	return planets, err
+}
+
+func (t traced) FindByName(ctx context.Context, name string) (*entity.Planet, error) {
+	ctx, span := start(ctx, "FindByName", attribute.String("planet.name", name))
+	planet, err := t.next.FindByName(ctx, name)
+	tracing.End(span, err)
+	return planet, err
+}
+
+func (t traced) FindByID(ctx context.Context, id string) (*entity.Planet, error) {
+	ctx, span := start(ctx, "FindByID", attribute.String("planet.id", id))
+	planet, err := t.next.FindByID(ctx, id)
+	tracing.End(span, err)
+	return planet, err
+}
+
+func (t traced) FindByIDs(ctx context.Context, ids []string) (*[]entity.Planet, error) {
+	ctx, span := start(ctx, "FindByIDs", attribute.Int("planet.ids", len(ids)))
+	planets, err := t.next.FindByIDs(ctx, ids)
+	tracing.End(span, err)
+	return planets, err
+}
+
+func (t traced) Delete(ctx context.Context, id string, version int64) error {
+	ctx, span := start(ctx, "Delete", attribute.String("planet.id", id), attribute.Int64("planet.version", version))
+	err := t.next.Delete(ctx, id, version)
+	tracing.End(span, err)
+	return err
+}
+
+func (t traced) Restore(ctx context.Context, id string, version int64) (*entity.Planet, error) {
+	ctx, span := start(ctx, "Restore", attribute.String("planet.id", id), attribute.Int64("planet.version", version))
+	planet, err := t.next.Restore(ctx, id, version)
+	tracing.End(span, err)
+	return planet, err
+}
+
+func (t traced) Purge(ctx context.Context, retention time.Duration) (int64, error) {
+	ctx, span := start(ctx, "Purge", attribute.String("retention", retention.String()))
+	total, err := t.next.Purge(ctx, retention)
+	span.SetAttributes(attribute.Int64("purged", total))
+	tracing.End(span, err)
+	return total, err
+}
+
+func (t traced) Distinct(ctx context.Context, field string) ([]string, error) {
+	ctx, span := start(ctx, "Distinct", attribute.String("field", field))
+	values, err := t.next.Distinct(ctx, field)
+	tracing.End(span, err)
+	return values, err
+}
+
+func (t traced) SaveMany(ctx context.Context, planets []entity.Planet, atomic bool) ([]error, error) {
+	ctx, span := start(ctx, "SaveMany", attribute.Int("batch.size", len(planets)), attribute.Bool("batch.atomic", atomic))
+	errs, err := t.next.SaveMany(ctx, planets, atomic)
+	tracing.End(span, err)
+	return errs, err
+}
+
+func (t traced) DeleteMany(ctx context.Context, refs []entity.PlanetRef, atomic bool) ([]error, error) {
+	ctx, span := start(ctx, "DeleteMany", attribute.Int("batch.size", len(refs)), attribute.Bool("batch.atomic", atomic))
+	errs, err := t.next.DeleteMany(ctx, refs, atomic)
+	tracing.End(span, err)
+	return errs, err
+}
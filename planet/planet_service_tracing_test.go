@@ -0,0 +1,51 @@
+package planet
+
+import (
+	"context"
+	"star-wars/api/handler"
+	"star-wars/entity"
+	"star-wars/planet/mock_planet"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracedService(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	c := gomock.NewController(t)
+	defer c.Finish()
+	s := mock_planet.NewMockService(c)
+
+	var inner trace.SpanContext
+	s.EXPECT().FindByID(gomock.Any(), "5f2c88567563c4bae600d7e0").DoAndReturn(
+		func(ctx context.Context, id string) (*entity.Planet, error) {
+			inner = trace.SpanContextFromContext(ctx)
+			return nil, handler.NotFound{Message: "planet not found"}
+		})
+	s.EXPECT().Distinct(gomock.Any(), "climate").Return([]string{"arid"}, nil)
+
+	srv := NewTracedService(s)
+
+	_, err := srv.FindByID(ctx, "5f2c88567563c4bae600d7e0")
+	assert.Equal(t, handler.NotFound{Message: "planet not found"}, err)
+
+	values, err := srv.Distinct(ctx, "climate")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"arid"}, values)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "planet.Service/FindByID", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, spans[0].SpanContext().SpanID(), inner.SpanID())
+	assert.Equal(t, "planet.Service/Distinct", spans[1].Name())
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+}
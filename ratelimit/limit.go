@@ -0,0 +1,104 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit allows Count requests per Period; the bucket holds Count tokens and
+// refills continuously, so bursts up to Count are accepted
+type Limit struct {
+	Count  int
+	Period time.Duration
+}
+
+// ParseLimit reads "count/period", such as 100/1m
+func ParseLimit(s string) (Limit, error) {
+	parts := strings.SplitN(strings.TrimSpace(s), "/", 2)
+	if len(parts) != 2 {
+		return Limit{}, fmt.Errorf("limit %q must be count/period", s)
+	}
+
+	count, err := strconv.Atoi(parts[0])
+	if err != nil || count <= 0 {
+		return Limit{}, fmt.Errorf("limit %q has an invalid count", s)
+	}
+
+	period, err := time.ParseDuration(parts[1])
+	if err != nil || period <= 0 {
+		return Limit{}, fmt.Errorf("limit %q has an invalid period", s)
+	}
+
+	return Limit{Count: count, Period: period}, nil
+}
+
+// ParseRoutes reads per-route limits keyed by "METHOD /path/{param}"
+func ParseRoutes(routes map[string]string) (map[string]Limit, error) {
+	limits := make(map[string]Limit, len(routes))
+
+	for route, value := range routes {
+		l, err := ParseLimit(value)
+		if err != nil {
+			return nil, fmt.Errorf("route %q: %w", route, err)
+		}
+		limits[normalize(route)] = l
+	}
+
+	return limits, nil
+}
+
+func normalize(route string) string {
+	fields := strings.Fields(route)
+	if len(fields) != 2 {
+		return route
+	}
+	return strings.ToUpper(fields[0]) + " " + fields[1]
+}
+
+// perSecond refill rate
+func (l Limit) perSecond() float64 {
+	return float64(l.Count) / l.Period.Seconds()
+}
+
+func (l Limit) String() string {
+	return fmt.Sprintf("%d;w=%d", l.Count, int(math.Ceil(l.Period.Seconds())))
+}
+
+// refill the tokens left at last up to now, never above Count
+func refill(tokens float64, last time.Time, now time.Time, l Limit) float64 {
+	if elapsed := now.Sub(last).Seconds(); elapsed > 0 {
+		tokens += elapsed * l.perSecond()
+	}
+	return math.Min(tokens, float64(l.Count))
+}
+
+// Result of taking a token
+type Result struct {
+	Allowed    bool
+	Limit      Limit
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+func result(l Limit, allowed bool, tokens float64) Result {
+	seconds := func(missing float64) time.Duration {
+		return time.Duration(missing / l.perSecond() * float64(time.Second))
+	}
+
+	r := Result{
+		Allowed:   allowed,
+		Limit:     l,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     seconds(float64(l.Count) - tokens),
+	}
+
+	if !allowed {
+		r.RetryAfter = seconds(1 - tokens)
+	}
+
+	return r
+}
@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLimit(t *testing.T) {
+	type test struct {
+		value   string
+		want    Limit
+		wantErr string
+	}
+
+	tests := []test{
+		{value: "100/1m", want: Limit{Count: 100, Period: time.Minute}},
+		{value: " 5/1s ", want: Limit{Count: 5, Period: time.Second}},
+		{value: "100", wantErr: `limit "100" must be count/period`},
+		{value: "0/1m", wantErr: `limit "0/1m" has an invalid count`},
+		{value: "10/forever", wantErr: `limit "10/forever" has an invalid period`},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLimit(tt.value)
+
+		assert.Equal(t, tt.want, got, tt.value)
+		if tt.wantErr != "" {
+			assert.EqualError(t, err, tt.wantErr)
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+}
+
+func TestParseRoutes(t *testing.T) {
+	routes, err := ParseRoutes(map[string]string{"post  /planets": "10/1m"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]Limit{"POST /planets": {Count: 10, Period: time.Minute}}, routes)
+
+	_, err = ParseRoutes(map[string]string{"POST /planets": "fast"})
+	assert.EqualError(t, err, `route "POST /planets": limit "fast" must be count/period`)
+}
+
+func TestResult(t *testing.T) {
+	l := Limit{Count: 60, Period: time.Minute}
+
+	r := result(l, true, 30.5)
+	assert.Equal(t, Result{Allowed: true, Limit: l, Remaining: 30, Reset: 29500 * time.Millisecond}, r)
+
+	r = result(l, false, 0.25)
+	assert.Equal(t, 750*time.Millisecond, r.RetryAfter)
+	assert.Equal(t, 0, r.Remaining)
+	assert.Equal(t, "60;w=60", l.String())
+}
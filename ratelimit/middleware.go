@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"math"
+	"regexp"
+	"star-wars/api/handler"
+	"star-wars/api/openapi"
+	"star-wars/auth"
+	"star-wars/clientip"
+	"star-wars/logger"
+	"star-wars/metrics"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// defaultGroup shares one bucket per client across routes without their own limit
+const defaultGroup = "default"
+
+// Middleware takes a token per request from the client bucket of the route;
+// routes listed in routes get their own bucket, the others share def. The
+// client is the authenticated principal, or the IP for anonymous requests.
+// Paths in exempt are never limited, and a failing store lets requests through.
+func Middleware(store Store, def Limit, routes map[string]Limit, exempt ...string) gin.HandlerFunc {
+	skip := map[string]bool{}
+	for _, path := range exempt {
+		skip[path] = true
+	}
+
+	return func(c *gin.Context) {
+		if skip[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		group, limit := defaultGroup, def
+		if l, ok := routes[route(c)]; ok {
+			group, limit = route(c), l
+		}
+
+		ctx := c.Request.Context()
+
+		allowed, tokens, err := store.Take(ctx, "ratelimit:"+group+":"+client(c), limit, time.Now())
+		if err != nil {
+			logger.FromContext(ctx).Warn("rate limit store failed", zap.Error(err))
+			c.Next()
+			return
+		}
+
+		r := result(limit, allowed, tokens)
+
+		c.Header("RateLimit-Limit", strconv.Itoa(limit.Count))
+		c.Header("RateLimit-Remaining", strconv.Itoa(r.Remaining))
+		c.Header("RateLimit-Reset", seconds(r.Reset))
+		c.Header("RateLimit-Policy", limit.String())
+
+		if !r.Allowed {
+			metrics.RateLimited(group)
+			c.Header("Retry-After", seconds(r.RetryAfter))
+			handler.ResponseError(handler.TooManyRequests{Message: "rate limit exceeded"}, c)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// version prefix of the API; every version of a route shares its limit
+var version = regexp.MustCompile(`^/v[0-9]+(/|$)`)
+
+// route as written in the config and the spec: "GET /planets/{id}"
+func route(c *gin.Context) string {
+	return c.Request.Method + " " + openapi.Path(version.ReplaceAllString(c.FullPath(), "/"))
+}
+
+func client(c *gin.Context) string {
+	if p, ok := auth.From(c.Request.Context()); ok && p.Authenticated() {
+		return p.Method + ":" + p.Subject
+	}
+	return "ip:" + clientip.Of(c.Request)
+}
+
+// seconds rounded up, a client waiting that long always finds a token
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,142 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"star-wars/api/openapi"
+	"star-wars/auth"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type failing struct{}
+
+func (failing) Take(context.Context, string, Limit, time.Time) (bool, float64, error) {
+	return false, 0, errors.New("connection refused")
+}
+
+func router(store Store) *gin.Engine {
+	r := gin.New()
+	r.Use(openapi.CustomMethods(r))
+	r.Use(func(c *gin.Context) {
+		if subject := c.GetHeader("X-Subject"); subject != "" {
+			p := auth.Principal{Subject: subject, Role: auth.RoleReader, Method: "jwt"}
+			c.Request = c.Request.WithContext(auth.With(c.Request.Context(), p))
+		}
+	})
+	r.Use(Middleware(
+		store,
+		Limit{Count: 2, Period: time.Minute},
+		map[string]Limit{
+			"GET /planets/{id}":   {Count: 1, Period: time.Minute},
+			"GET /planets:export": {Count: 1, Period: time.Minute},
+		},
+		"/livez",
+	))
+
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/planets", ok)
+	r.GET("/climates", ok)
+	r.GET("/planets/:id", ok)
+	r.GET("/v2/planets/:id", ok)
+	r.GET("/livez", ok)
+	r.GET(openapi.CustomMethod("/planets", "export"), ok)
+
+	return r
+}
+
+func get(r *gin.Engine, path string, subject string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", path, nil)
+	req.RemoteAddr = "10.0.0.1:4242"
+	if subject != "" {
+		req.Header.Set("X-Subject", subject)
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("when the client runs out of tokens", func(t *testing.T) {
+		r := router(NewMemoryStore())
+
+		w := get(r, "/planets", "")
+		assert.Equal(t, 200, w.Code)
+		assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "30", w.Header().Get("RateLimit-Reset"))
+		assert.Equal(t, "2;w=60", w.Header().Get("RateLimit-Policy"))
+
+		assert.Equal(t, 200, get(r, "/climates", "").Code, "routes without a limit share the default bucket")
+
+		w = get(r, "/planets", "")
+		assert.Equal(t, 429, w.Code)
+		assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "30", w.Header().Get("Retry-After"))
+		assert.Equal(t, `{"error":"rate limit exceeded"}`, w.Body.String())
+	})
+
+	t.Run("when the route has its own limit", func(t *testing.T) {
+		r := router(NewMemoryStore())
+
+		assert.Equal(t, 200, get(r, "/planets/1", "").Code)
+		assert.Equal(t, 429, get(r, "/planets/2", "").Code)
+		assert.Equal(t, 429, get(r, "/v2/planets/2", "").Code, "versions share the limit")
+		assert.Equal(t, 200, get(r, "/planets", "").Code)
+	})
+
+	t.Run("when the route is a custom method", func(t *testing.T) {
+		r := router(NewMemoryStore())
+
+		assert.Equal(t, 200, get(r, "/planets:export", "").Code)
+		assert.Equal(t, 429, get(r, "/planets:export", "").Code)
+		assert.Equal(t, 200, get(r, "/planets", "").Code)
+	})
+
+	t.Run("when clients are authenticated", func(t *testing.T) {
+		r := router(NewMemoryStore())
+
+		assert.Equal(t, 200, get(r, "/planets/1", "luke").Code)
+		assert.Equal(t, 200, get(r, "/planets/1", "leia").Code, "same IP, another principal")
+		assert.Equal(t, 200, get(r, "/planets/1", "").Code, "same IP, anonymous")
+		assert.Equal(t, 429, get(r, "/planets/1", "luke").Code)
+	})
+
+	t.Run("when an anonymous client forges forwarding headers", func(t *testing.T) {
+		r := router(NewMemoryStore())
+
+		assert.Equal(t, 200, get(r, "/planets/1", "").Code)
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/planets/2", nil)
+		req.RemoteAddr = "10.0.0.1:4243"
+		req.Header.Set("X-Forwarded-For", "198.51.100.7")
+		req.Header.Set("X-Real-IP", "198.51.100.8")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, 429, w.Code, "the peer is no trusted proxy")
+	})
+
+	t.Run("when the path is exempt", func(t *testing.T) {
+		r := router(NewMemoryStore())
+
+		for i := 0; i < 5; i++ {
+			w := get(r, "/livez", "")
+			assert.Equal(t, 200, w.Code)
+			assert.Equal(t, "", w.Header().Get("RateLimit-Limit"))
+		}
+	})
+
+	t.Run("when the store fails", func(t *testing.T) {
+		r := router(failing{})
+
+		w := get(r, "/planets", "")
+		assert.Equal(t, 200, w.Code)
+		assert.Equal(t, "", w.Header().Get("RateLimit-Limit"))
+	})
+}
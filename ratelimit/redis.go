@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// take runs atomically on the server so every instance shares the bucket;
+// the clock comes from the caller to keep the script deterministic
+var take = redis.NewScript(`
+local count = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local ttl = tonumber(ARGV[4])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'last')
+local tokens = tonumber(state[1]) or count
+local last = tonumber(state[2]) or now
+
+if now > last then
+	tokens = math.min(count, tokens + (now - last) * rate)
+	last = now
+end
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HMSET', KEYS[1], 'tokens', tostring(tokens), 'last', tostring(last))
+redis.call('PEXPIRE', KEYS[1], ttl)
+
+return {allowed, tostring(tokens)}
+`)
+
+type redisStore struct {
+	client redis.UniversalClient
+}
+
+// NewRedisStore shares buckets through Redis, or any server speaking its protocol and Lua
+func NewRedisStore(client redis.UniversalClient) Store {
+	return &redisStore{client: client}
+}
+
+func (r redisStore) Take(ctx context.Context, key string, l Limit, now time.Time) (bool, float64, error) {
+	// an idle bucket is full again after one period, no need to keep it longer
+	ttl := (l.Period + time.Second).Milliseconds()
+	rate := l.perSecond() / 1000
+
+	reply, err := take.Run(ctx, r.client, []string{key}, l.Count, rate, now.UnixNano()/int64(time.Millisecond), ttl).Slice()
+	if err != nil {
+		return false, 0, err
+	}
+
+	allowed, _ := reply[0].(int64)
+	raw, _ := reply[1].(string)
+
+	tokens, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return false, 0, err
+	}
+
+	return allowed == 1, tokens, nil
+}
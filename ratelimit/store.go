@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Store keeps the token buckets
+type Store interface {
+	// Take removes a token from the bucket at key when there is one, and
+	// returns whether it did and how many tokens are left
+	Take(ctx context.Context, key string, l Limit, now time.Time) (bool, float64, error)
+}
+
+// sweepInterval how often full buckets are dropped from memory
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time
+}
+
+type memory struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+// NewMemoryStore keeps buckets in this process, for a single instance
+func NewMemoryStore() Store {
+	return &memory{buckets: map[string]*bucket{}}
+}
+
+func (m *memory) Take(_ context.Context, key string, l Limit, now time.Time) (bool, float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep(now)
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.Count), last: now}
+		m.buckets[key] = b
+	}
+
+	b.tokens = refill(b.tokens, b.last, now, l)
+	b.last = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	missing := float64(l.Count) - b.tokens
+	b.full = now.Add(time.Duration(missing / l.perSecond() * float64(time.Second)))
+
+	return allowed, b.tokens, nil
+}
+
+// sweep drops buckets that refilled completely, a new one is the same thing
+func (m *memory) sweep(now time.Time) {
+	if now.Sub(m.swept) < sweepInterval {
+		return
+	}
+
+	for key, b := range m.buckets {
+		if !now.Before(b.full) {
+			delete(m.buckets, key)
+		}
+	}
+
+	m.swept = now
+}
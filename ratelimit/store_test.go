@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+// checkStore runs the same scenario against every store
+func checkStore(t *testing.T, s Store) {
+	ctx := context.Background()
+	l := Limit{Count: 2, Period: 2 * time.Second}
+	now := time.Date(2020, 8, 7, 10, 0, 0, 0, time.UTC)
+
+	allowed, tokens, err := s.Take(ctx, "a", l, now)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, 1.0, tokens)
+
+	allowed, tokens, _ = s.Take(ctx, "a", l, now)
+	assert.True(t, allowed)
+	assert.Equal(t, 0.0, tokens)
+
+	allowed, _, _ = s.Take(ctx, "a", l, now)
+	assert.False(t, allowed, "the bucket is empty")
+
+	allowed, _, _ = s.Take(ctx, "b", l, now)
+	assert.True(t, allowed, "another key has its own bucket")
+
+	allowed, tokens, _ = s.Take(ctx, "a", l, now.Add(time.Second))
+	assert.True(t, allowed, "a second refills one token")
+	assert.Equal(t, 0.0, tokens)
+
+	allowed, tokens, _ = s.Take(ctx, "a", l, now.Add(time.Hour))
+	assert.True(t, allowed)
+	assert.Equal(t, 1.0, tokens, "the bucket never holds more than the limit")
+}
+
+func TestMemoryStore(t *testing.T) {
+	checkStore(t, NewMemoryStore())
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	s := NewMemoryStore().(*memory)
+	l := Limit{Count: 2, Period: time.Second}
+	now := time.Date(2020, 8, 7, 10, 0, 0, 0, time.UTC)
+
+	_, _, _ = s.Take(context.Background(), "a", l, now)
+	_, _, _ = s.Take(context.Background(), "b", l, now.Add(sweepInterval))
+
+	assert.Len(t, s.buckets, 1, "a refilled and was dropped")
+}
+
+func TestRedisStore(t *testing.T) {
+	server, err := miniredis.Run()
+	assert.NoError(t, err)
+	defer server.Close()
+
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	checkStore(t, NewRedisStore(client))
+
+	assert.True(t, server.TTL("a") > 0, "idle buckets expire")
+}
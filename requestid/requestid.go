@@ -0,0 +1,62 @@
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Header carrying the request ID in both directions
+const Header = "X-Request-ID"
+
+type contextKey struct{}
+
+// With stores the request ID
+func With(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// From returns the request ID, empty when missing
+func From(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// New random request ID
+func New() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// valid accepts short printable IDs only, anything else could forge log lines
+func valid(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+
+	for _, r := range id {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Middleware reuses the caller's X-Request-ID or generates one, and echoes it in the response
+func Middleware(c *gin.Context) {
+	id := c.GetHeader(Header)
+	if !valid(id) {
+		id = New()
+	}
+
+	c.Request = c.Request.WithContext(With(c.Request.Context(), id))
+	c.Header(Header, id)
+
+	c.Next()
+}
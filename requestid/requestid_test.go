@@ -0,0 +1,55 @@
+package requestid
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	type test struct {
+		name     string
+		header   string
+		generate bool
+	}
+
+	tests := []test{
+		{name: "when the caller sends an ID", header: "req-1"},
+		{name: "when the header is missing", generate: true},
+		{name: "when the header is too long", header: strings.Repeat("a", 129), generate: true},
+		{name: "when the header has spaces", header: "req 1", generate: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+
+			router := gin.New()
+			router.Use(Middleware)
+			router.GET("/", func(c *gin.Context) {
+				got = From(c.Request.Context())
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				req.Header.Set(Header, tt.header)
+			}
+			router.ServeHTTP(w, req)
+
+			if tt.generate {
+				assert.Len(t, got, 32)
+				assert.NotEqual(t, tt.header, got)
+			} else {
+				assert.Equal(t, tt.header, got)
+			}
+			assert.Equal(t, got, w.Header().Get(Header))
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"star-wars/api/handler"
+	"star-wars/auth"
+	"star-wars/logger"
+	"star-wars/rpc/planetpb"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Authentication of the calls with the authenticators and anonymous role of
+// the HTTP API, credentials go in the authorization and x-api-key metadata
+type Authentication struct {
+	Anonymous      auth.Role
+	Authenticators []auth.Authenticator
+}
+
+// credentials metadata handed to the authenticators as headers
+var credentials = []string{"Authorization", auth.APIKeyHeader}
+
+// writes of the PlanetService, the other methods require the reader role
+var writes = map[string]bool{
+	"Create": true,
+	"Delete": true,
+}
+
+// required role of a method, false for the ones left open: health and reflection
+func required(fullMethod string) (auth.Role, bool) {
+	prefix := "/" + planetpb.PlanetService_ServiceDesc.ServiceName + "/"
+
+	if !strings.HasPrefix(fullMethod, prefix) {
+		return "", false
+	}
+
+	if writes[strings.TrimPrefix(fullMethod, prefix)] {
+		return auth.RoleEditor, true
+	}
+
+	return auth.RoleReader, true
+}
+
+// authorize logs the caller in ctx and checks it may call the method
+func (a Authentication) authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	role, ok := required(fullMethod)
+	if !ok {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	header := http.Header{}
+	for _, name := range credentials {
+		for _, value := range md.Get(name) {
+			header.Add(name, value)
+		}
+	}
+
+	r := (&http.Request{Method: http.MethodPost, URL: &url.URL{Path: fullMethod}, Header: header}).WithContext(ctx)
+
+	p, err := auth.Authenticate(r, a.Anonymous, a.Authenticators...)
+
+	if errors.Is(err, auth.ErrInvalidCredentials) {
+		logger.FromContext(ctx).Info("authentication failed", zap.Error(err))
+		return ctx, handler.Unauthorized{Message: "authentication required"}
+	}
+
+	if err != nil {
+		return ctx, handler.InternalServer{Message: err.Error()}
+	}
+
+	ctx = auth.Login(ctx, p)
+	return ctx, auth.Check(ctx, role)
+}
+
+func (a Authentication) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, next grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := a.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return next(ctx, req)
+}
+
+func (a Authentication) stream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, next grpc.StreamHandler) error {
+	ctx, err := a.authorize(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return next(srv, loggedIn{ServerStream: stream, ctx: ctx})
+}
+
+// loggedIn stream whose context carries the caller
+type loggedIn struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s loggedIn) Context() context.Context {
+	return s.ctx
+}
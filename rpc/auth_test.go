@@ -0,0 +1,90 @@
+package rpc
+
+import (
+	"context"
+	"star-wars/audit"
+	"star-wars/auth"
+	"star-wars/entity"
+	"star-wars/planet/mock_planet"
+	"star-wars/rpc/planetpb"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthentication(t *testing.T) {
+	keys, err := auth.NewAPIKeys(map[string]string{
+		"ci":    "editor@ci-secret",
+		"stats": "reader@stats-secret",
+	})
+	assert.NoError(t, err)
+
+	withKey := func(key string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "x-api-key", key)
+	}
+
+	type test struct {
+		name      string
+		anonymous auth.Role
+		ctx       context.Context
+		write     bool
+		wantCode  codes.Code
+		wantActor string
+	}
+
+	tests := []test{
+		{name: "when an anonymous call reads with the anonymous role", anonymous: auth.RoleReader, ctx: context.Background(), wantCode: codes.OK},
+		{name: "when an anonymous call writes", anonymous: auth.RoleReader, ctx: context.Background(), write: true, wantCode: codes.Unauthenticated},
+		{name: "when anonymous calls are not allowed", ctx: context.Background(), wantCode: codes.Unauthenticated},
+		{name: "when an editor key writes", ctx: withKey("ci-secret"), write: true, wantCode: codes.OK, wantActor: "ci"},
+		{name: "when a reader key writes", ctx: withKey("stats-secret"), write: true, wantCode: codes.PermissionDenied},
+		{name: "when the key is unknown", anonymous: auth.RoleReader, ctx: withKey("guess"), wantCode: codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			srv := mock_planet.NewMockService(ctrl)
+			client := planetpb.NewPlanetServiceClient(dialAuthenticated(t, srv, &Authentication{Anonymous: tt.anonymous, Authenticators: []auth.Authenticator{keys}}))
+
+			var err error
+
+			if tt.write {
+				if tt.wantCode == codes.OK {
+					srv.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, _ *entity.Planet) error {
+						assert.Equal(t, tt.wantActor, audit.ActorFrom(ctx))
+						return nil
+					})
+				}
+				_, err = client.Create(tt.ctx, &planetpb.CreateRequest{Name: "Hoth", Climate: []string{"frozen"}, Terrain: []string{"tundra"}})
+			} else {
+				if tt.wantCode == codes.OK {
+					srv.EXPECT().FindAll(gomock.Any(), gomock.Any(), gomock.Any()).Return(named("Hoth"), nil)
+				}
+				var stream planetpb.PlanetService_ListStreamClient
+				stream, err = client.ListStream(tt.ctx, &planetpb.ListRequest{Limit: 1})
+				if err == nil {
+					_, err = stream.Recv()
+				}
+			}
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+
+	t.Run("health is left open", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		conn := dialAuthenticated(t, mock_planet.NewMockService(ctrl), &Authentication{Authenticators: []auth.Authenticator{keys}})
+
+		_, err := grpc_health_v1.NewHealthClient(conn).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,68 @@
+package rpc
+
+import (
+	"context"
+	"star-wars/api/handler"
+	"star-wars/logger"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// code of the status answering the handler error err
+func code(err error) codes.Code {
+	switch err.(type) {
+	case handler.BadRequest:
+		return codes.InvalidArgument
+	case handler.Unauthorized:
+		return codes.Unauthenticated
+	case handler.Forbidden:
+		return codes.PermissionDenied
+	case handler.NotFound:
+		return codes.NotFound
+	case handler.PreconditionFailed:
+		// a failed test-and-set, the client reads again and retries
+		return codes.Aborted
+	case handler.TooManyRequests:
+		return codes.ResourceExhausted
+	}
+
+	switch err {
+	case context.DeadlineExceeded:
+		return codes.DeadlineExceeded
+	case context.Canceled:
+		return codes.Canceled
+	}
+
+	return codes.Internal
+}
+
+// toStatus converts the errors the services return, as handler.ResponseError
+// does for HTTP
+func toStatus(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	c := code(err)
+	if c == codes.Internal {
+		logger.FromContext(ctx).Error(err.Error(), zap.Error(handler.Cause(err)))
+	}
+
+	return status.Error(c, err.Error())
+}
+
+func unaryErrors(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, next grpc.UnaryHandler) (interface{}, error) {
+	resp, err := next(ctx, req)
+	return resp, toStatus(ctx, err)
+}
+
+func streamErrors(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, next grpc.StreamHandler) error {
+	return toStatus(stream.Context(), next(srv, stream))
+}
@@ -0,0 +1,325 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: rpc/planetpb/planet.proto
+
+package planetpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// PlanetServiceClient is the client API for PlanetService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PlanetServiceClient interface {
+	// List a page of planets
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+	// ListStream streams the planets from skip on, limit 0 streams them all
+	ListStream(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (PlanetService_ListStreamClient, error)
+	// Get a planet by id
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Planet, error)
+	// GetByName a planet by its exact name
+	GetByName(ctx context.Context, in *GetByNameRequest, opts ...grpc.CallOption) (*Planet, error)
+	// Create a planet, its films counted from SWAPI
+	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*Planet, error)
+	// Delete a planet, it can be restored over HTTP until purged
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+}
+
+type planetServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPlanetServiceClient(cc grpc.ClientConnInterface) PlanetServiceClient {
+	return &planetServiceClient{cc}
+}
+
+func (c *planetServiceClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
+	out := new(ListResponse)
+	err := c.cc.Invoke(ctx, "/starwars.planet.v1.PlanetService/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *planetServiceClient) ListStream(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (PlanetService_ListStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PlanetService_ServiceDesc.Streams[0], "/starwars.planet.v1.PlanetService/ListStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &planetServiceListStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PlanetService_ListStreamClient interface {
+	Recv() (*Planet, error)
+	grpc.ClientStream
+}
+
+type planetServiceListStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *planetServiceListStreamClient) Recv() (*Planet, error) {
+	m := new(Planet)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *planetServiceClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Planet, error) {
+	out := new(Planet)
+	err := c.cc.Invoke(ctx, "/starwars.planet.v1.PlanetService/Get", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *planetServiceClient) GetByName(ctx context.Context, in *GetByNameRequest, opts ...grpc.CallOption) (*Planet, error) {
+	out := new(Planet)
+	err := c.cc.Invoke(ctx, "/starwars.planet.v1.PlanetService/GetByName", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *planetServiceClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*Planet, error) {
+	out := new(Planet)
+	err := c.cc.Invoke(ctx, "/starwars.planet.v1.PlanetService/Create", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *planetServiceClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/starwars.planet.v1.PlanetService/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PlanetServiceServer is the server API for PlanetService service.
+// All implementations must embed UnimplementedPlanetServiceServer
+// for forward compatibility
+type PlanetServiceServer interface {
+	// List a page of planets
+	List(context.Context, *ListRequest) (*ListResponse, error)
+	// ListStream streams the planets from skip on, limit 0 streams them all
+	ListStream(*ListRequest, PlanetService_ListStreamServer) error
+	// Get a planet by id
+	Get(context.Context, *GetRequest) (*Planet, error)
+	// GetByName a planet by its exact name
+	GetByName(context.Context, *GetByNameRequest) (*Planet, error)
+	// Create a planet, its films counted from SWAPI
+	Create(context.Context, *CreateRequest) (*Planet, error)
+	// Delete a planet, it can be restored over HTTP until purged
+	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	mustEmbedUnimplementedPlanetServiceServer()
+}
+
+// UnimplementedPlanetServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedPlanetServiceServer struct {
+}
+
+func (UnimplementedPlanetServiceServer) List(context.Context, *ListRequest) (*ListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedPlanetServiceServer) ListStream(*ListRequest, PlanetService_ListStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListStream not implemented")
+}
+func (UnimplementedPlanetServiceServer) Get(context.Context, *GetRequest) (*Planet, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (UnimplementedPlanetServiceServer) GetByName(context.Context, *GetByNameRequest) (*Planet, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetByName not implemented")
+}
+func (UnimplementedPlanetServiceServer) Create(context.Context, *CreateRequest) (*Planet, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (UnimplementedPlanetServiceServer) Delete(context.Context, *DeleteRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedPlanetServiceServer) mustEmbedUnimplementedPlanetServiceServer() {}
+
+// UnsafePlanetServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PlanetServiceServer will
+// result in compilation errors.
+type UnsafePlanetServiceServer interface {
+	mustEmbedUnimplementedPlanetServiceServer()
+}
+
+func RegisterPlanetServiceServer(s grpc.ServiceRegistrar, srv PlanetServiceServer) {
+	s.RegisterService(&PlanetService_ServiceDesc, srv)
+}
+
+func _PlanetService_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlanetServiceServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/starwars.planet.v1.PlanetService/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlanetServiceServer).List(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlanetService_ListStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PlanetServiceServer).ListStream(m, &planetServiceListStreamServer{stream})
+}
+
+type PlanetService_ListStreamServer interface {
+	Send(*Planet) error
+	grpc.ServerStream
+}
+
+type planetServiceListStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *planetServiceListStreamServer) Send(m *Planet) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _PlanetService_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlanetServiceServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/starwars.planet.v1.PlanetService/Get",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlanetServiceServer).Get(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlanetService_GetByName_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetByNameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlanetServiceServer).GetByName(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/starwars.planet.v1.PlanetService/GetByName",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlanetServiceServer).GetByName(ctx, req.(*GetByNameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlanetService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlanetServiceServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/starwars.planet.v1.PlanetService/Create",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlanetServiceServer).Create(ctx, req.(*CreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlanetService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlanetServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/starwars.planet.v1.PlanetService/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlanetServiceServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PlanetService_ServiceDesc is the grpc.ServiceDesc for PlanetService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PlanetService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "starwars.planet.v1.PlanetService",
+	HandlerType: (*PlanetServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "List",
+			Handler:    _PlanetService_List_Handler,
+		},
+		{
+			MethodName: "Get",
+			Handler:    _PlanetService_Get_Handler,
+		},
+		{
+			MethodName: "GetByName",
+			Handler:    _PlanetService_GetByName_Handler,
+		},
+		{
+			MethodName: "Create",
+			Handler:    _PlanetService_Create_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _PlanetService_Delete_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListStream",
+			Handler:       _PlanetService_ListStream_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "rpc/planetpb/planet.proto",
+}
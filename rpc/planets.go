@@ -0,0 +1,162 @@
+package rpc
+
+import (
+	"context"
+	"star-wars/api/handler"
+	"star-wars/entity"
+	"star-wars/env"
+	"star-wars/planet"
+	"star-wars/rpc/planetpb"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// defaultLimit of List, the same as GET /planets
+	defaultLimit = 3
+	// streamPage planets ListStream reads from the database at a time
+	streamPage = 100
+)
+
+type planets struct {
+	planetpb.UnimplementedPlanetServiceServer
+	srv planet.Service
+}
+
+// NewPlanets returns the PlanetService over srv
+func NewPlanets(srv planet.Service) planetpb.PlanetServiceServer {
+	return &planets{srv: srv}
+}
+
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func toProto(p *entity.Planet) *planetpb.Planet {
+	return &planetpb.Planet{
+		Id:         p.ID,
+		Name:       p.Name,
+		Climate:    p.Climate,
+		Terrain:    p.Terrain,
+		TotalFilms: int32(p.TotalFilms),
+		CreatedAt:  timestamp(p.CreatedAt),
+		UpdatedAt:  timestamp(p.UpdatedAt),
+		Version:    p.Version,
+	}
+}
+
+func (p planets) List(ctx context.Context, req *planetpb.ListRequest) (*planetpb.ListResponse, error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultLimit
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, env.Vars.Timeouts.Read)
+	defer cancel()
+
+	found, err := p.srv.FindAll(ctx, limit, req.Skip)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &planetpb.ListResponse{Planets: make([]*planetpb.Planet, len(*found))}
+	for i := range *found {
+		resp.Planets[i] = toProto(&(*found)[i])
+	}
+	return resp, nil
+}
+
+func (p planets) ListStream(req *planetpb.ListRequest, stream planetpb.PlanetService_ListStreamServer) error {
+	skip, remaining := req.Skip, req.Limit
+
+	for {
+		size := int64(streamPage)
+		if req.Limit > 0 && remaining < size {
+			size = remaining
+		}
+
+		// each page gets the read budget, the stream lasts as long as the client reads
+		ctx, cancel := context.WithTimeout(stream.Context(), env.Vars.Timeouts.Read)
+		found, err := p.srv.FindAll(ctx, size, skip)
+		cancel()
+
+		if err != nil {
+			return err
+		}
+
+		for i := range *found {
+			if err := stream.Send(toProto(&(*found)[i])); err != nil {
+				return err
+			}
+		}
+
+		read := int64(len(*found))
+		skip, remaining = skip+read, remaining-read
+
+		if read < size || (req.Limit > 0 && remaining == 0) {
+			return nil
+		}
+	}
+}
+
+func (p planets) Get(ctx context.Context, req *planetpb.GetRequest) (*planetpb.Planet, error) {
+	ctx, cancel := context.WithTimeout(ctx, env.Vars.Timeouts.Read)
+	defer cancel()
+
+	found, err := p.srv.FindByID(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return toProto(found), nil
+}
+
+func (p planets) GetByName(ctx context.Context, req *planetpb.GetByNameRequest) (*planetpb.Planet, error) {
+	ctx, cancel := context.WithTimeout(ctx, env.Vars.Timeouts.Read)
+	defer cancel()
+
+	found, err := p.srv.FindByName(ctx, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	return toProto(found), nil
+}
+
+func (p planets) Create(ctx context.Context, req *planetpb.CreateRequest) (*planetpb.Planet, error) {
+	planet := entity.Planet{
+		Name:    req.Name,
+		Climate: entity.NewTerms(req.Climate...),
+		Terrain: entity.NewTerms(req.Terrain...),
+	}
+
+	if err := planet.Validate(); err != nil {
+		return nil, handler.BadRequest{Message: err.Error()}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, env.Vars.Timeouts.Write)
+	defer cancel()
+
+	if err := p.srv.Save(ctx, &planet); err != nil {
+		return nil, err
+	}
+	return toProto(&planet), nil
+}
+
+func (p planets) Delete(ctx context.Context, req *planetpb.DeleteRequest) (*planetpb.DeleteResponse, error) {
+	// versions start at 1, the zero value means the client sent none
+	version := req.Version
+	if version == 0 {
+		version = handler.AnyVersion
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, env.Vars.Timeouts.Write)
+	defer cancel()
+
+	if err := p.srv.Delete(ctx, req.Id, version); err != nil {
+		return nil, err
+	}
+	return &planetpb.DeleteResponse{}, nil
+}
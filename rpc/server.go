@@ -0,0 +1,72 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"star-wars/planet"
+	"star-wars/rpc/planetpb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// Server gRPC server with the PlanetService, health and reflection
+type Server struct {
+	GRPC   *grpc.Server
+	health *health.Server
+}
+
+// NewServer returns a server over the planets service; calls are
+// authenticated unless authn is nil, as while auth is disabled
+func NewServer(srv planet.Service, authn *Authentication) *Server {
+	unary := []grpc.UnaryServerInterceptor{unaryErrors}
+	stream := []grpc.StreamServerInterceptor{streamErrors}
+
+	if authn != nil {
+		unary = append(unary, authn.unary)
+		stream = append(stream, authn.stream)
+	}
+
+	s := &Server{
+		GRPC: grpc.NewServer(
+			grpc.ChainUnaryInterceptor(unary...),
+			grpc.ChainStreamInterceptor(stream...),
+		),
+		health: health.NewServer(),
+	}
+
+	planetpb.RegisterPlanetServiceServer(s.GRPC, NewPlanets(srv))
+	grpc_health_v1.RegisterHealthServer(s.GRPC, s.health)
+	reflection.Register(s.GRPC)
+
+	s.health.SetServingStatus(planetpb.PlanetService_ServiceDesc.ServiceName, grpc_health_v1.HealthCheckResponse_SERVING)
+
+	return s
+}
+
+// Serve accepts connections on lis until Shutdown
+func (s *Server) Serve(lis net.Listener) error {
+	return s.GRPC.Serve(lis)
+}
+
+// Shutdown reports not serving and waits for the calls in flight, cutting
+// them when ctx ends; it fits api.Server.OnShutdown
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.health.Shutdown()
+
+	done := make(chan struct{})
+	go func() {
+		s.GRPC.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.GRPC.Stop()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,243 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"star-wars/api/handler"
+	"star-wars/entity"
+	"star-wars/planet/mock_planet"
+	"star-wars/rpc/planetpb"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dial serves srv in memory and returns a connection to it
+func dial(t *testing.T, srv *mock_planet.MockService) *grpc.ClientConn {
+	return dialAuthenticated(t, srv, nil)
+}
+
+// dialAuthenticated as dial, with the calls authenticated by authn
+func dialAuthenticated(t *testing.T, srv *mock_planet.MockService, authn *Authentication) *grpc.ClientConn {
+	lis := bufconn.Listen(1 << 20)
+	s := NewServer(srv, authn)
+	go s.Serve(lis)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithInsecure(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+		s.Shutdown(context.Background())
+	})
+
+	return conn
+}
+
+func named(names ...string) *[]entity.Planet {
+	found := []entity.Planet{}
+	for _, name := range names {
+		found = append(found, entity.Planet{Name: name})
+	}
+	return &found
+}
+
+func TestPlanets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	srv := mock_planet.NewMockService(ctrl)
+	client := planetpb.NewPlanetServiceClient(dial(t, srv))
+	ctx := context.Background()
+
+	t.Run("list", func(t *testing.T) {
+		srv.EXPECT().FindAll(gomock.Any(), int64(3), int64(0)).Return(named("Alderaan", "Hoth"), nil)
+
+		resp, err := client.List(ctx, &planetpb.ListRequest{})
+
+		assert.NoError(t, err)
+		assert.Len(t, resp.Planets, 2)
+		assert.Equal(t, "Hoth", resp.Planets[1].Name)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		created := time.Date(2020, 8, 7, 10, 0, 0, 0, time.UTC)
+		srv.EXPECT().FindByID(gomock.Any(), "5f2c891e9a9e070b1ef2e28c").Return(&entity.Planet{
+			ID:         "5f2c891e9a9e070b1ef2e28c",
+			Name:       "Alderaan",
+			Climate:    entity.Terms{"temperate"},
+			Terrain:    entity.Terms{"grasslands", "mountains"},
+			TotalFilms: 2,
+			CreatedAt:  &created,
+			Version:    3,
+		}, nil)
+
+		p, err := client.Get(ctx, &planetpb.GetRequest{Id: "5f2c891e9a9e070b1ef2e28c"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Alderaan", p.Name)
+		assert.Equal(t, []string{"grasslands", "mountains"}, p.Terrain)
+		assert.Equal(t, int32(2), p.TotalFilms)
+		assert.Equal(t, created, p.CreatedAt.AsTime())
+		assert.Nil(t, p.UpdatedAt)
+		assert.Equal(t, int64(3), p.Version)
+	})
+
+	t.Run("create validates the planet", func(t *testing.T) {
+		_, err := client.Create(ctx, &planetpb.CreateRequest{Name: "Hoth"})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("create", func(t *testing.T) {
+		srv.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, p *entity.Planet) error {
+			p.ID, p.Version = "5f2c891e9a9e070b1ef2e28c", 1
+			return nil
+		})
+
+		p, err := client.Create(ctx, &planetpb.CreateRequest{Name: "Hoth", Climate: []string{"frozen"}, Terrain: []string{"tundra"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "5f2c891e9a9e070b1ef2e28c", p.Id)
+		assert.Equal(t, []string{"frozen"}, p.Climate)
+	})
+
+	t.Run("create normalizes climate and terrain", func(t *testing.T) {
+		srv.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, p *entity.Planet) error {
+			assert.Equal(t, entity.Terms{"temperate"}, p.Climate)
+			assert.Equal(t, entity.Terms{"tundra", "ice caves"}, p.Terrain)
+			return nil
+		})
+
+		p, err := client.Create(ctx, &planetpb.CreateRequest{Name: "Hoth", Climate: []string{"Temperate"}, Terrain: []string{" tundra", "Ice Caves ", ""}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"temperate"}, p.Climate)
+	})
+
+	t.Run("delete any version", func(t *testing.T) {
+		srv.EXPECT().Delete(gomock.Any(), "5f2c891e9a9e070b1ef2e28c", handler.AnyVersion).Return(nil)
+
+		_, err := client.Delete(ctx, &planetpb.DeleteRequest{Id: "5f2c891e9a9e070b1ef2e28c"})
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestListStream(t *testing.T) {
+	type test struct {
+		name  string
+		limit int64
+		pages []*[]entity.Planet
+		want  int
+	}
+
+	page := func(n int) *[]entity.Planet {
+		names := make([]string, n)
+		return named(names...)
+	}
+
+	tests := []test{
+		{name: "streams every page", pages: []*[]entity.Planet{page(streamPage), page(streamPage), page(1)}, want: 2*streamPage + 1},
+		{name: "stops at a full last page", pages: []*[]entity.Planet{page(streamPage), page(0)}, want: streamPage},
+		{name: "stops at the limit", limit: streamPage + 2, pages: []*[]entity.Planet{page(streamPage), page(2)}, want: streamPage + 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			srv := mock_planet.NewMockService(ctrl)
+
+			skip, remaining := int64(5), tt.limit
+			var calls []*gomock.Call
+			for _, p := range tt.pages {
+				size := int64(streamPage)
+				if tt.limit > 0 && remaining < size {
+					size = remaining
+				}
+				calls = append(calls, srv.EXPECT().FindAll(gomock.Any(), size, skip).Return(p, nil))
+				skip += int64(len(*p))
+				remaining -= int64(len(*p))
+			}
+			gomock.InOrder(calls...)
+
+			stream, err := planetpb.NewPlanetServiceClient(dial(t, srv)).ListStream(context.Background(), &planetpb.ListRequest{Limit: tt.limit, Skip: 5})
+			assert.NoError(t, err)
+
+			received := 0
+			for {
+				_, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				assert.NoError(t, err)
+				received++
+			}
+
+			assert.Equal(t, tt.want, received)
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	type test struct {
+		name string
+		err  error
+		want codes.Code
+	}
+
+	tests := []test{
+		{name: "bad request", err: handler.BadRequest{Message: "id is invalid"}, want: codes.InvalidArgument},
+		{name: "not found", err: handler.NotFound{Message: "planet not found"}, want: codes.NotFound},
+		{name: "precondition failed", err: handler.PreconditionFailed{Message: "planet was modified"}, want: codes.Aborted},
+		{name: "internal", err: handler.InternalServer{Message: "connection refused"}, want: codes.Internal},
+		{name: "deadline", err: context.DeadlineExceeded, want: codes.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			srv := mock_planet.NewMockService(ctrl)
+			srv.EXPECT().FindByName(gomock.Any(), "Hoth").Return(nil, tt.err)
+			srv.EXPECT().FindAll(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, tt.err)
+
+			client := planetpb.NewPlanetServiceClient(dial(t, srv))
+
+			_, err := client.GetByName(context.Background(), &planetpb.GetByNameRequest{Name: "Hoth"})
+			assert.Equal(t, tt.want, status.Code(err))
+			assert.Equal(t, tt.err.Error(), status.Convert(err).Message())
+
+			stream, err := client.ListStream(context.Background(), &planetpb.ListRequest{})
+			assert.NoError(t, err)
+			_, err = stream.Recv()
+			assert.Equal(t, tt.want, status.Code(err), "streams map errors too")
+		})
+	}
+}
+
+func TestHealth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := grpc_health_v1.NewHealthClient(dial(t, mock_planet.NewMockService(ctrl)))
+
+	for _, service := range []string{"", "starwars.planet.v1.PlanetService"} {
+		resp, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: service})
+
+		assert.NoError(t, err)
+		assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, resp.Status)
+	}
+}
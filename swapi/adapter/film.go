@@ -0,0 +1,14 @@
+package adapter
+
+// Film adapter
+type Film struct {
+	Title        string   `json:"title"`
+	EpisodeID    int      `json:"episode_id"`
+	OpeningCrawl string   `json:"opening_crawl"`
+	Director     string   `json:"director"`
+	Producer     string   `json:"producer"`
+	ReleaseDate  string   `json:"release_date"`
+	Characters   []string `json:"characters"`
+	Planets      []string `json:"planets"`
+	URL          string   `json:"url"`
+}
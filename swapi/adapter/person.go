@@ -0,0 +1,13 @@
+package adapter
+
+// Person adapter
+type Person struct {
+	Name      string   `json:"name"`
+	Height    string   `json:"height"`
+	Mass      string   `json:"mass"`
+	BirthYear string   `json:"birth_year"`
+	Gender    string   `json:"gender"`
+	Homeworld string   `json:"homeworld"`
+	Films     []string `json:"films"`
+	URL       string   `json:"url"`
+}
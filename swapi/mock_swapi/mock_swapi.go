@@ -5,6 +5,7 @@
 package mock_swapi
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
 	adapter "star-wars/swapi/adapter"
@@ -34,16 +35,44 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 }
 
 // GetPlanet mocks base method
-func (m *MockService) GetPlanet(name string) (adapter.Planets, error) {
+func (m *MockService) GetPlanet(ctx context.Context, name string) (adapter.Planets, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPlanet", name)
+	ret := m.ctrl.Call(m, "GetPlanet", ctx, name)
 	ret0, _ := ret[0].(adapter.Planets)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPlanet indicates an expected call of GetPlanet
-func (mr *MockServiceMockRecorder) GetPlanet(name interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) GetPlanet(ctx, name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlanet", reflect.TypeOf((*MockService)(nil).GetPlanet), name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlanet", reflect.TypeOf((*MockService)(nil).GetPlanet), ctx, name)
+}
+
+// Fetch mocks base method
+func (m *MockService) Fetch(ctx context.Context, url string, v interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fetch", ctx, url, v)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Fetch indicates an expected call of Fetch
+func (mr *MockServiceMockRecorder) Fetch(ctx, url, v interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockService)(nil).Fetch), ctx, url, v)
+}
+
+// Ping mocks base method
+func (m *MockService) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping
+func (mr *MockServiceMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockService)(nil).Ping), ctx)
 }
@@ -0,0 +1,54 @@
+package swapi
+
+import (
+	"context"
+	"net/http"
+	"star-wars/metrics"
+	"star-wars/swapi/adapter"
+	"strconv"
+	"time"
+)
+
+type instrumented struct {
+	next Service
+}
+
+// NewInstrumented records latency and status of every call of s
+func NewInstrumented(s Service) Service {
+	return &instrumented{
+		next: s,
+	}
+}
+
+func status(err error) string {
+	if err == nil {
+		return strconv.Itoa(http.StatusOK)
+	}
+
+	if e, ok := err.(StatusError); ok {
+		return strconv.Itoa(e.Code)
+	}
+
+	return "error"
+}
+
+func (i instrumented) GetPlanet(ctx context.Context, name string) (adapter.Planets, error) {
+	start := time.Now()
+	planets, err := i.next.GetPlanet(ctx, name)
+	metrics.Swapi("get_planet", status(err), start)
+	return planets, err
+}
+
+func (i instrumented) Fetch(ctx context.Context, url string, v interface{}) error {
+	start := time.Now()
+	err := i.next.Fetch(ctx, url, v)
+	metrics.Swapi("fetch", status(err), start)
+	return err
+}
+
+func (i instrumented) Ping(ctx context.Context) error {
+	start := time.Now()
+	err := i.next.Ping(ctx)
+	metrics.Swapi("ping", status(err), start)
+	return err
+}
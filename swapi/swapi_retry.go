@@ -0,0 +1,103 @@
+package swapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"star-wars/metrics"
+	"star-wars/swapi/adapter"
+	"syscall"
+	"time"
+)
+
+type retry struct {
+	next     Service
+	attempts int
+	backoff  time.Duration
+}
+
+// NewRetry repeats failed planet lookups up to attempts times, doubling the backoff
+func NewRetry(s Service, attempts int, backoff time.Duration) Service {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	return &retry{
+		next:     s,
+		attempts: attempts,
+		backoff:  backoff,
+	}
+}
+
+// transient causes of a network failure, the connection may work next time
+var transient = []error{syscall.ECONNREFUSED, syscall.ECONNRESET, syscall.EPIPE, io.EOF, io.ErrUnexpectedEOF}
+
+// retryable timeouts, dropped connections, throttling and server errors; an
+// answer that cannot be decoded, a malformed URL, an unsupported protocol or a
+// link that is not SWAPI fails the same way again
+func retryable(err error) bool {
+	var status StatusError
+	if errors.As(err, &status) {
+		return status.Code == http.StatusTooManyRequests || status.Code >= http.StatusInternalServerError
+	}
+
+	var network net.Error
+	if errors.As(err, &network) && network.Timeout() {
+		return true
+	}
+
+	for _, cause := range transient {
+		if errors.Is(err, cause) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r retry) GetPlanet(ctx context.Context, name string) (adapter.Planets, error) {
+	var planets adapter.Planets
+
+	err := r.do(ctx, "get_planet", func() error {
+		var err error
+		planets, err = r.next.GetPlanet(ctx, name)
+		return err
+	})
+
+	return planets, err
+}
+
+func (r retry) Fetch(ctx context.Context, url string, v interface{}) error {
+	return r.do(ctx, "fetch", func() error {
+		return r.next.Fetch(ctx, url, v)
+	})
+}
+
+// do calls attempt until it succeeds, fails for good or runs out of attempts
+func (r retry) do(ctx context.Context, operation string, attempt func() error) error {
+	wait := r.backoff
+
+	for n := 1; ; n++ {
+		err := attempt()
+
+		if err == nil || n == r.attempts || !retryable(err) {
+			return err
+		}
+
+		metrics.SwapiRetry(operation)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+
+		wait *= 2
+	}
+}
+
+func (r retry) Ping(ctx context.Context) error {
+	return r.next.Ping(ctx)
+}
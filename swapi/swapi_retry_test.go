@@ -0,0 +1,151 @@
+package swapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"star-wars/metrics"
+	"star-wars/swapi/adapter"
+	"star-wars/swapi/mock_swapi"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry(t *testing.T) {
+	t.Run("when a retryable failure recovers", func(t *testing.T) {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		s := mock_swapi.NewMockService(c)
+
+		gomock.InOrder(
+			s.EXPECT().GetPlanet(context.Background(), "Tatooine").Return(adapter.Planets{}, &url.Error{Op: "Get", URL: "https://swapi.dev/api/planets/", Err: &net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}}),
+			s.EXPECT().GetPlanet(context.Background(), "Tatooine").Return(adapter.Planets{}, StatusError{Code: 503}),
+			s.EXPECT().GetPlanet(context.Background(), "Tatooine").Return(adapter.Planets{Count: 1}, nil),
+		)
+
+		planets, err := NewRetry(s, 3, 0).GetPlanet(context.Background(), "Tatooine")
+
+		assert.Nil(t, err)
+		assert.Equal(t, int32(1), planets.Count)
+
+		expected := `
+# HELP swapi_retries_total SWAPI calls repeated after a failure.
+# TYPE swapi_retries_total counter
+swapi_retries_total{operation="get_planet"} 2
+`
+		assert.Nil(t, testutil.GatherAndCompare(metrics.Registry, strings.NewReader(expected), "swapi_retries_total"))
+	})
+
+	t.Run("when attempts run out", func(t *testing.T) {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		s := mock_swapi.NewMockService(c)
+		s.EXPECT().GetPlanet(context.Background(), "Tatooine").Return(adapter.Planets{}, StatusError{Code: 502}).Times(2)
+
+		_, err := NewRetry(s, 2, 0).GetPlanet(context.Background(), "Tatooine")
+
+		assert.Equal(t, StatusError{Code: 502}, err)
+	})
+
+	t.Run("when the failure is not retryable", func(t *testing.T) {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		s := mock_swapi.NewMockService(c)
+		s.EXPECT().GetPlanet(context.Background(), "Tatooine").Return(adapter.Planets{}, StatusError{Code: 404})
+
+		_, err := NewRetry(s, 3, 0).GetPlanet(context.Background(), "Tatooine")
+
+		assert.Equal(t, "swapi answered 404", err.Error())
+	})
+
+	t.Run("when the request timed out", func(t *testing.T) {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		s := mock_swapi.NewMockService(c)
+		gomock.InOrder(
+			s.EXPECT().GetPlanet(context.Background(), "Tatooine").Return(adapter.Planets{}, &url.Error{Op: "Get", URL: "https://swapi.dev/api/planets/", Err: &net.DNSError{Err: "i/o timeout", IsTimeout: true}}),
+			s.EXPECT().GetPlanet(context.Background(), "Tatooine").Return(adapter.Planets{Count: 1}, nil),
+		)
+
+		_, err := NewRetry(s, 3, 0).GetPlanet(context.Background(), "Tatooine")
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("when the request can never succeed", func(t *testing.T) {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		s := mock_swapi.NewMockService(c)
+		permanent := &url.Error{Op: "Get", URL: "ftp://swapi.dev/api/planets/", Err: errors.New(`unsupported protocol scheme "ftp"`)}
+		s.EXPECT().GetPlanet(context.Background(), "Tatooine").Return(adapter.Planets{}, permanent)
+
+		_, err := NewRetry(s, 3, 0).GetPlanet(context.Background(), "Tatooine")
+
+		assert.Equal(t, permanent, err)
+	})
+
+	t.Run("when the answer cannot be decoded", func(t *testing.T) {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		s := mock_swapi.NewMockService(c)
+		s.EXPECT().GetPlanet(context.Background(), "Tatooine").Return(adapter.Planets{}, &json.SyntaxError{})
+
+		_, err := NewRetry(s, 3, 0).GetPlanet(context.Background(), "Tatooine")
+
+		assert.Equal(t, &json.SyntaxError{}, err)
+	})
+
+	t.Run("when the body is cut short", func(t *testing.T) {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		s := mock_swapi.NewMockService(c)
+		gomock.InOrder(
+			s.EXPECT().GetPlanet(context.Background(), "Tatooine").Return(adapter.Planets{}, io.ErrUnexpectedEOF),
+			s.EXPECT().GetPlanet(context.Background(), "Tatooine").Return(adapter.Planets{Count: 1}, nil),
+		)
+
+		_, err := NewRetry(s, 3, 0).GetPlanet(context.Background(), "Tatooine")
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("when the link is not swapi", func(t *testing.T) {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		s := mock_swapi.NewMockService(c)
+		s.EXPECT().Fetch(context.Background(), "https://example.com/", nil).Return(errors.New("https://example.com/ is not a swapi resource"))
+
+		err := NewRetry(s, 3, 0).Fetch(context.Background(), "https://example.com/", nil)
+
+		assert.EqualError(t, err, "https://example.com/ is not a swapi resource")
+	})
+
+	t.Run("fetch is retried too", func(t *testing.T) {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		s := mock_swapi.NewMockService(c)
+
+		var film adapter.Film
+		gomock.InOrder(
+			s.EXPECT().Fetch(context.Background(), "https://swapi.dev/api/films/1/", &film).Return(StatusError{Code: 429}),
+			s.EXPECT().Fetch(context.Background(), "https://swapi.dev/api/films/1/", &film).Return(nil),
+		)
+
+		assert.Nil(t, NewRetry(s, 3, 0).Fetch(context.Background(), "https://swapi.dev/api/films/1/", &film))
+	})
+}
+
+func TestStatus(t *testing.T) {
+	assert.Equal(t, "200", status(nil))
+	assert.Equal(t, "503", status(StatusError{Code: 503}))
+	assert.Equal(t, "error", status(errors.New("timeout")))
+}
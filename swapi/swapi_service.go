@@ -1,19 +1,40 @@
 package swapi
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"fmt"
 	"net/http"
 	"net/url"
 	"star-wars/env"
+	"star-wars/logger"
 	"star-wars/swapi/adapter"
+	"star-wars/tracing"
+	"strings"
+
+	"go.uber.org/zap"
 )
 
 // Service contract
 type Service interface {
-	GetPlanet(name string) (adapter.Planets, error)
+	GetPlanet(ctx context.Context, name string) (adapter.Planets, error)
+	// Fetch decodes the resource at url, one of the links SWAPI answers, into v
+	Fetch(ctx context.Context, url string, v interface{}) error
+	Ping(ctx context.Context) error
+}
+
+// StatusError SWAPI answered with an unexpected status
+type StatusError struct {
+	Code int
 }
 
+func (e StatusError) Error() string {
+	return fmt.Sprintf("swapi answered %d", e.Code)
+}
+
+// client traces every call to SWAPI
+var client = &http.Client{Transport: tracing.Transport(http.DefaultTransport)}
+
 type swapi struct{}
 
 // New returns a swapi service instance
@@ -21,22 +42,68 @@ func New() Service {
 	return &swapi{}
 }
 
-func (s swapi) GetPlanet(name string) (adapter.Planets, error) {
+func (s swapi) GetPlanet(ctx context.Context, name string) (adapter.Planets, error) {
 	var adapter adapter.Planets
+	err := get(ctx, env.Vars.Swapi.Url+"/planets/?search="+url.QueryEscape(name), &adapter)
+	return adapter, err
+}
 
-	resp, err := http.Get(env.Vars.Swapi.Url + "/planets/?search=" + url.QueryEscape(name))
+func (s swapi) Fetch(ctx context.Context, resource string, v interface{}) error {
+	// links come from SWAPI answers, never follow one elsewhere
+	if !strings.HasPrefix(resource, env.Vars.Swapi.Url+"/") {
+		return fmt.Errorf("%s is not a swapi resource", resource)
+	}
+	return get(ctx, resource, v)
+}
 
+func get(ctx context.Context, resource string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, resource, nil)
 	if err != nil {
-		log.Print(err)
-		return adapter, err
+		return err
 	}
 
-	err = json.NewDecoder(resp.Body).Decode(&adapter)
+	resp, err := client.Do(req.WithContext(ctx))
 
 	if err != nil {
-		log.Print(err)
-		return adapter, err
+		logger.FromContext(ctx).Warn("swapi request failed", zap.Error(err))
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err := StatusError{Code: resp.StatusCode}
+		logger.FromContext(ctx).Warn("swapi request failed", zap.Error(err))
+		return err
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(v)
+
+	if err != nil {
+		logger.FromContext(ctx).Warn("swapi response is invalid", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// Ping checks SWAPI answers
+func (s swapi) Ping(ctx context.Context) error {
+	req, err := http.NewRequest(http.MethodGet, env.Vars.Swapi.Url+"/", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return StatusError{Code: resp.StatusCode}
 	}
 
-	return adapter, nil
+	return nil
 }
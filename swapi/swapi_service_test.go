@@ -0,0 +1,53 @@
+package swapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"star-wars/env"
+	"star-wars/swapi/adapter"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/films/1/":
+			w.Write([]byte(`{"title":"A New Hope","episode_id":4}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	previous := env.Vars.Swapi.Url
+	env.Vars.Swapi.Url = server.URL + "/api"
+	defer func() { env.Vars.Swapi.Url = previous }()
+
+	t.Run("decodes the resource", func(t *testing.T) {
+		var film adapter.Film
+
+		err := New().Fetch(context.Background(), server.URL+"/api/films/1/", &film)
+
+		assert.Nil(t, err)
+		assert.Equal(t, adapter.Film{Title: "A New Hope", EpisodeID: 4}, film)
+	})
+
+	t.Run("when the resource is missing", func(t *testing.T) {
+		var film adapter.Film
+
+		err := New().Fetch(context.Background(), server.URL+"/api/films/99/", &film)
+
+		assert.Equal(t, StatusError{Code: 404}, err)
+	})
+
+	t.Run("when the url is not swapi", func(t *testing.T) {
+		var film adapter.Film
+
+		err := New().Fetch(context.Background(), "http://internal.example.com/api/films/1/", &film)
+
+		assert.EqualError(t, err, "http://internal.example.com/api/films/1/ is not a swapi resource")
+	})
+}
@@ -0,0 +1,105 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"star-wars/env"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Name of the instrumentation library
+const Name = "star-wars"
+
+// Tracer used by every span of the application
+func Tracer() trace.Tracer {
+	return otel.Tracer(Name)
+}
+
+// End records err on span and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Setup installs the global tracer provider and the W3C propagator;
+// the returned function flushes pending spans and must run on shutdown
+func Setup(ctx context.Context) (func(ctx context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	exporter, closeFile, err := newExporter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if exporter == nil {
+		return func(ctx context.Context) error { return nil }, nil
+	}
+
+	service := env.Vars.Tracing.Service
+	if service == "" {
+		service = Name
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", service))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+
+		if closeErr := closeFile(); err == nil {
+			err = closeErr
+		}
+
+		return err
+	}, nil
+}
+
+func newExporter(ctx context.Context) (sdktrace.SpanExporter, func() error, error) {
+	noop := func() error { return nil }
+
+	switch env.Vars.Tracing.Exporter {
+	case "", "none":
+		return nil, noop, nil
+	case "otlp":
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(env.Vars.Tracing.Endpoint)}
+		if env.Vars.Tracing.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+
+		exporter, err := otlptracehttp.New(ctx, opts...)
+		return exporter, noop, err
+	case "stdout":
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		return exporter, noop, err
+	case "file":
+		f, err := os.OpenFile(env.Vars.Tracing.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, noop, err
+		}
+
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(io.Writer(f)))
+		return exporter, f.Close, err
+	}
+
+	return nil, noop, fmt.Errorf("tracing exporter %q is invalid", env.Vars.Tracing.Exporter)
+}